@@ -8,20 +8,24 @@ import (
 	"os/signal"
 	"syscall"
 
+	"k8s-deployment-hpa-validator/internal/config"
 	"k8s-deployment-hpa-validator/internal/webhook"
 )
 
 func main() {
-	var (
-		port     = flag.Int("port", 8443, "Webhook server port")
-		certFile = flag.String("cert-file", "/etc/certs/tls.crt", "TLS certificate file")
-		keyFile  = flag.String("key-file", "/etc/certs/tls.key", "TLS private key file")
-	)
+	// 優先順位: デフォルト値 < 設定ファイル < 環境変数 < コマンドライン引数
+	flags := config.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
-	log.Printf("Starting HPA-Deployment validator webhook server on port %d", *port)
+	// 設定が不正な場合はデフォルト値で起動せずに終了する
+	cfg, err := flags.LoadConfig()
+	if err != nil {
+		log.Fatalf("Invalid webhook configuration: %v", err)
+	}
+
+	log.Printf("Starting HPA-Deployment validator webhook server on port %d (environment: %s)", cfg.Port, cfg.Environment)
 
-	server, err := webhook.NewServer(*port, *certFile, *keyFile)
+	server, err := webhook.NewServerFromConfig(cfg)
 	if err != nil {
 		log.Fatalf("Failed to create webhook server: %v", err)
 	}
@@ -42,4 +46,4 @@ func main() {
 	if err := server.Start(ctx); err != nil {
 		log.Fatalf("Failed to start webhook server: %v", err)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,78 @@
+// Package asyncqueue はAdmissionRequestの処理を待たせずに項目を積み、別のgoroutineで順に処理する上限付きのキューを提供する
+// 決定ログ・拒否の通知・CloudEventsのように、出力先の遅延や障害をリクエストの処理時間に影響させたくない処理で使用する
+package asyncqueue
+
+import "sync"
+
+// Queue 上限付きのキュー
+// 上限に達した場合や停止後に追加された項目は、待たずに破棄する
+type Queue[T any] struct {
+	items chan T
+	// dropped 上限に達したため項目を破棄したときに呼び出す（メトリクスの記録など）
+	dropped func()
+
+	mu      sync.RWMutex
+	closed  bool
+	started bool
+	done    chan struct{}
+}
+
+// New 上限がsizeのキューを作成
+func New[T any](size int, dropped func()) *Queue[T] {
+	return &Queue[T]{
+		items:   make(chan T, size),
+		dropped: dropped,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start 別のgoroutineでconsumeを実行する
+// consumeはキューを停止するまでitemsから項目を受け取り、itemsが閉じられたら残りを処理して戻る
+func (q *Queue[T]) Start(consume func(items <-chan T)) {
+	q.mu.Lock()
+	q.started = true
+	q.mu.Unlock()
+
+	go func() {
+		defer close(q.done)
+		consume(q.items)
+	}()
+}
+
+// Enqueue 項目をキューに追加（ブロックしない）
+// 追加できなかった場合はfalseを返す
+func (q *Queue[T]) Enqueue(item T) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return false
+	}
+	select {
+	case q.items <- item:
+		return true
+	default:
+		if q.dropped != nil {
+			q.dropped()
+		}
+		return false
+	}
+}
+
+// Close 項目の追加を止め、consumeが残りの項目を処理して戻るまで待つ
+// 既に停止していた場合はfalseを返す
+func (q *Queue[T]) Close() bool {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return false
+	}
+	q.closed = true
+	close(q.items)
+	started := q.started
+	q.mu.Unlock()
+
+	if started {
+		<-q.done
+	}
+	return true
+}
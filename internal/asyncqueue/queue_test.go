@@ -0,0 +1,60 @@
+package asyncqueue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueue_DropsWhenFull(t *testing.T) {
+	// 処理用のgoroutineを開始せず、キューが一杯の状態を再現する
+	dropped := 0
+	q := New[int](1, func() { dropped++ })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if !q.Enqueue(1) {
+			t.Error("Expected the first item to be queued")
+		}
+		if q.Enqueue(2) {
+			t.Error("Expected the second item to be dropped")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Enqueue() blocked on a full queue")
+	}
+
+	if dropped != 1 {
+		t.Errorf("Expected the drop callback to be called once, got %d", dropped)
+	}
+}
+
+func TestQueue_CloseFlushes(t *testing.T) {
+	q := New[int](16, nil)
+	var consumed []int
+	q.Start(func(items <-chan int) {
+		for item := range items {
+			consumed = append(consumed, item)
+		}
+	})
+
+	for i := 1; i <= 3; i++ {
+		q.Enqueue(i)
+	}
+	// Closeは残りの項目を処理し終えるまで待ち、以後に追加された項目は破棄する
+	if !q.Close() {
+		t.Error("Expected the first Close() to stop the queue")
+	}
+	if q.Enqueue(4) {
+		t.Error("Expected Enqueue() after Close() to be rejected")
+	}
+	if q.Close() {
+		t.Error("Expected the second Close() to report that the queue was already stopped")
+	}
+
+	if len(consumed) != 3 || consumed[0] != 1 || consumed[2] != 3 {
+		t.Errorf("consumed = %v, expected [1 2 3]", consumed)
+	}
+}
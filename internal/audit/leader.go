@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// LeaseName 結果を出力するレプリカを選出するLease
+	LeaseName = "k8s-deployment-hpa-validator-audit"
+
+	// leaseDuration, renewDeadline, retryPeriod Leaseの有効期間・更新の期限・取得の再試行の間隔（client-goの推奨値）
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// NewLeaseLock 結果を出力するレプリカを選出するLeaseのロックを作成
+// IDはPodのホスト名と、同じホスト名で再起動した場合に区別するためのUUIDとする
+func NewLeaseLock(client kubernetes.Interface, namespace string) (resourcelock.Interface, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hostname: %w", err)
+	}
+	return resourcelock.New(resourcelock.LeasesResourceLock, namespace, LeaseName,
+		client.CoreV1(), client.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: hostname + "_" + string(uuid.NewUUID())})
+}
+
+// SetLeaderElection 結果の出力をLeaseで選出された1つのレプリカに限定する（Runの前に呼び出す）
+// スキャンと違反の一覧・メトリクスは全てのレプリカで行い、PolicyReportの書き込みのみ選出されたレプリカで行う
+func (s *Scanner) SetLeaderElection(lock resourcelock.Interface) {
+	s.lock = lock
+	s.leaseDuration = leaseDuration
+	s.renewDeadline = renewDeadline
+	s.retryPeriod = retryPeriod
+}
+
+// IsLeader 結果を出力するレプリカかどうか（選出しない場合は常にtrue）
+func (s *Scanner) IsLeader() bool {
+	return s.lock == nil || s.leading.Load()
+}
+
+// runLeaderElection Leaseの取得を繰り返す（ctxが終了するまで）
+// Leaseを失った場合は結果の出力を止め、再度取得を試みる
+func (s *Scanner) runLeaderElection(ctx context.Context) {
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            s.lock,
+		LeaseDuration:   s.leaseDuration,
+		RenewDeadline:   s.renewDeadline,
+		RetryPeriod:     s.retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				// 他のレプリカが書き込んだ後の可能性があるため、以前に書き込んだ内容を破棄して読み込み直す
+				s.resetReporter.Store(true)
+				s.leading.Store(true)
+				s.logger.Info("監査スキャンの結果を出力するレプリカに選出されました", map[string]interface{}{
+					"identity": s.lock.Identity(),
+				})
+				s.requestScan()
+			},
+			OnStoppedLeading: func() {
+				s.leading.Store(false)
+				s.logger.Info("監査スキャンの結果の出力を停止しました", map[string]interface{}{
+					"identity": s.lock.Identity(),
+				})
+			},
+		},
+	})
+	if err != nil {
+		s.logger.Error("監査スキャンのリーダー選出を開始できません", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	for ctx.Err() == nil {
+		elector.Run(ctx)
+	}
+}
@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+// countingReporter 出力とResetの回数を記録するReporter
+type countingReporter struct {
+	mu      sync.Mutex
+	reports int
+	resets  int
+}
+
+func (r *countingReporter) Report(context.Context, []Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports++
+}
+
+func (r *countingReporter) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resets++
+}
+
+func (r *countingReporter) counts() (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reports, r.resets
+}
+
+// replica 同じクラスターで実行するレプリカの監査スキャナー
+type replica struct {
+	scanner  *Scanner
+	reporter *countingReporter
+	stop     context.CancelFunc
+	done     chan struct{}
+}
+
+// startReplica Leaseで選出された場合のみ出力するスキャナーを開始（Leaseの期間はテスト用に短くする）
+func startReplica(t *testing.T, client *fake.Clientset, identity string) *replica {
+	t.Helper()
+	cfg := &config.WebhookConfig{AuditInterval: 50 * time.Millisecond}
+	scanner := NewScanner(client, validator.NewDeploymentHPAValidator(client), cfg, logging.NewLogger("test-audit"))
+	reporter := &countingReporter{}
+	scanner.SetReporter(reporter)
+
+	lock, err := resourcelock.New(resourcelock.LeasesResourceLock, "webhook-system", LeaseName,
+		client.CoreV1(), client.CoordinationV1(), resourcelock.ResourceLockConfig{Identity: identity})
+	if err != nil {
+		t.Fatalf("Failed to create lock: %v", err)
+	}
+	scanner.SetLeaderElection(lock)
+	scanner.leaseDuration = time.Second
+	scanner.renewDeadline = 800 * time.Millisecond
+	scanner.retryPeriod = 100 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &replica{scanner: scanner, reporter: reporter, stop: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		scanner.Run(ctx)
+	}()
+	t.Cleanup(r.shutdown)
+	return r
+}
+
+func (r *replica) shutdown() {
+	r.stop()
+	<-r.done
+}
+
+func TestScanner_LeaderElection(t *testing.T) {
+	client := fake.NewSimpleClientset(newDeployment("web", "default", 1, nil), newHPA("web-hpa", "default", "web", 0, nil))
+	first := startReplica(t, client, "replica-a")
+	waitFor(t, first.scanner.IsLeader)
+	second := startReplica(t, client, "replica-b")
+	waitFor(t, func() bool { return !second.scanner.LastScan().IsZero() })
+
+	// 両方のレプリカがスキャンするが、出力するのはLeaseを保持するレプリカのみ
+	waitFor(t, func() bool {
+		reports, _ := first.reporter.counts()
+		return reports >= 2
+	})
+	if second.scanner.IsLeader() {
+		t.Fatal("Expected only one replica to hold the lease")
+	}
+	if reports, _ := second.reporter.counts(); reports != 0 {
+		t.Fatalf("Expected the standby replica not to report, got %d reports", reports)
+	}
+	if len(second.scanner.Violations()) != 2 {
+		t.Errorf("Expected the standby replica to still track violations, got %+v", second.scanner.Violations())
+	}
+
+	// Leaseを保持するレプリカが停止すると、他のレプリカが以前の状態を破棄して出力を引き継ぐ
+	first.shutdown()
+	waitFor(t, second.scanner.IsLeader)
+	waitFor(t, func() bool {
+		reports, _ := second.reporter.counts()
+		return reports >= 1
+	})
+	if _, resets := second.reporter.counts(); resets != 1 {
+		t.Errorf("Expected the new leader to reset the reporter once, got %d", resets)
+	}
+}
@@ -0,0 +1,262 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	"k8s-deployment-hpa-validator/internal/logging"
+)
+
+const (
+	// PolicyReportName namespaceごとに作成するPolicyReportの名前
+	PolicyReportName = "k8s-deployment-hpa-validator"
+	// PolicyName PolicyReportの結果に記録するポリシー名
+	PolicyName = "deployment-hpa-replicas"
+	// PolicySource PolicyReportの結果に記録するツール名
+	PolicySource = "k8s-deployment-hpa-validator"
+
+	// policyReportManagedByLabel このツールが作成したPolicyReportを識別するラベル
+	policyReportManagedByLabel = "app.kubernetes.io/managed-by"
+
+	// crdRecheckInterval CRDがない場合に再確認する間隔
+	crdRecheckInterval = 5 * time.Minute
+)
+
+// PolicyReportGVR Kubernetes Policy WGのPolicyReport（wgpolicyk8s.io/v1alpha2）
+var PolicyReportGVR = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "policyreports"}
+
+// PolicyReportWriter スキャンの結果をnamespaceごとのPolicyReportとして書き込むReporter
+// 結果が変わったnamespaceのみ更新し、結果がなくなったnamespaceのPolicyReportは削除する
+// Scannerのスキャン処理からのみ呼び出されるため、状態はロックせずに保持する
+type PolicyReportWriter struct {
+	client    dynamic.Interface
+	discovery discovery.DiscoveryInterface
+	logger    *logging.Logger
+
+	// available PolicyReportのCRDがインストールされているかどうか
+	available bool
+	lastCheck time.Time
+	// written namespaceごとに書き込んだ結果の内容（nilの場合は既存のPolicyReportを未確認）
+	written map[string]string
+}
+
+// NewPolicyReportWriter PolicyReportを書き込むReporterを作成
+func NewPolicyReportWriter(client dynamic.Interface, discovery discovery.DiscoveryInterface, logger *logging.Logger) *PolicyReportWriter {
+	return &PolicyReportWriter{
+		client:    client,
+		discovery: discovery,
+		logger:    logger,
+	}
+}
+
+// Report スキャンの結果をnamespaceごとにまとめてPolicyReportに書き込む
+func (w *PolicyReportWriter) Report(ctx context.Context, results []Result) {
+	if !w.crdAvailable() {
+		return
+	}
+	if w.written == nil && !w.loadWritten(ctx) {
+		return
+	}
+
+	byNamespace := map[string][]Result{}
+	for _, result := range results {
+		byNamespace[result.Namespace] = append(byNamespace[result.Namespace], result)
+	}
+
+	now := time.Now()
+	for namespace, nsResults := range byNamespace {
+		sortResults(nsResults)
+		content := resultsContent(nsResults)
+		if previous, ok := w.written[namespace]; ok && previous == content {
+			continue
+		}
+		if err := w.apply(ctx, newPolicyReport(namespace, nsResults, now)); err != nil {
+			w.writeFailed(namespace, err)
+			continue
+		}
+		w.written[namespace] = content
+	}
+
+	for namespace := range w.written {
+		if _, ok := byNamespace[namespace]; ok {
+			continue
+		}
+		err := w.client.Resource(PolicyReportGVR).Namespace(namespace).Delete(ctx, PolicyReportName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			w.writeFailed(namespace, err)
+			continue
+		}
+		delete(w.written, namespace)
+	}
+}
+
+// Reset 書き込んだ結果の内容を破棄し、次の書き込みで既存のPolicyReportを読み込み直す
+// 他のレプリカが書き込んだ可能性がある場合（リーダーに選出された時）に呼び出す
+func (w *PolicyReportWriter) Reset() {
+	w.written = nil
+}
+
+// crdAvailable PolicyReportのCRDがインストールされているかを確認（ない場合は一定間隔ごとに再確認）
+func (w *PolicyReportWriter) crdAvailable() bool {
+	if w.available {
+		return true
+	}
+	if !w.lastCheck.IsZero() && time.Since(w.lastCheck) < crdRecheckInterval {
+		return false
+	}
+	w.lastCheck = time.Now()
+
+	groupVersion := PolicyReportGVR.GroupVersion().String()
+	resources, err := w.discovery.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil && !apierrors.IsNotFound(err) {
+		w.logger.Warn("PolicyReportのCRDの確認に失敗しました", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return false
+	}
+	if resources != nil {
+		for _, resource := range resources.APIResources {
+			if resource.Name == PolicyReportGVR.Resource {
+				w.available = true
+				w.logger.Info("監査スキャンの結果をPolicyReportに書き込みます", map[string]interface{}{
+					"group_version": groupVersion,
+				})
+				return true
+			}
+		}
+	}
+	w.logger.Warn("PolicyReportのCRDがインストールされていないため、監査スキャンの結果を書き込みません", map[string]interface{}{
+		"group_version": groupVersion,
+		"recheck_after": crdRecheckInterval.String(),
+	})
+	return false
+}
+
+// loadWritten 以前に作成したPolicyReportを一覧し、結果がなくなったnamespaceの削除の対象にする
+func (w *PolicyReportWriter) loadWritten(ctx context.Context) bool {
+	list, err := w.client.Resource(PolicyReportGVR).List(ctx, metav1.ListOptions{
+		LabelSelector: policyReportManagedByLabel + "=" + PolicySource,
+	})
+	if err != nil {
+		w.writeFailed("", err)
+		return false
+	}
+	w.written = map[string]string{}
+	for _, item := range list.Items {
+		if item.GetName() == PolicyReportName {
+			// 内容は比較できないため、空にして次の書き込みで更新する
+			w.written[item.GetNamespace()] = ""
+		}
+	}
+	return true
+}
+
+// apply PolicyReportを作成、既に存在する場合は置き換える
+func (w *PolicyReportWriter) apply(ctx context.Context, report *unstructured.Unstructured) error {
+	client := w.client.Resource(PolicyReportGVR).Namespace(report.GetNamespace())
+	existing, err := client.Get(ctx, report.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Create(ctx, report, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	report.SetResourceVersion(existing.GetResourceVersion())
+	_, err = client.Update(ctx, report, metav1.UpdateOptions{})
+	return err
+}
+
+// writeFailed 書き込みの失敗を記録
+// NotFound（PolicyReportの既存の有無は確認済みのため、CRDが削除された場合）は、次のスキャンでCRDの有無から確認し直す
+func (w *PolicyReportWriter) writeFailed(namespace string, err error) {
+	w.logger.Warn("PolicyReportの書き込みに失敗しました", map[string]interface{}{
+		"namespace": namespace,
+		"error":     err.Error(),
+	})
+	if apierrors.IsNotFound(err) {
+		w.available = false
+		w.lastCheck = time.Time{}
+		w.written = nil
+	}
+}
+
+// sortResults PolicyReportの差分が出ないように結果を種類、名前、ルールの順に並べる
+func sortResults(results []Result) {
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Rule < b.Rule
+	})
+}
+
+// resultsContent 書き込みが必要かを判定するための結果の内容（時刻を含まない）
+func resultsContent(results []Result) string {
+	content, _ := json.Marshal(results)
+	return string(content)
+}
+
+// newPolicyReport namespaceの結果からPolicyReportを作成
+func newPolicyReport(namespace string, results []Result, timestamp time.Time) *unstructured.Unstructured {
+	summary := map[string]interface{}{
+		string(ResultPass):  int64(0),
+		string(ResultFail):  int64(0),
+		string(ResultWarn):  int64(0),
+		string(ResultError): int64(0),
+		string(ResultSkip):  int64(0),
+	}
+	entries := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		summary[string(result.Status)] = summary[string(result.Status)].(int64) + 1
+
+		entry := map[string]interface{}{
+			"policy": PolicyName,
+			"rule":   result.Rule,
+			"result": string(result.Status),
+			"source": PolicySource,
+			"scored": true,
+			"timestamp": map[string]interface{}{
+				"seconds": timestamp.Unix(),
+				"nanos":   int64(0),
+			},
+			"resources": []interface{}{
+				map[string]interface{}{
+					"apiVersion": result.APIVersion,
+					"kind":       result.Kind,
+					"name":       result.Name,
+					"namespace":  result.Namespace,
+					"uid":        string(result.UID),
+				},
+			},
+		}
+		if result.Message != "" {
+			entry["message"] = result.Message
+		}
+		entries = append(entries, entry)
+	}
+
+	report := &unstructured.Unstructured{Object: map[string]interface{}{
+		"summary": summary,
+		"results": entries,
+	}}
+	report.SetAPIVersion(PolicyReportGVR.GroupVersion().String())
+	report.SetKind("PolicyReport")
+	report.SetName(PolicyReportName)
+	report.SetNamespace(namespace)
+	report.SetLabels(map[string]string{policyReportManagedByLabel: PolicySource})
+	return report
+}
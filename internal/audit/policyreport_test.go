@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+// policyReportResources PolicyReportのCRDがインストールされたクラスターのdiscoveryの結果
+var policyReportResources = []*metav1.APIResourceList{{
+	GroupVersion: PolicyReportGVR.GroupVersion().String(),
+	APIResources: []metav1.APIResource{{Name: "policyreports", Namespaced: true, Kind: "PolicyReport"}},
+}}
+
+// newTestPolicyReportWriter fakeのdynamic clientとdiscoveryを使用するPolicyReportWriter
+func newTestPolicyReportWriter(resources []*metav1.APIResourceList, objects ...runtime.Object) (*PolicyReportWriter, *dynamicfake.FakeDynamicClient, *fakediscovery.FakeDiscovery) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{PolicyReportGVR: "PolicyReportList"}, objects...)
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: resources}}
+	return NewPolicyReportWriter(client, discovery, logging.NewLogger("test-audit")), client, discovery
+}
+
+// writeActions PolicyReportを変更したアクションの数
+func writeActions(client *dynamicfake.FakeDynamicClient) int {
+	count := 0
+	for _, action := range client.Actions() {
+		switch action.GetVerb() {
+		case "create", "update", "delete":
+			count++
+		}
+	}
+	return count
+}
+
+// getPolicyReport namespaceのPolicyReportを取得（存在しない場合はnil）
+func getPolicyReport(t *testing.T, client *dynamicfake.FakeDynamicClient, namespace string) *unstructured.Unstructured {
+	t.Helper()
+	report, err := client.Resource(PolicyReportGVR).Namespace(namespace).Get(context.Background(), PolicyReportName, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return report
+}
+
+func TestPolicyReportWriter_Report(t *testing.T) {
+	// 以前の起動で作成し、結果がなくなったnamespaceのPolicyReport
+	stale := newPolicyReport("removed", nil, time.Now())
+	writer, client, _ := newTestPolicyReportWriter(policyReportResources, stale)
+
+	results := []Result{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "default", UID: "web-uid", Rule: validator.CodeDeploymentHPAConflict, Status: ResultFail, Message: "conflict"},
+		{APIVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscaler", Name: "web-hpa", Namespace: "default", Rule: validator.CodeHPASingleReplica, Status: ResultFail, Message: "single replica"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "api", Namespace: "team-a", Rule: validator.CodeDeploymentHPAConflict, Status: ResultPass},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "batch", Namespace: "team-a", Rule: validator.CodeDeploymentHPAConflict, Status: ResultWarn, Message: "conflict"},
+		{APIVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscaler", Name: "batch-hpa", Namespace: "team-a", Rule: validator.CodeHPASingleReplica, Status: ResultSkip},
+	}
+	writer.Report(context.Background(), results)
+
+	report := getPolicyReport(t, client, "default")
+	if report == nil {
+		t.Fatal("Expected a PolicyReport in default")
+	}
+	if report.GetLabels()[policyReportManagedByLabel] != PolicySource {
+		t.Errorf("Labels = %v", report.GetLabels())
+	}
+	summary, _, _ := unstructured.NestedMap(report.Object, "summary")
+	if summary["fail"] != int64(2) || summary["pass"] != int64(0) {
+		t.Errorf("summary = %v, expected fail=2", summary)
+	}
+	entries, _, _ := unstructured.NestedSlice(report.Object, "results")
+	if len(entries) != 2 {
+		t.Fatalf("results = %v, expected 2 entries", entries)
+	}
+	entry := entries[0].(map[string]interface{})
+	resources := entry["resources"].([]interface{})
+	resource := resources[0].(map[string]interface{})
+	if entry["rule"] != validator.CodeDeploymentHPAConflict || entry["result"] != "fail" || entry["policy"] != PolicyName ||
+		entry["message"] != "conflict" || resource["name"] != "web" || resource["uid"] != "web-uid" {
+		t.Errorf("results[0] = %v", entry)
+	}
+
+	teamA := getPolicyReport(t, client, "team-a")
+	if teamA == nil {
+		t.Fatal("Expected a PolicyReport in team-a")
+	}
+	summary, _, _ = unstructured.NestedMap(teamA.Object, "summary")
+	if summary["pass"] != int64(1) || summary["warn"] != int64(1) || summary["skip"] != int64(1) || summary["fail"] != int64(0) {
+		t.Errorf("team-a summary = %v, expected pass=1 warn=1 skip=1", summary)
+	}
+	if getPolicyReport(t, client, "removed") != nil {
+		t.Error("Expected the stale PolicyReport to be deleted")
+	}
+
+	// 結果が変わらない場合は書き込まない
+	client.ClearActions()
+	writer.Report(context.Background(), results)
+	if count := writeActions(client); count != 0 {
+		t.Errorf("Expected no writes for unchanged results, got %d: %v", count, client.Actions())
+	}
+
+	// 違反が解消されたnamespaceのみ更新し、結果がなくなったnamespaceは削除する
+	client.ClearActions()
+	fixed := []Result{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "default", UID: "web-uid", Rule: validator.CodeDeploymentHPAConflict, Status: ResultPass},
+		{APIVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscaler", Name: "web-hpa", Namespace: "default", Rule: validator.CodeHPASingleReplica, Status: ResultPass},
+	}
+	writer.Report(context.Background(), fixed)
+	if count := writeActions(client); count != 2 {
+		t.Errorf("Expected 1 update and 1 delete, got %d: %v", count, client.Actions())
+	}
+	summary, _, _ = unstructured.NestedMap(getPolicyReport(t, client, "default").Object, "summary")
+	if summary["pass"] != int64(2) || summary["fail"] != int64(0) {
+		t.Errorf("default summary = %v, expected pass=2", summary)
+	}
+	if getPolicyReport(t, client, "team-a") != nil {
+		t.Error("Expected the team-a PolicyReport to be deleted")
+	}
+}
+
+func TestPolicyReportWriter_CRDMissing(t *testing.T) {
+	writer, client, discovery := newTestPolicyReportWriter(nil)
+	results := []Result{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "default", Rule: validator.CodeDeploymentHPAConflict, Status: ResultFail},
+	}
+
+	writer.Report(context.Background(), results)
+	if len(client.Actions()) != 0 {
+		t.Fatalf("Expected no API calls without the CRD, got %v", client.Actions())
+	}
+
+	// CRDをインストールしても、再確認の間隔が経過するまでは確認しない
+	discovery.Resources = policyReportResources
+	writer.Report(context.Background(), results)
+	if len(client.Actions()) != 0 {
+		t.Fatalf("Expected the CRD check to be rate limited, got %v", client.Actions())
+	}
+
+	writer.lastCheck = time.Now().Add(-crdRecheckInterval)
+	writer.Report(context.Background(), results)
+	if getPolicyReport(t, client, "default") == nil {
+		t.Error("Expected a PolicyReport after the CRD was installed")
+	}
+}
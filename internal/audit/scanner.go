@@ -0,0 +1,500 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	autoscalinglisters "k8s.io/client-go/listers/autoscaling/v2"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+const (
+	// DefaultInterval 定期的に全体をスキャンする間隔
+	DefaultInterval = 10 * time.Minute
+
+	// scanDebounce informerのイベントを受けてからスキャンするまでの待ち時間（連続した変更をまとめる）
+	scanDebounce = time.Second
+)
+
+// ResultStatus リソースにルールを適用した結果（PolicyReportのresultと同じ値）
+type ResultStatus string
+
+const (
+	// ResultPass 違反なし
+	ResultPass ResultStatus = "pass"
+	// ResultFail 違反あり
+	ResultFail ResultStatus = "fail"
+	// ResultWarn 対象外のラベルが付いているが違反あり（webhookでは拒否されない）
+	ResultWarn ResultStatus = "warn"
+	// ResultSkip 対象外のラベルが付いており違反なし
+	ResultSkip ResultStatus = "skip"
+	// ResultError 検証に失敗
+	ResultError ResultStatus = "error"
+)
+
+// resourceRules リソースの種類ごとに適用するルール（エラーコード）
+var resourceRules = map[string]string{
+	"Deployment":              validator.CodeDeploymentHPAConflict,
+	"HorizontalPodAutoscaler": validator.CodeHPASingleReplica,
+}
+
+// resourceAPIVersions リソースの種類ごとのAPIバージョン
+var resourceAPIVersions = map[string]string{
+	"Deployment":              "apps/v1",
+	"HorizontalPodAutoscaler": "autoscaling/v2",
+}
+
+// Result スキャンしたリソースごとのルールの適用結果
+type Result struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+	UID        types.UID
+	// Rule 適用したルール（エラーコード）
+	Rule    string
+	Status  ResultStatus
+	Message string
+}
+
+// Reporter スキャンの結果を外部に出力する（スキャンごとに全件を渡す）
+type Reporter interface {
+	Report(ctx context.Context, results []Result)
+}
+
+// resettableReporter 以前の出力の状態を保持するReporter（他のレプリカが出力した後に破棄する）
+type resettableReporter interface {
+	Reset()
+}
+
+// Violation 既存のリソースで検出したルール違反
+type Violation struct {
+	Kind          string                     `json:"kind"`
+	Name          string                     `json:"name"`
+	Namespace     string                     `json:"namespace"`
+	Rule          string                     `json:"rule"`
+	Message       string                     `json:"message"`
+	RelatedObject *validator.ObjectReference `json:"related_object,omitempty"`
+	Fixes         []validator.SuggestedFix   `json:"fixes,omitempty"`
+	// FirstSeen 違反を最初に検出した時刻（解消されるまで保持）
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+// violationKey 違反を識別するキー（リソースとルール）
+type violationKey struct {
+	kind, namespace, name, rule string
+}
+
+// gaugeKey 違反の数のメトリクスのラベル
+type gaugeKey struct {
+	namespace, rule string
+}
+
+// Scanner webhookの導入前から存在するDeploymentとHPAのルール違反を定期的に検出する
+// リソースはinformerのキャッシュから取得し、変更時にも再スキャンする
+type Scanner struct {
+	factory     informers.SharedInformerFactory
+	deployments appslisters.DeploymentLister
+	hpas        autoscalinglisters.HorizontalPodAutoscalerLister
+	validator   *validator.DeploymentHPAValidator
+	config      *config.WebhookConfig
+	logger      *logging.Logger
+	interval    time.Duration
+	trigger     chan struct{}
+	reporter    Reporter
+
+	// lock 結果を出力するレプリカを選出するLease（nilの場合は選出せずに出力する）
+	lock          resourcelock.Interface
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+	// leading Leaseを保持しているかどうか
+	leading atomic.Bool
+	// resetReporter 次の出力の前にReporterが保持する以前の出力の状態を破棄する
+	resetReporter atomic.Bool
+
+	mu         sync.RWMutex
+	violations map[violationKey]Violation
+	gauges     map[gaugeKey]int
+	lastScan   time.Time
+}
+
+// NewScanner 監査スキャナーを作成（validatorのルールをinformerのキャッシュに対して実行する）
+func NewScanner(client kubernetes.Interface, v *validator.DeploymentHPAValidator, cfg *config.WebhookConfig, logger *logging.Logger) *Scanner {
+	factory := informers.NewSharedInformerFactory(client, 0)
+	deployments := factory.Apps().V1().Deployments()
+	hpas := factory.Autoscaling().V2().HorizontalPodAutoscalers()
+
+	interval := cfg.AuditInterval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	s := &Scanner{
+		factory:     factory,
+		deployments: deployments.Lister(),
+		hpas:        hpas.Lister(),
+		config:      cfg,
+		logger:      logger,
+		interval:    interval,
+		trigger:     make(chan struct{}, 1),
+		violations:  map[violationKey]Violation{},
+		gauges:      map[gaugeKey]int{},
+	}
+	s.validator = v.WithLookup(listerLookup{deployments: s.deployments, hpas: s.hpas})
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { s.requestScan() },
+		UpdateFunc: s.onUpdate,
+		DeleteFunc: func(interface{}) { s.requestScan() },
+	}
+	deployments.Informer().AddEventHandler(handler)
+	hpas.Informer().AddEventHandler(handler)
+	return s
+}
+
+// SetReporter スキャンの結果の出力先を設定（Runの前に呼び出す）
+func (s *Scanner) SetReporter(reporter Reporter) {
+	s.reporter = reporter
+}
+
+// Run informerを開始し、キャッシュの同期後にスキャンを繰り返す（ctxが終了するまで）
+func (s *Scanner) Run(ctx context.Context) {
+	s.factory.Start(ctx.Done())
+	defer s.factory.Shutdown()
+
+	for informerType, synced := range s.factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			s.logger.Error("監査スキャンのキャッシュの同期に失敗しました", map[string]interface{}{
+				"informer": informerType.String(),
+			})
+			return
+		}
+	}
+	s.logger.Info("監査スキャンを開始しました", map[string]interface{}{
+		"interval": s.interval.String(),
+	})
+	if s.lock != nil {
+		go s.runLeaderElection(ctx)
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.Scan(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.trigger:
+			// 連続した変更を1回のスキャンにまとめる
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(scanDebounce):
+			}
+			select {
+			case <-s.trigger:
+			default:
+			}
+		}
+	}
+}
+
+// requestScan 次のスキャンを要求（実行待ちの要求がある場合はまとめる）
+func (s *Scanner) requestScan() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// onUpdate specかラベルが変わった場合のみスキャンを要求（statusの更新は違反に影響しないため無視する）
+func (s *Scanner) onUpdate(oldObj, newObj interface{}) {
+	oldMeta, oldOK := oldObj.(metav1.Object)
+	newMeta, newOK := newObj.(metav1.Object)
+	if oldOK && newOK && oldMeta.GetGeneration() == newMeta.GetGeneration() &&
+		labels.Equals(oldMeta.GetLabels(), newMeta.GetLabels()) {
+		return
+	}
+	s.requestScan()
+}
+
+// Scan キャッシュ内のすべてのDeploymentとHPAを検証し、違反の一覧とメトリクスを更新
+func (s *Scanner) Scan(ctx context.Context) {
+	start := time.Now()
+
+	deployments, err := s.deployments.List(labels.Everything())
+	if err != nil {
+		s.scanFailed(err)
+		return
+	}
+	hpas, err := s.hpas.List(labels.Everything())
+	if err != nil {
+		s.scanFailed(err)
+		return
+	}
+
+	found := map[violationKey]Violation{}
+	var results []Result
+	for _, deployment := range deployments {
+		if s.config.ShouldSkipNamespace(deployment.Namespace) {
+			continue
+		}
+		results = append(results, s.evaluate(found, "Deployment", deployment.ObjectMeta, s.validator.ValidateDeployment(ctx, deployment)))
+	}
+	for _, hpa := range hpas {
+		if s.config.ShouldSkipNamespace(hpa.Namespace) {
+			continue
+		}
+		results = append(results, s.evaluate(found, "HorizontalPodAutoscaler", hpa.ObjectMeta, s.validator.ValidateHPA(ctx, hpa)))
+	}
+
+	s.update(found)
+	metrics.RecordAuditScan(true)
+	s.logger.Debug("監査スキャンが完了しました", map[string]interface{}{
+		"deployments": len(deployments),
+		"hpas":        len(hpas),
+		"violations":  len(found),
+		"duration":    time.Since(start).String(),
+	})
+
+	if s.reporter != nil && s.IsLeader() {
+		if reset, ok := s.reporter.(resettableReporter); ok && s.resetReporter.Swap(false) {
+			reset.Reset()
+		}
+		s.reporter.Report(ctx, results)
+	}
+}
+
+// scanFailed スキャンの失敗を記録（前回の結果は保持する）
+func (s *Scanner) scanFailed(err error) {
+	metrics.RecordAuditScan(false)
+	s.logger.Warn("監査スキャンに失敗しました", map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+// evaluate 検証結果からリソースの結果を作成し、ルール違反の場合は一覧に追加
+// webhookと同様に対象外のラベルのリソースは違反として扱わず、違反がある場合はwarnとする
+func (s *Scanner) evaluate(found map[violationKey]Violation, kind string, meta metav1.ObjectMeta, err error) Result {
+	result := Result{
+		APIVersion: resourceAPIVersions[kind],
+		Kind:       kind,
+		Name:       meta.Name,
+		Namespace:  meta.Namespace,
+		UID:        meta.UID,
+		Rule:       resourceRules[kind],
+		Status:     ResultPass,
+	}
+	skipped := s.config.ShouldSkipByLabel(meta.Labels)
+	if err == nil {
+		if skipped {
+			result.Status = ResultSkip
+		}
+		return result
+	}
+
+	webhookErr, ok := err.(*validator.WebhookError)
+	if !ok || webhookErr.Type != validator.ErrorTypeValidation {
+		s.logger.Warn("監査スキャンでリソースを検証できませんでした", map[string]interface{}{
+			"resource_type": kind,
+			"resource_name": meta.Name,
+			"namespace":     meta.Namespace,
+			"error":         err.Error(),
+		})
+		result.Status = ResultError
+		result.Message = err.Error()
+		return result
+	}
+
+	result.Rule = webhookErr.Code
+	result.Message = webhookErr.Message
+	if skipped {
+		result.Status = ResultWarn
+		return result
+	}
+	result.Status = ResultFail
+
+	key := violationKey{kind: kind, namespace: meta.Namespace, name: meta.Name, rule: webhookErr.Code}
+	found[key] = Violation{
+		Kind:          kind,
+		Name:          meta.Name,
+		Namespace:     meta.Namespace,
+		Rule:          webhookErr.Code,
+		Message:       webhookErr.Message,
+		RelatedObject: webhookErr.RelatedObject,
+		Fixes:         webhookErr.Fixes,
+	}
+	return result
+}
+
+// update 違反の一覧を置き換え、新たに検出・解消した違反を記録してメトリクスを更新
+func (s *Scanner) update(found map[violationKey]Violation) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, violation := range found {
+		if previous, ok := s.violations[key]; ok {
+			violation.FirstSeen = previous.FirstSeen
+		} else {
+			violation.FirstSeen = now
+			s.logger.Warn("既存のリソースのルール違反を検出しました", map[string]interface{}{
+				"resource_type": violation.Kind,
+				"resource_name": violation.Name,
+				"namespace":     violation.Namespace,
+				"error_code":    violation.Rule,
+			})
+		}
+		found[key] = violation
+	}
+	for key, violation := range s.violations {
+		if _, ok := found[key]; !ok {
+			s.logger.Info("既存のリソースのルール違反が解消されました", map[string]interface{}{
+				"resource_type": violation.Kind,
+				"resource_name": violation.Name,
+				"namespace":     violation.Namespace,
+				"error_code":    violation.Rule,
+			})
+		}
+	}
+
+	gauges := map[gaugeKey]int{}
+	for key := range found {
+		gauges[gaugeKey{namespace: key.namespace, rule: key.rule}]++
+	}
+	for key, count := range gauges {
+		metrics.SetAuditViolations(key.namespace, key.rule, count)
+	}
+	// 違反がなくなった系列は0ではなく削除し、namespaceの増減で系列が残り続けないようにする
+	for key := range s.gauges {
+		if _, ok := gauges[key]; !ok {
+			metrics.DeleteAuditViolations(key.namespace, key.rule)
+		}
+	}
+
+	s.violations = found
+	s.gauges = gauges
+	s.lastScan = now
+}
+
+// Violations 最後のスキャンで検出した違反の一覧（namespace、種類、名前、ルールの順）
+func (s *Scanner) Violations() []Violation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	violations := make([]Violation, 0, len(s.violations))
+	for _, violation := range s.violations {
+		violations = append(violations, violation)
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Rule < b.Rule
+	})
+	return violations
+}
+
+// LastScan 最後にスキャンが完了した時刻（未実行の場合はゼロ値）
+func (s *Scanner) LastScan() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastScan
+}
+
+// violationsResponse /violationsのレスポンス
+type violationsResponse struct {
+	LastScan   time.Time   `json:"last_scan"`
+	Count      int         `json:"count"`
+	Violations []Violation `json:"violations"`
+}
+
+// ServeHTTP 違反の一覧をJSONで返す（namespace、ruleのクエリパラメータで絞り込み）
+// 最初のスキャンが完了するまでは503を返す
+func (s *Scanner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	lastScan := s.LastScan()
+	if lastScan.IsZero() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "error",
+			"message": "監査スキャンが完了していません",
+		})
+		return
+	}
+
+	namespace, rule := r.URL.Query().Get("namespace"), r.URL.Query().Get("rule")
+	violations := []Violation{}
+	for _, violation := range s.Violations() {
+		if (namespace == "" || violation.Namespace == namespace) && (rule == "" || violation.Rule == rule) {
+			violations = append(violations, violation)
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(violationsResponse{
+		LastScan:   lastScan,
+		Count:      len(violations),
+		Violations: violations,
+	})
+}
+
+// listerLookup informerのキャッシュから関係するリソースを取得するResourceLookup
+type listerLookup struct {
+	deployments appslisters.DeploymentLister
+	hpas        autoscalinglisters.HorizontalPodAutoscalerLister
+}
+
+// ListHPAs namespace内のHPAをキャッシュから取得
+func (l listerLookup) ListHPAs(ctx context.Context, namespace string) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpas, err := l.hpas.HorizontalPodAutoscalers(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	items := make([]autoscalingv2.HorizontalPodAutoscaler, 0, len(hpas))
+	for _, hpa := range hpas {
+		items = append(items, *hpa)
+	}
+	return items, nil
+}
+
+// GetDeployment Deploymentをキャッシュから取得
+func (l listerLookup) GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	return l.deployments.Deployments(namespace).Get(name)
+}
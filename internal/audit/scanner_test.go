@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+func init() {
+	// テストモードを有効にしてメトリクスの重複登録を防ぐ
+	metrics.EnableTestMode()
+}
+
+func int32Ptr(i int32) *int32 { return &i }
+
+// newDeployment 指定したreplica数のDeployment
+func newDeployment(name, namespace string, replicas int32, labels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels, Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(replicas)},
+	}
+}
+
+// newHPA 指定したDeploymentを対象とするHPA（minReplicasが0の場合は未指定）
+func newHPA(name, namespace, target string, minReplicas int32, labels map[string]string) *autoscalingv2.HorizontalPodAutoscaler {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels, Generation: 1},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: target},
+		},
+	}
+	if minReplicas > 0 {
+		hpa.Spec.MinReplicas = int32Ptr(minReplicas)
+	}
+	return hpa
+}
+
+// startScanner スキャナーを開始し、最初のスキャンの完了を待つ
+func startScanner(t *testing.T, client *fake.Clientset) *Scanner {
+	t.Helper()
+	cfg := &config.WebhookConfig{
+		SkipNamespaces: []string{"kube-system"},
+		SkipLabels:     []string{"k8s-deployment-hpa-validator.io/skip-validation=true"},
+		AuditInterval:  time.Hour,
+	}
+	scanner := NewScanner(client, validator.NewDeploymentHPAValidator(client), cfg, logging.NewLogger("test-audit"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	waitFor(t, func() bool { return !scanner.LastScan().IsZero() })
+	return scanner
+}
+
+// waitFor 条件を満たすまで待つ
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		return condition(), nil
+	})
+	if err != nil {
+		t.Fatalf("Timed out waiting for condition: %v", err)
+	}
+}
+
+// violationNames 違反のリソースとルールの一覧
+func violationNames(violations []Violation) []string {
+	names := []string{}
+	for _, violation := range violations {
+		names = append(names, violation.Namespace+"/"+violation.Kind+"/"+violation.Name+":"+violation.Rule)
+	}
+	return names
+}
+
+func TestScanner_Run(t *testing.T) {
+	skipLabels := map[string]string{"k8s-deployment-hpa-validator.io/skip-validation": "true"}
+	client := fake.NewSimpleClientset(
+		// 違反: replicas=1のDeploymentとminReplicas未指定のHPA
+		newDeployment("web", "default", 1, nil),
+		newHPA("web-hpa", "default", "web", 0, nil),
+		// 違反なし
+		newDeployment("api", "team-a", 3, nil),
+		newHPA("api-hpa", "team-a", "api", 2, nil),
+		// 対象外のnamespaceとラベル
+		newDeployment("dns", "kube-system", 1, nil),
+		newHPA("dns-hpa", "kube-system", "dns", 0, nil),
+		newDeployment("batch", "team-b", 1, skipLabels),
+		newHPA("batch-hpa", "team-b", "batch", 0, skipLabels),
+	)
+	// 他のテストのスキャナーが設定した系列を削除
+	metrics.WebhookAuditViolations.Reset()
+	scanner := startScanner(t, client)
+
+	expected := []string{
+		"default/Deployment/web:" + validator.CodeDeploymentHPAConflict,
+		"default/HorizontalPodAutoscaler/web-hpa:" + validator.CodeHPASingleReplica,
+	}
+	violations := scanner.Violations()
+	if names := violationNames(violations); len(names) != len(expected) || names[0] != expected[0] || names[1] != expected[1] {
+		t.Fatalf("Violations() = %v, expected %v", names, expected)
+	}
+	if related := violations[0].RelatedObject; related == nil || related.Name != "web-hpa" {
+		t.Errorf("RelatedObject = %+v, expected web-hpa", related)
+	}
+	if violations[0].FirstSeen.IsZero() || len(violations[0].Fixes) == 0 {
+		t.Errorf("Expected FirstSeen and Fixes to be set: %+v", violations[0])
+	}
+	if got := testutil.ToFloat64(metrics.WebhookAuditViolations.WithLabelValues("default", validator.CodeDeploymentHPAConflict)); got != 1 {
+		t.Errorf("webhook_audit_violations{default,%s} = %v, expected 1", validator.CodeDeploymentHPAConflict, got)
+	}
+
+	// Deploymentを修正すると、informerのイベントで再スキャンして違反とメトリクスの系列を削除する
+	fixed := newDeployment("web", "default", 2, nil)
+	fixed.Generation = 2
+	if _, err := client.AppsV1().Deployments("default").Update(context.Background(), fixed, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update deployment: %v", err)
+	}
+	waitFor(t, func() bool { return len(scanner.Violations()) == 0 })
+	if count := testutil.CollectAndCount(metrics.WebhookAuditViolations); count != 0 {
+		t.Errorf("webhook_audit_violations series = %d, expected 0", count)
+	}
+}
+
+func TestScanner_ServeHTTP(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newDeployment("web", "default", 1, nil),
+		newHPA("web-hpa", "default", "web", 0, nil),
+		newDeployment("api", "team-a", 1, nil),
+		newHPA("api-hpa", "team-a", "api", 0, nil),
+	)
+	scanner := startScanner(t, client)
+
+	tests := []struct {
+		query         string
+		expectedCount int
+	}{
+		{query: "", expectedCount: 4},
+		{query: "?namespace=team-a", expectedCount: 2},
+		{query: "?namespace=team-a&rule=" + validator.CodeHPASingleReplica, expectedCount: 1},
+		{query: "?namespace=missing", expectedCount: 0},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		scanner.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/violations"+tt.query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET /violations%s status = %d", tt.query, w.Code)
+		}
+		var response violationsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if response.Count != tt.expectedCount || len(response.Violations) != tt.expectedCount || response.LastScan.IsZero() {
+			t.Errorf("GET /violations%s = %+v, expected %d violations", tt.query, response, tt.expectedCount)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	scanner.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/violations", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /violations status = %d, expected %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
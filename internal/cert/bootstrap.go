@@ -0,0 +1,461 @@
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultCAValidity 自己署名CAの有効期間
+	DefaultCAValidity = 10 * 365 * 24 * time.Hour
+
+	// DefaultServingCertValidity サーバー証明書の有効期間
+	DefaultServingCertValidity = 365 * 24 * time.Hour
+
+	// DefaultRotateBefore 有効期限のこの期間前に証明書を更新する
+	DefaultRotateBefore = 30 * 24 * time.Hour
+
+	// DefaultBootstrapCheckInterval 証明書の有効期限を確認する間隔
+	DefaultBootstrapCheckInterval = time.Hour
+
+	// caSecretSuffix CAを保存するSecretの名前の接尾辞（<サーバー証明書のSecret名>-ca）
+	caSecretSuffix = "-ca"
+
+	// secretPreviousCAKey CAの更新後もcaBundleに含める以前のCA証明書のキー
+	secretPreviousCAKey = "previous-ca.crt"
+
+	// reconcileAttempts 他のレプリカと同時に発行・更新して競合した場合に、読み込み直して再試行する回数
+	reconcileAttempts = 5
+)
+
+// BootstrapConfig 自己署名CAによる証明書の自動発行の設定
+type BootstrapConfig struct {
+	// Namespace Service・Secretのnamespace
+	Namespace string
+	// ServiceName webhookのService名（サーバー証明書のDNS名に使用）
+	ServiceName string
+	// SecretName サーバー証明書を保存するSecret（tls.crt、tls.key、ca.crt）
+	SecretName string
+	// ValidatingWebhookName caBundleを設定するValidatingWebhookConfigurationの名前
+	ValidatingWebhookName string
+	// MutatingWebhookName caBundleを設定するMutatingWebhookConfigurationの名前（空の場合は設定しない）
+	MutatingWebhookName string
+
+	// CAValidity, ServingCertValidity 証明書の有効期間（0の場合は既定値）
+	CAValidity          time.Duration
+	ServingCertValidity time.Duration
+	// RotateBefore 有効期限のこの期間前に更新する（0の場合は既定値）
+	RotateBefore time.Duration
+}
+
+// Bootstrapper 自己署名CAとサーバー証明書を発行してSecretに保存し、webhook設定のcaBundleを更新する
+type Bootstrapper struct {
+	client kubernetes.Interface
+	config BootstrapConfig
+	// now 現在時刻（テスト用に差し替え可能）
+	now func() time.Time
+}
+
+// NewBootstrapper creates a new certificate bootstrapper
+func NewBootstrapper(client kubernetes.Interface, config BootstrapConfig) *Bootstrapper {
+	if config.CAValidity <= 0 {
+		config.CAValidity = DefaultCAValidity
+	}
+	if config.ServingCertValidity <= 0 {
+		config.ServingCertValidity = DefaultServingCertValidity
+	}
+	if config.RotateBefore <= 0 {
+		config.RotateBefore = DefaultRotateBefore
+	}
+	return &Bootstrapper{
+		client: client,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// DNSNames サーバー証明書に含めるServiceのDNS名
+func (b *Bootstrapper) DNSNames() []string {
+	svc, ns := b.config.ServiceName, b.config.Namespace
+	return []string{
+		svc,
+		fmt.Sprintf("%s.%s", svc, ns),
+		fmt.Sprintf("%s.%s.svc", svc, ns),
+		fmt.Sprintf("%s.%s.svc.cluster.local", svc, ns),
+	}
+}
+
+// CASecretName CAを保存するSecretの名前
+func (b *Bootstrapper) CASecretName() string {
+	return b.config.SecretName + caSecretSuffix
+}
+
+// Run 証明書を定期的に確認し、有効期限が近い場合は更新する（ctxが終了するまで）
+func (b *Bootstrapper) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.Reconcile(ctx); err != nil {
+				log.Printf("証明書の自動発行に失敗しました: %v", err)
+				certificateMonitoringErrors.WithLabelValues(b.source(), "bootstrap_failed").Inc()
+			}
+		}
+	}
+}
+
+// Reconcile CAとサーバー証明書が有効であることを確認し、必要に応じて発行・更新してcaBundleを設定する
+// 複数のレプリカが同時に発行・更新した場合（AlreadyExists・Conflict）は、保存されたCAと証明書を読み込み直して再試行する
+func (b *Bootstrapper) Reconcile(ctx context.Context) error {
+	var err error
+	for attempt := 1; attempt <= reconcileAttempts; attempt++ {
+		if err = b.reconcile(ctx); !isConcurrentUpdate(err) {
+			return err
+		}
+		log.Printf("他のレプリカによる証明書の更新と競合したため、読み込み直します（%d/%d）: %v", attempt, reconcileAttempts, err)
+	}
+	return err
+}
+
+// reconcile CAの確認、caBundleの設定、サーバー証明書の確認を1回行う
+// caBundleはサーバー証明書より先に設定する（CAの更新時に、新しいCAで署名したサーバー証明書を
+// APIサーバーが検証できない期間をなくすため。caBundleには以前のCAも含まれる）
+func (b *Bootstrapper) reconcile(ctx context.Context) error {
+	ca, caBundle, err := b.ensureCA(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.injectCABundle(ctx, caBundle); err != nil {
+		return err
+	}
+	return b.ensureServingCert(ctx, ca, caBundle)
+}
+
+// isConcurrentUpdate 他のレプリカが先にSecretやwebhook設定を作成・更新したことによるエラーか
+func isConcurrentUpdate(err error) bool {
+	return apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err)
+}
+
+// bootstrapCA 署名に使用するCA
+type bootstrapCA struct {
+	cert    *x509.Certificate
+	key     crypto.Signer
+	certPEM []byte
+}
+
+// ensureCA CAを読み込み、存在しないか有効期限が近い場合は新しく発行する
+// 返すcaBundleには、CAの更新直後は以前のCAも含める（以前のCAで署名された証明書の検証用）
+func (b *Bootstrapper) ensureCA(ctx context.Context) (*bootstrapCA, []byte, error) {
+	secrets := b.client.CoreV1().Secrets(b.config.Namespace)
+	secret, err := secrets.Get(ctx, b.CASecretName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("CAのSecretの取得に失敗しました: %w", err)
+	}
+
+	var previous []byte
+	if err == nil {
+		ca, parseErr := parseBootstrapCA(secret.Data[SecretCertKey], secret.Data[SecretKeyKey])
+		if parseErr == nil && b.now().Add(b.config.RotateBefore).Before(ca.cert.NotAfter) {
+			return ca, b.caBundle(ca.certPEM, secret.Data[secretPreviousCAKey]), nil
+		}
+		if parseErr != nil {
+			log.Printf("CAのSecretが不正なため、新しいCAを発行します: %v", parseErr)
+		} else {
+			log.Printf("CAの有効期限が近いため、新しいCAを発行します（有効期限: %s）", ca.cert.NotAfter.Format("2006-01-02"))
+			previous = ca.certPEM
+		}
+	}
+
+	ca, keyPEM, err := b.generateCA()
+	if err != nil {
+		return nil, nil, err
+	}
+	data := map[string][]byte{SecretCertKey: ca.certPEM, SecretKeyKey: keyPEM}
+	if len(previous) > 0 {
+		data[secretPreviousCAKey] = previous
+	}
+	if err := b.saveSecret(ctx, b.CASecretName(), corev1.SecretTypeTLS, data); err != nil {
+		return nil, nil, fmt.Errorf("CAの保存に失敗しました: %w", err)
+	}
+	log.Printf("自己署名CAを発行しました: %s/%s", b.config.Namespace, b.CASecretName())
+
+	return ca, b.caBundle(ca.certPEM, previous), nil
+}
+
+// caBundle 現在のCAと、有効期限内であれば以前のCAを連結したcaBundle
+func (b *Bootstrapper) caBundle(current, previous []byte) []byte {
+	bundle := append([]byte{}, current...)
+	if block, _ := pem.Decode(previous); block != nil {
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil && b.now().Before(cert.NotAfter) {
+			bundle = append(bundle, previous...)
+		}
+	}
+	return bundle
+}
+
+// ensureServingCert サーバー証明書を確認し、存在しない・CAが異なる・DNS名が不足している・有効期限が近い場合は発行する
+func (b *Bootstrapper) ensureServingCert(ctx context.Context, ca *bootstrapCA, caBundle []byte) error {
+	secret, err := b.client.CoreV1().Secrets(b.config.Namespace).Get(ctx, b.config.SecretName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("サーバー証明書のSecretの取得に失敗しました: %w", err)
+	}
+	if err == nil {
+		reason := b.servingCertRenewalReason(secret, ca, caBundle)
+		if reason == "" {
+			return nil
+		}
+		log.Printf("サーバー証明書を更新します: %s", reason)
+	}
+
+	certPEM, keyPEM, err := b.generateServingCert(ca)
+	if err != nil {
+		return err
+	}
+	data := map[string][]byte{SecretCertKey: certPEM, SecretKeyKey: keyPEM, SecretCAKey: caBundle}
+	if err := b.saveSecret(ctx, b.config.SecretName, corev1.SecretTypeTLS, data); err != nil {
+		return fmt.Errorf("サーバー証明書の保存に失敗しました: %w", err)
+	}
+	log.Printf("サーバー証明書を発行しました: %s/%s", b.config.Namespace, b.config.SecretName)
+	return nil
+}
+
+// servingCertRenewalReason サーバー証明書を更新する理由（更新不要の場合は空）
+func (b *Bootstrapper) servingCertRenewalReason(secret *corev1.Secret, ca *bootstrapCA, caBundle []byte) string {
+	pair, err := tls.X509KeyPair(secret.Data[SecretCertKey], secret.Data[SecretKeyKey])
+	if err != nil {
+		return fmt.Sprintf("証明書を読み込めません: %v", err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return fmt.Sprintf("証明書を解析できません: %v", err)
+	}
+	if err := cert.CheckSignatureFrom(ca.cert); err != nil {
+		return "現在のCAで署名されていません"
+	}
+	for _, name := range b.DNSNames() {
+		if err := cert.VerifyHostname(name); err != nil {
+			return fmt.Sprintf("DNS名が含まれていません: %s", name)
+		}
+	}
+	if !b.now().Add(b.config.RotateBefore).Before(cert.NotAfter) {
+		return fmt.Sprintf("有効期限が近づいています（%s）", cert.NotAfter.Format("2006-01-02"))
+	}
+	if !bytes.Equal(secret.Data[SecretCAKey], caBundle) {
+		return "ca.crtが現在のcaBundleと一致しません"
+	}
+	return ""
+}
+
+// injectCABundle webhook設定の全てのwebhookにcaBundleを設定する（変更がない場合は更新しない）
+func (b *Bootstrapper) injectCABundle(ctx context.Context, caBundle []byte) error {
+	admission := b.client.AdmissionregistrationV1()
+
+	if name := b.config.ValidatingWebhookName; name != "" {
+		config, err := admission.ValidatingWebhookConfigurations().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("ValidatingWebhookConfigurationの取得に失敗しました (%s): %w", name, err)
+		}
+		changed := false
+		for i := range config.Webhooks {
+			if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, caBundle) {
+				config.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if changed {
+			if _, err := admission.ValidatingWebhookConfigurations().Update(ctx, config, metav1.UpdateOptions{}); err != nil {
+				return fmt.Errorf("ValidatingWebhookConfigurationのcaBundleの更新に失敗しました (%s): %w", name, err)
+			}
+			log.Printf("ValidatingWebhookConfigurationのcaBundleを更新しました: %s", name)
+		}
+	}
+
+	if name := b.config.MutatingWebhookName; name != "" {
+		config, err := admission.MutatingWebhookConfigurations().Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("MutatingWebhookConfigurationの取得に失敗しました (%s): %w", name, err)
+		}
+		changed := false
+		for i := range config.Webhooks {
+			if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, caBundle) {
+				config.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if changed {
+			if _, err := admission.MutatingWebhookConfigurations().Update(ctx, config, metav1.UpdateOptions{}); err != nil {
+				return fmt.Errorf("MutatingWebhookConfigurationのcaBundleの更新に失敗しました (%s): %w", name, err)
+			}
+			log.Printf("MutatingWebhookConfigurationのcaBundleを更新しました: %s", name)
+		}
+	}
+
+	return nil
+}
+
+// saveSecret Secretを作成、または既存のSecretのデータを置き換える
+func (b *Bootstrapper) saveSecret(ctx context.Context, name string, secretType corev1.SecretType, data map[string][]byte) error {
+	secrets := b.client.CoreV1().Secrets(b.config.Namespace)
+
+	existing, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: b.config.Namespace,
+				Labels:    map[string]string{"app": "k8s-deployment-hpa-validator"},
+			},
+			Type: secretType,
+			Data: data,
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	existing.Data = data
+	_, err = secrets.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+// generateCA 自己署名CAを発行
+func (b *Bootstrapper) generateCA() (*bootstrapCA, []byte, error) {
+	key, keyPEM, err := generateECDSAKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := b.now()
+	template := &x509.Certificate{
+		SerialNumber: newSerialNumber(),
+		Subject: pkix.Name{
+			CommonName:   fmt.Sprintf("%s-ca@%d", b.config.ServiceName, now.Unix()),
+			Organization: []string{"k8s-deployment-hpa-validator"},
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(b.config.CAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CA証明書の作成に失敗しました: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CA証明書の解析に失敗しました: %w", err)
+	}
+
+	return &bootstrapCA{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}, keyPEM, nil
+}
+
+// generateServingCert ServiceのDNS名に対するサーバー証明書をCAで署名して発行
+func (b *Bootstrapper) generateServingCert(ca *bootstrapCA) ([]byte, []byte, error) {
+	key, keyPEM, err := generateECDSAKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := b.now()
+	notAfter := now.Add(b.config.ServingCertValidity)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	dnsNames := b.DNSNames()
+	template := &x509.Certificate{
+		SerialNumber: newSerialNumber(),
+		Subject: pkix.Name{
+			CommonName:   dnsNames[2],
+			Organization: []string{"k8s-deployment-hpa-validator"},
+		},
+		DNSNames:    dnsNames,
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("サーバー証明書の作成に失敗しました: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// source メトリクスで使用する読み込み元の名前
+func (b *Bootstrapper) source() string {
+	return fmt.Sprintf("secret:%s/%s", b.config.Namespace, b.config.SecretName)
+}
+
+// parseBootstrapCA SecretのデータからCAを復元
+func parseBootstrapCA(certPEM, keyPEM []byte) (*bootstrapCA, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("CA証明書ではありません: %s", cert.Subject)
+	}
+	signer, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("CAの秘密鍵で署名できません")
+	}
+	return &bootstrapCA{cert: cert, key: signer, certPEM: certPEM}, nil
+}
+
+// generateECDSAKey ECDSA P-256の秘密鍵を生成
+func generateECDSAKey() (*ecdsa.PrivateKey, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("秘密鍵の生成に失敗しました: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("秘密鍵のエンコードに失敗しました: %w", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// newSerialNumber 証明書のシリアル番号（128ビットの乱数）
+func newSerialNumber() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return big.NewInt(time.Now().UnixNano())
+	}
+	return serial
+}
@@ -0,0 +1,308 @@
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+const (
+	testBootstrapNamespace = "webhook-system"
+	testBootstrapSecret    = "webhook-tls"
+	testBootstrapService   = "k8s-deployment-hpa-validator"
+	testWebhookConfigName  = "k8s-deployment-hpa-validator"
+)
+
+// newBootstrapTestClient caBundleが未設定のwebhook設定を登録したfake clientsetを作成
+func newBootstrapTestClient() *fake.Clientset {
+	clientConfig := admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{Namespace: testBootstrapNamespace, Name: testBootstrapService},
+	}
+	return fake.NewSimpleClientset(
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: testWebhookConfigName},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{
+				{Name: "deployment.hpa-validator.io", ClientConfig: clientConfig},
+				{Name: "hpa.hpa-validator.io", ClientConfig: clientConfig},
+			},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: testWebhookConfigName},
+			Webhooks: []admissionregistrationv1.MutatingWebhook{
+				{Name: "deployment.hpa-mutator.io", ClientConfig: clientConfig},
+			},
+		},
+	)
+}
+
+func newTestBootstrapper(client *fake.Clientset) *Bootstrapper {
+	return NewBootstrapper(client, BootstrapConfig{
+		Namespace:             testBootstrapNamespace,
+		ServiceName:           testBootstrapService,
+		SecretName:            testBootstrapSecret,
+		ValidatingWebhookName: testWebhookConfigName,
+		MutatingWebhookName:   testWebhookConfigName,
+	})
+}
+
+// injectedCABundles webhook設定の全てのwebhookに設定されたcaBundle
+func injectedCABundles(t *testing.T, client *fake.Clientset) [][]byte {
+	t.Helper()
+
+	validating, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), testWebhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("ValidatingWebhookConfigurationの取得に失敗: %v", err)
+	}
+	mutating, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), testWebhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("MutatingWebhookConfigurationの取得に失敗: %v", err)
+	}
+
+	var bundles [][]byte
+	for _, webhook := range validating.Webhooks {
+		bundles = append(bundles, webhook.ClientConfig.CABundle)
+	}
+	for _, webhook := range mutating.Webhooks {
+		bundles = append(bundles, webhook.ClientConfig.CABundle)
+	}
+	return bundles
+}
+
+// servingSecretCert Secretに保存されたサーバー証明書
+func servingSecretCert(t *testing.T, client *fake.Clientset) *x509.Certificate {
+	t.Helper()
+
+	secret, err := client.CoreV1().Secrets(testBootstrapNamespace).Get(context.Background(), testBootstrapSecret, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("サーバー証明書のSecretの取得に失敗: %v", err)
+	}
+	block, _ := pem.Decode(secret.Data[SecretCertKey])
+	if block == nil {
+		t.Fatal("サーバー証明書のPEMデコードに失敗")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("サーバー証明書の解析に失敗: %v", err)
+	}
+	return cert
+}
+
+// verifyServingCert caBundleをルートとしてサーバー証明書をServiceのDNS名で検証（atの時点で）
+func verifyServingCert(t *testing.T, cert *x509.Certificate, caBundle []byte, at time.Time) {
+	t.Helper()
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caBundle) {
+		t.Fatal("caBundleの解析に失敗")
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		DNSName:     testBootstrapService + "." + testBootstrapNamespace + ".svc",
+		Roots:       roots,
+		CurrentTime: at,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}); err != nil {
+		t.Errorf("サーバー証明書がcaBundleで検証できません: %v", err)
+	}
+}
+
+func TestBootstrapper_Reconcile(t *testing.T) {
+	client := newBootstrapTestClient()
+	bootstrapper := newTestBootstrapper(client)
+
+	if err := bootstrapper.Reconcile(context.Background()); err != nil {
+		t.Fatalf("証明書の自動発行に失敗: %v", err)
+	}
+
+	// 全てのwebhookに同じcaBundleが設定される
+	bundles := injectedCABundles(t, client)
+	caBundle := bundles[0]
+	if len(caBundle) == 0 {
+		t.Fatal("caBundleが設定されていません")
+	}
+	for _, bundle := range bundles[1:] {
+		if !bytes.Equal(bundle, caBundle) {
+			t.Error("webhookごとにcaBundleが異なります")
+		}
+	}
+
+	caSecret, err := client.CoreV1().Secrets(testBootstrapNamespace).Get(context.Background(), bootstrapper.CASecretName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("CAのSecretが作成されていません: %v", err)
+	}
+	if !bytes.Equal(caSecret.Data[SecretCertKey], caBundle) {
+		t.Error("caBundleが保存したCAと一致しません")
+	}
+	verifyServingCert(t, servingSecretCert(t, client), caBundle, time.Now())
+
+	// 保存したSecretから読み込んだ証明書で、caBundleを信頼するクライアントとTLS接続できる
+	manager := NewManagerFromSecret(client, testBootstrapNamespace, testBootstrapSecret)
+	if _, err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("Secretからの証明書の読み込みに失敗: %v", err)
+	}
+	if err := manager.ValidateCertificateChain(); err != nil {
+		t.Errorf("証明書チェーンの検証に失敗: %v", err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{GetCertificate: manager.GetCertificate})
+	if err != nil {
+		t.Fatalf("TLSリスナーの作成に失敗: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.(*tls.Conn).Handshake()
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caBundle)
+	conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{
+		RootCAs:    roots,
+		ServerName: testBootstrapService + "." + testBootstrapNamespace + ".svc",
+	})
+	if err != nil {
+		t.Fatalf("caBundleを信頼するクライアントのTLS接続に失敗: %v", err)
+	}
+	conn.Close()
+
+	// 有効な証明書とcaBundleが揃っている場合は何も更新しない
+	client.ClearActions()
+	if err := bootstrapper.Reconcile(context.Background()); err != nil {
+		t.Fatalf("2回目の自動発行に失敗: %v", err)
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() != "get" {
+			t.Errorf("変更がないのに更新されました: %s %s", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+}
+
+func TestBootstrapper_Reconcile_Rotation(t *testing.T) {
+	client := newBootstrapTestClient()
+	bootstrapper := newTestBootstrapper(client)
+	start := time.Now()
+
+	if err := bootstrapper.Reconcile(context.Background()); err != nil {
+		t.Fatalf("証明書の自動発行に失敗: %v", err)
+	}
+	initialCABundle := injectedCABundles(t, client)[0]
+	initialServing := servingSecretCert(t, client)
+
+	// サーバー証明書の有効期限が近づくと、同じCAで再発行する
+	bootstrapper.now = func() time.Time { return start.Add(DefaultServingCertValidity - DefaultRotateBefore + time.Hour) }
+	if err := bootstrapper.Reconcile(context.Background()); err != nil {
+		t.Fatalf("サーバー証明書の更新に失敗: %v", err)
+	}
+	rotatedServing := servingSecretCert(t, client)
+	if rotatedServing.SerialNumber.Cmp(initialServing.SerialNumber) == 0 {
+		t.Fatal("有効期限が近いサーバー証明書が更新されていません")
+	}
+	if !bytes.Equal(injectedCABundles(t, client)[0], initialCABundle) {
+		t.Error("サーバー証明書の更新でcaBundleが変わりました")
+	}
+	verifyServingCert(t, rotatedServing, initialCABundle, bootstrapper.now())
+
+	// CAの有効期限が近づくと新しいCAを発行し、以前のCAも期限まではcaBundleに含める
+	bootstrapper.now = func() time.Time { return start.Add(DefaultCAValidity - DefaultRotateBefore + time.Hour) }
+	client.ClearActions()
+	if err := bootstrapper.Reconcile(context.Background()); err != nil {
+		t.Fatalf("CAの更新に失敗: %v", err)
+	}
+	// 新しいCAを含むcaBundleを設定してから、新しいCAで署名したサーバー証明書を保存する
+	bundleUpdated, servingUpdated := -1, -1
+	for i, action := range client.Actions() {
+		update, ok := action.(clienttesting.UpdateAction)
+		if !ok {
+			continue
+		}
+		switch {
+		case action.GetResource().Resource == "validatingwebhookconfigurations" && bundleUpdated < 0:
+			bundleUpdated = i
+		case action.GetResource().Resource == "secrets" && update.GetObject().(*corev1.Secret).Name == testBootstrapSecret:
+			servingUpdated = i
+		}
+	}
+	if bundleUpdated < 0 || servingUpdated < 0 || bundleUpdated > servingUpdated {
+		t.Errorf("caBundleの更新(%d)がサーバー証明書の更新(%d)より先に行われていません", bundleUpdated, servingUpdated)
+	}
+	rotatedCABundle := injectedCABundles(t, client)[0]
+	if !bytes.Contains(rotatedCABundle, initialCABundle) || len(rotatedCABundle) <= len(initialCABundle) {
+		t.Fatal("新しいCAと以前のCAがcaBundleに含まれていません")
+	}
+
+	caSecret, err := client.CoreV1().Secrets(testBootstrapNamespace).Get(context.Background(), bootstrapper.CASecretName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("CAのSecretの取得に失敗: %v", err)
+	}
+	block, _ := pem.Decode(caSecret.Data[SecretCertKey])
+	newCA, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("新しいCAの解析に失敗: %v", err)
+	}
+	if err := servingSecretCert(t, client).CheckSignatureFrom(newCA); err != nil {
+		t.Errorf("サーバー証明書が新しいCAで署名されていません: %v", err)
+	}
+}
+
+func TestBootstrapper_Reconcile_MissingWebhookConfiguration(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	bootstrapper := newTestBootstrapper(client)
+
+	if err := bootstrapper.Reconcile(context.Background()); err == nil {
+		t.Fatal("ValidatingWebhookConfigurationが存在しない場合はエラーが期待されます")
+	}
+}
+
+func TestBootstrapper_Reconcile_ConcurrentReplicas(t *testing.T) {
+	// 2つのレプリカが同じAPIサーバー（オブジェクト）を参照する
+	clientA := newBootstrapTestClient()
+	clientB := &fake.Clientset{}
+	clientB.AddReactor("*", "*", clienttesting.ObjectReaction(clientA.Tracker()))
+	replicaA := newTestBootstrapper(clientA)
+	replicaB := newTestBootstrapper(clientB)
+
+	// レプリカBがCAのSecretを作成する直前に、レプリカAが全ての発行を終える
+	raced := false
+	clientB.PrependReactor("create", "secrets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if !raced {
+			raced = true
+			if err := replicaA.Reconcile(context.Background()); err != nil {
+				t.Errorf("レプリカAの自動発行に失敗: %v", err)
+			}
+		}
+		return false, nil, nil
+	})
+
+	if err := replicaB.Reconcile(context.Background()); err != nil {
+		t.Fatalf("競合したレプリカBの自動発行に失敗: %v", err)
+	}
+	if !raced {
+		t.Fatal("レプリカBがSecretを作成しようとしていません")
+	}
+
+	// レプリカBはレプリカAが発行したCAとサーバー証明書をそのまま使用する
+	caSecret, err := clientA.CoreV1().Secrets(testBootstrapNamespace).Get(context.Background(), replicaA.CASecretName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("CAのSecretの取得に失敗: %v", err)
+	}
+	for _, bundle := range injectedCABundles(t, clientA) {
+		if !bytes.Equal(bundle, caSecret.Data[SecretCertKey]) {
+			t.Error("caBundleが保存したCAと一致しません")
+		}
+	}
+	verifyServingCert(t, servingSecretCert(t, clientA), caSecret.Data[SecretCertKey], time.Now())
+}
@@ -0,0 +1,215 @@
+package cert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// caBundleMatch webhookのcaBundleでサーバー証明書を検証できるかどうか
+	caBundleMatch = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "webhook_ca_bundle_match",
+			Help: "webhook設定のcaBundleでサーバー証明書を検証できるかどうか（1=一致、0=不一致）",
+		},
+		[]string{"configuration", "webhook"},
+	)
+)
+
+// CABundleResult webhookごとのcaBundleの検証結果
+type CABundleResult struct {
+	// Configuration webhook設定の種類と名前（例: ValidatingWebhookConfiguration/k8s-deployment-hpa-validator）
+	Configuration string `json:"configuration"`
+	Webhook       string `json:"webhook"`
+	Matched       bool   `json:"matched"`
+	Error         string `json:"error,omitempty"`
+}
+
+// CABundleStatus caBundleの検証結果
+type CABundleStatus struct {
+	CheckedAt time.Time        `json:"checked_at"`
+	Results   []CABundleResult `json:"results"`
+	// Err webhook設定を取得できなかった場合のエラー（検証は行われていない）
+	Err error `json:"-"`
+}
+
+// Mismatches caBundleでサーバー証明書を検証できなかったwebhook
+func (s *CABundleStatus) Mismatches() []CABundleResult {
+	var mismatches []CABundleResult
+	for _, result := range s.Results {
+		if !result.Matched {
+			mismatches = append(mismatches, result)
+		}
+	}
+	return mismatches
+}
+
+// CABundleChecker 自身のwebhook設定のcaBundleで、読み込み済みのサーバー証明書を検証できるかを確認する
+// 証明書を更新した結果caBundleのCAと一致しなくなると、API serverからの呼び出しが全て失敗するため
+type CABundleChecker struct {
+	client  kubernetes.Interface
+	manager *Manager
+	// validatingName, mutatingName 確認するwebhook設定の名前（mutatingNameが空の場合は確認しない）
+	validatingName string
+	mutatingName   string
+
+	mutex sync.RWMutex
+	last  *CABundleStatus
+}
+
+// NewCABundleChecker creates a new caBundle checker
+func NewCABundleChecker(client kubernetes.Interface, manager *Manager, validatingName, mutatingName string) *CABundleChecker {
+	return &CABundleChecker{
+		client:         client,
+		manager:        manager,
+		validatingName: validatingName,
+		mutatingName:   mutatingName,
+	}
+}
+
+// Check 現在のサーバー証明書を検証し、結果をメトリクスと LastStatus に反映する
+func (c *CABundleChecker) Check(ctx context.Context) *CABundleStatus {
+	var status *CABundleStatus
+	if current := c.manager.GetCurrentCertificate(); current == nil {
+		status = &CABundleStatus{CheckedAt: time.Now(), Err: fmt.Errorf("証明書が読み込まれていません")}
+	} else {
+		status = c.Verify(ctx, current)
+	}
+
+	if status.Err != nil {
+		certificateMonitoringErrors.WithLabelValues(c.manager.source, "ca_bundle_check_failed").Inc()
+	} else {
+		caBundleMatch.Reset()
+		for _, result := range status.Results {
+			value := 0.0
+			if result.Matched {
+				value = 1
+			}
+			caBundleMatch.WithLabelValues(result.Configuration, result.Webhook).Set(value)
+		}
+	}
+
+	c.mutex.Lock()
+	c.last = status
+	c.mutex.Unlock()
+	return status
+}
+
+// LastStatus 最後に Check した結果（未確認の場合はnil）
+func (c *CABundleChecker) LastStatus() *CABundleStatus {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.last
+}
+
+// Verify 指定した証明書をwebhook設定の各caBundleで検証する（差し替え前の新しい証明書の確認にも使用する）
+func (c *CABundleChecker) Verify(ctx context.Context, cert *tls.Certificate) *CABundleStatus {
+	status := &CABundleStatus{CheckedAt: time.Now()}
+
+	chain, err := parseChain(cert)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+
+	admission := c.client.AdmissionregistrationV1()
+	if c.validatingName != "" {
+		config, err := admission.ValidatingWebhookConfigurations().Get(ctx, c.validatingName, metav1.GetOptions{})
+		if err != nil {
+			status.Err = fmt.Errorf("ValidatingWebhookConfigurationの取得に失敗しました (%s): %w", c.validatingName, err)
+			return status
+		}
+		for _, webhook := range config.Webhooks {
+			status.Results = append(status.Results, verifyClientConfig("ValidatingWebhookConfiguration/"+config.Name, webhook.Name, webhook.ClientConfig, chain))
+		}
+	}
+
+	if c.mutatingName != "" {
+		config, err := admission.MutatingWebhookConfigurations().Get(ctx, c.mutatingName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			status.Err = fmt.Errorf("MutatingWebhookConfigurationの取得に失敗しました (%s): %w", c.mutatingName, err)
+			return status
+		}
+		if err == nil {
+			for _, webhook := range config.Webhooks {
+				status.Results = append(status.Results, verifyClientConfig("MutatingWebhookConfiguration/"+config.Name, webhook.Name, webhook.ClientConfig, chain))
+			}
+		}
+	}
+
+	return status
+}
+
+// parseChain tls.Certificateのサーバー証明書と中間証明書を解析
+func parseChain(cert *tls.Certificate) ([]*x509.Certificate, error) {
+	if cert == nil || len(cert.Certificate) == 0 {
+		return nil, fmt.Errorf("証明書が読み込まれていません")
+	}
+	chain := make([]*x509.Certificate, 0, len(cert.Certificate))
+	for _, der := range cert.Certificate {
+		parsed, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("証明書の解析に失敗しました: %w", err)
+		}
+		chain = append(chain, parsed)
+	}
+	return chain, nil
+}
+
+// verifyClientConfig API serverと同じ条件（caBundle、未設定の場合はシステムのルート証明書）でサーバー証明書を検証
+func verifyClientConfig(configuration, webhook string, clientConfig admissionregistrationv1.WebhookClientConfig, chain []*x509.Certificate) CABundleResult {
+	result := CABundleResult{Configuration: configuration, Webhook: webhook}
+
+	var roots *x509.CertPool
+	if len(clientConfig.CABundle) > 0 {
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(clientConfig.CABundle) {
+			result.Error = "caBundleを解析できません"
+			return result
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	options := x509.VerifyOptions{
+		DNSName:       webhookServerName(clientConfig),
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if _, err := chain[0].Verify(options); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Matched = true
+	return result
+}
+
+// webhookServerName API serverが接続時に検証するサーバー名
+func webhookServerName(clientConfig admissionregistrationv1.WebhookClientConfig) string {
+	if service := clientConfig.Service; service != nil {
+		return fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+	}
+	if clientConfig.URL != nil {
+		if parsed, err := url.Parse(*clientConfig.URL); err == nil {
+			return parsed.Hostname()
+		}
+	}
+	return ""
+}
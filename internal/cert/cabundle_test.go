@@ -0,0 +1,139 @@
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCABundleChecker_Check(t *testing.T) {
+	client := newBootstrapTestClient()
+	if err := newTestBootstrapper(client).Reconcile(context.Background()); err != nil {
+		t.Fatalf("証明書の自動発行に失敗: %v", err)
+	}
+	manager := NewManagerFromSecret(client, testBootstrapNamespace, testBootstrapSecret)
+	if _, err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("証明書の読み込みに失敗: %v", err)
+	}
+	checker := NewCABundleChecker(client, manager, testWebhookConfigName, testWebhookConfigName)
+
+	if checker.LastStatus() != nil {
+		t.Fatal("確認前に結果があります")
+	}
+
+	// 自動発行したcaBundleでは全てのwebhookで検証できる
+	status := checker.Check(context.Background())
+	if status.Err != nil {
+		t.Fatalf("caBundleの確認に失敗: %v", status.Err)
+	}
+	if len(status.Results) != 3 {
+		t.Fatalf("3つのwebhookの結果が期待されましたが、%d件でした", len(status.Results))
+	}
+	if mismatches := status.Mismatches(); len(mismatches) != 0 {
+		t.Fatalf("不一致は期待されません: %+v", mismatches)
+	}
+
+	// 別のCAのcaBundleに差し替えられたwebhookは不一致として検出する
+	dir := t.TempDir()
+	otherCAFile := filepath.Join(dir, "ca.crt")
+	if err := generateCACertificate(otherCAFile, filepath.Join(dir, "ca.key")); err != nil {
+		t.Fatalf("CA証明書の生成に失敗: %v", err)
+	}
+	otherCA, err := os.ReadFile(otherCAFile)
+	if err != nil {
+		t.Fatalf("CA証明書の読み込みに失敗: %v", err)
+	}
+	configs := client.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	config, err := configs.Get(context.Background(), testWebhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("ValidatingWebhookConfigurationの取得に失敗: %v", err)
+	}
+	config.Webhooks[1].ClientConfig.CABundle = otherCA
+	if _, err := configs.Update(context.Background(), config, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("ValidatingWebhookConfigurationの更新に失敗: %v", err)
+	}
+
+	status = checker.Check(context.Background())
+	mismatches := status.Mismatches()
+	if len(mismatches) != 1 || mismatches[0].Webhook != "hpa.hpa-validator.io" || mismatches[0].Error == "" {
+		t.Fatalf("hpa.hpa-validator.io の不一致が期待されましたが、%+v でした", mismatches)
+	}
+	if checker.LastStatus() != status {
+		t.Error("LastStatusが最後の確認結果ではありません")
+	}
+
+	configuration := "ValidatingWebhookConfiguration/" + testWebhookConfigName
+	if value := testutil.ToFloat64(caBundleMatch.WithLabelValues(configuration, "hpa.hpa-validator.io")); value != 0 {
+		t.Errorf("不一致のwebhookのメトリクスは0が期待されましたが、%v でした", value)
+	}
+	if value := testutil.ToFloat64(caBundleMatch.WithLabelValues(configuration, "deployment.hpa-validator.io")); value != 1 {
+		t.Errorf("一致するwebhookのメトリクスは1が期待されましたが、%v でした", value)
+	}
+
+	// webhook設定を取得できない場合は不一致ではなくエラーとする
+	missing := NewCABundleChecker(client, manager, "missing", "")
+	if status := missing.Check(context.Background()); status.Err == nil || len(status.Mismatches()) != 0 {
+		t.Errorf("取得エラーが期待されましたが、%+v でした", status)
+	}
+}
+
+func TestManager_BeforeSwapCallback(t *testing.T) {
+	client := newBootstrapTestClient()
+	secrets := client.CoreV1().Secrets(testBootstrapNamespace)
+	if _, err := secrets.Create(context.Background(), newTLSSecret(newCASignedSecretData(t)), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Secretの作成に失敗: %v", err)
+	}
+
+	manager := NewManagerFromSecret(client, testBootstrapNamespace, testBootstrapSecret)
+	var swapped []tls.Certificate
+	manager.SetBeforeSwapCallback(func(cert tls.Certificate) {
+		swapped = append(swapped, cert)
+	})
+
+	// 初回の読み込みは差し替えではないため呼び出されない
+	if _, err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("証明書の読み込みに失敗: %v", err)
+	}
+	if len(swapped) != 0 {
+		t.Fatal("初回の読み込みでコールバックが呼び出されました")
+	}
+	initial := manager.GetCurrentCertificate()
+
+	// 差し替えの直前に新しい証明書で呼び出され、その時点では現在の証明書は変わっていない
+	// コールバックの実行中もCA証明書を参照できる（読み込みのロックを保持していない）
+	manager.SetBeforeSwapCallback(func(cert tls.Certificate) {
+		if manager.GetCurrentCertificate() != initial {
+			t.Error("コールバックの前に証明書が差し替えられました")
+		}
+		read := make(chan struct{})
+		go func() {
+			defer close(read)
+			manager.readCABundle()
+		}()
+		select {
+		case <-read:
+		case <-time.After(5 * time.Second):
+			t.Error("コールバックの実行中にCA証明書の参照がブロックされました")
+		}
+		swapped = append(swapped, cert)
+	})
+	if _, err := secrets.Update(context.Background(), newTLSSecret(newCASignedSecretData(t)), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Secretの更新に失敗: %v", err)
+	}
+	if _, err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("証明書の再読み込みに失敗: %v", err)
+	}
+	if len(swapped) != 1 {
+		t.Fatalf("コールバックが1回呼び出されることが期待されましたが、%d回でした", len(swapped))
+	}
+	if !bytes.Equal(swapped[0].Certificate[0], manager.GetCurrentCertificate().Certificate[0]) {
+		t.Error("コールバックに差し替え後の証明書が渡されていません")
+	}
+}
@@ -1,7 +1,9 @@
 package cert
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
@@ -10,6 +12,7 @@ import (
 	"net"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -59,13 +62,33 @@ type Manager struct {
 	certFile        string
 	keyFile         string
 	caFile          string
-	currentCert     *tls.Certificate
-	certMutex       sync.RWMutex
+	// secret 証明書を読み込むSecret（nilの場合はファイルから読み込む）
+	secret          *secretSource
+	// source メトリクスとログで使用する証明書の読み込み元
+	source          string
+	// currentCert 現在の証明書（ハンドシェイク中に読み込まれるため、アトミックに差し替える）
+	currentCert     atomic.Pointer[tls.Certificate]
+	// reloadMutex 証明書の読み込みから差し替えまでを直列化する（古い証明書で新しい証明書を上書きしないため）
+	reloadMutex     sync.Mutex
+	// loadMutex loadedHashとloadedCAを保護する（beforeSwapCallbackの実行中は保持しない）
+	loadMutex       sync.Mutex
+	// loadedHash 現在の証明書を読み込んだ時点の証明書と秘密鍵の内容のハッシュ
+	loadedHash      [sha256.Size]byte
+	// loadedCA 現在の証明書と同時に読み込んだCA証明書（Secretから読み込む場合のみ）
+	loadedCA        []byte
 	monitoringCtx   context.Context
 	monitoringStop  context.CancelFunc
 	reloadCallback  func(tls.Certificate)
+	// beforeSwapCallback 読み込んだ証明書で現在の証明書を差し替える直前に呼び出す（loadMutexは保持しない）
+	beforeSwapCallback func(tls.Certificate)
+	// fileWatch ファイル変更通知（inotifyなど）を使用するかどうか
+	fileWatch       bool
 }
 
+// fileWatchDebounce ファイル変更通知を受けてから再読み込みするまでの待機時間
+// kubeletによるSecretボリュームの更新（..dataシンボリックリンクの差し替え）が完了するのを待つ
+const fileWatchDebounce = 200 * time.Millisecond
+
 // NewManager creates a new certificate manager
 func NewManager(certFile, keyFile, caFile string) *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -73,6 +96,7 @@ func NewManager(certFile, keyFile, caFile string) *Manager {
 		certFile:       certFile,
 		keyFile:        keyFile,
 		caFile:         caFile,
+		source:         certFile,
 		monitoringCtx:  ctx,
 		monitoringStop: cancel,
 	}
@@ -83,52 +107,97 @@ func (m *Manager) SetReloadCallback(callback func(tls.Certificate)) {
 	m.reloadCallback = callback
 }
 
+// SetBeforeSwapCallback 再読み込みで証明書を差し替える直前に呼び出す関数を設定
+// 差し替えを止めることはできない（caBundleとの不一致などを事前に記録するために使用する）
+func (m *Manager) SetBeforeSwapCallback(callback func(tls.Certificate)) {
+	m.beforeSwapCallback = callback
+}
+
+// SetFileWatch ファイル変更通知による再読み込みを有効にする（プラットフォームが対応している場合のみ）
+// 定期的な確認は有効・無効にかかわらず行われる
+func (m *Manager) SetFileWatch(enabled bool) {
+	m.fileWatch = enabled
+}
+
 // LoadCertificate loads and validates TLS certificate
+// 読み込みや検証に失敗した場合、現在の証明書はそのまま使用される
 func (m *Manager) LoadCertificate() (tls.Certificate, error) {
-	log.Printf("証明書を読み込み中: %s, %s", m.certFile, m.keyFile)
-	
-	// 証明書ファイルの存在確認
-	if err := m.validateFileExists(m.certFile); err != nil {
-		certificateReloads.WithLabelValues(m.certFile, "error").Inc()
-		return tls.Certificate{}, fmt.Errorf("証明書ファイルが見つかりません: %w", err)
+	m.reloadMutex.Lock()
+	defer m.reloadMutex.Unlock()
+	return m.loadCertificate()
+}
+
+// loadCertificate 証明書を読み込む（reloadMutexを保持した状態で呼び出す）
+func (m *Manager) loadCertificate() (tls.Certificate, error) {
+	if m.secret != nil {
+		log.Printf("証明書を読み込み中: %s", m.source)
+	} else {
+		log.Printf("証明書を読み込み中: %s, %s", m.certFile, m.keyFile)
+
+		// 証明書ファイルの存在確認
+		if err := m.validateFileExists(m.certFile); err != nil {
+			certificateReloads.WithLabelValues(m.source, "error").Inc()
+			return tls.Certificate{}, fmt.Errorf("証明書ファイルが見つかりません: %w", err)
+		}
+
+		if err := m.validateFileExists(m.keyFile); err != nil {
+			certificateReloads.WithLabelValues(m.source, "error").Inc()
+			return tls.Certificate{}, fmt.Errorf("秘密鍵ファイルが見つかりません: %w", err)
+		}
 	}
 	
-	if err := m.validateFileExists(m.keyFile); err != nil {
-		certificateReloads.WithLabelValues(m.certFile, "error").Inc()
-		return tls.Certificate{}, fmt.Errorf("秘密鍵ファイルが見つかりません: %w", err)
+	// 証明書と秘密鍵を同じ世代の組として読み込む
+	pair, err := m.readKeyPair()
+	if err != nil {
+		certificateReloads.WithLabelValues(m.source, "error").Inc()
+		return tls.Certificate{}, fmt.Errorf("証明書の読み込みに失敗しました: %w", err)
 	}
-	
-	// 証明書の読み込み
-	cert, err := tls.LoadX509KeyPair(m.certFile, m.keyFile)
+
+	// 秘密鍵が証明書の公開鍵と一致しない組（更新途中の異なる世代の組など）はここで拒否される
+	cert, err := tls.X509KeyPair(pair.certPEM, pair.keyPEM)
 	if err != nil {
-		certificateReloads.WithLabelValues(m.certFile, "error").Inc()
+		certificateReloads.WithLabelValues(m.source, "error").Inc()
 		return tls.Certificate{}, fmt.Errorf("証明書の読み込みに失敗しました: %w", err)
 	}
 	
 	// 証明書の検証
 	if err := m.validateCertificate(cert); err != nil {
-		certificateReloads.WithLabelValues(m.certFile, "error").Inc()
+		certificateReloads.WithLabelValues(m.source, "error").Inc()
 		return tls.Certificate{}, fmt.Errorf("証明書の検証に失敗しました: %w", err)
 	}
 	
-	// 現在の証明書を更新
-	m.certMutex.Lock()
-	m.currentCert = &cert
-	m.certMutex.Unlock()
+	// 現在の証明書を更新（初回の読み込みは差し替えではないため通知しない）
+	// 差し替え前の確認はwebhook設定を取得するため、loadMutexを保持せずに行う
+	if m.beforeSwapCallback != nil && m.currentCert.Load() != nil {
+		m.beforeSwapCallback(cert)
+	}
+	m.loadMutex.Lock()
+	m.currentCert.Store(&cert)
+	m.loadedHash = pair.hash
+	m.loadedCA = pair.caPEM
+	m.loadMutex.Unlock()
 	
 	// メトリクスを更新
 	m.updateCertificateMetrics(cert)
 	
-	certificateReloads.WithLabelValues(m.certFile, "success").Inc()
+	certificateReloads.WithLabelValues(m.source, "success").Inc()
 	log.Printf("証明書の読み込みが完了しました")
 	return cert, nil
 }
 
 // GetCurrentCertificate returns the current certificate safely
 func (m *Manager) GetCurrentCertificate() *tls.Certificate {
-	m.certMutex.RLock()
-	defer m.certMutex.RUnlock()
-	return m.currentCert
+	return m.currentCert.Load()
+}
+
+// GetCertificate tls.Config.GetCertificate用に現在の証明書を返す
+// 再読み込みはポインタの差し替えのみで行われるため、実行中のハンドシェイクと競合しない
+func (m *Manager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := m.GetCurrentCertificate()
+	if cert == nil {
+		return nil, fmt.Errorf("証明書が読み込まれていません: %s", m.source)
+	}
+	return cert, nil
 }
 
 // StartMonitoring starts certificate monitoring and auto-reload
@@ -144,57 +213,145 @@ func (m *Manager) StopMonitoring() {
 }
 
 // monitorCertificate monitors certificate files for changes and expiry
+// 証明書と秘密鍵の内容のハッシュで変更を検出するため、更新時刻が変わらない
+// kubeletの..dataシンボリックリンクの差し替えも検出できる
 func (m *Manager) monitorCertificate(checkInterval time.Duration) {
 	ticker := time.NewTicker(checkInterval)
 	defer ticker.Stop()
-	
-	var lastModTime time.Time
-	
-	// 初回のファイル更新時刻を取得
-	if info, err := os.Stat(m.certFile); err == nil {
-		lastModTime = info.ModTime()
+
+	// Secretの変更通知、またはファイル変更通知（対応していない場合は定期的な確認のみ）
+	var events <-chan struct{}
+	if m.secret != nil {
+		events = m.secret.watch(m.monitoringCtx, m.source)
+	} else if m.fileWatch {
+		watcher, err := watchFiles(m.certFile, m.keyFile)
+		if err != nil {
+			log.Printf("証明書ファイルの変更通知を利用できません。定期的な確認のみ行います: %v", err)
+			certificateMonitoringErrors.WithLabelValues(m.source, "watch_failed").Inc()
+		} else {
+			defer watcher.Close()
+			events = watcher.Events()
+		}
 	}
-	
+
+	var debounce <-chan time.Time
 	for {
 		select {
 		case <-m.monitoringCtx.Done():
 			log.Printf("証明書監視を停止しました")
 			return
-		case <-ticker.C:
-			// ファイルの更新確認
-			if info, err := os.Stat(m.certFile); err == nil {
-				if info.ModTime().After(lastModTime) {
-					log.Printf("証明書ファイルの更新を検出しました: %s", m.certFile)
-					lastModTime = info.ModTime()
-					
-					// 証明書を再読み込み
-					if cert, err := m.LoadCertificate(); err != nil {
-						log.Printf("証明書の再読み込みに失敗しました: %v", err)
-						certificateMonitoringErrors.WithLabelValues(m.certFile, "reload_failed").Inc()
-					} else {
-						log.Printf("証明書の再読み込みが完了しました")
-						
-						// コールバック関数を呼び出し
-						if m.reloadCallback != nil {
-							m.reloadCallback(cert)
-						}
-					}
-				}
-			} else {
-				certificateMonitoringErrors.WithLabelValues(m.certFile, "file_access_error").Inc()
+		case <-events:
+			// 連続する変更通知はまとめて処理する
+			if debounce == nil {
+				debounce = time.After(fileWatchDebounce)
 			}
-			
+		case <-debounce:
+			debounce = nil
+			m.reloadIfChanged()
+		case <-ticker.C:
+			m.reloadIfChanged()
+
 			// 証明書の有効期限確認
 			m.checkCertificateExpiry()
 		}
 	}
 }
 
+// reloadIfChanged 証明書または秘密鍵の内容が変わっている場合に再読み込み
+// 再読み込みに失敗した場合は現在の証明書を使い続け、次回の確認で再試行する
+func (m *Manager) reloadIfChanged() {
+	m.reloadMutex.Lock()
+	pair, err := m.readKeyPair()
+	if err != nil {
+		m.reloadMutex.Unlock()
+		log.Printf("証明書の確認に失敗しました (%s): %v", m.source, err)
+		certificateMonitoringErrors.WithLabelValues(m.source, "file_access_error").Inc()
+		return
+	}
+	m.loadMutex.Lock()
+	unchanged := pair.hash == m.loadedHash
+	m.loadMutex.Unlock()
+	if unchanged {
+		m.reloadMutex.Unlock()
+		return
+	}
+
+	log.Printf("証明書の更新を検出しました: %s", m.source)
+	cert, err := m.loadCertificate()
+	m.reloadMutex.Unlock()
+	if err != nil {
+		log.Printf("証明書の再読み込みに失敗しました。現在の証明書を使い続けます: %v", err)
+		certificateMonitoringErrors.WithLabelValues(m.source, "reload_failed").Inc()
+		return
+	}
+	log.Printf("証明書の再読み込みが完了しました")
+
+	// コールバック関数を呼び出し
+	if m.reloadCallback != nil {
+		m.reloadCallback(cert)
+	}
+}
+
+// keyPair 同じ世代として読み込んだ証明書・秘密鍵・CA証明書
+type keyPair struct {
+	certPEM []byte
+	keyPEM  []byte
+	caPEM   []byte
+	// hash 全ての内容のハッシュ（変更検出用）
+	hash [sha256.Size]byte
+}
+
+// newKeyPair 読み込んだ内容からkeyPairを作成
+func newKeyPair(certPEM, keyPEM, caPEM []byte) *keyPair {
+	h := sha256.New()
+	for _, data := range [][]byte{certPEM, keyPEM, caPEM} {
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	pair := &keyPair{certPEM: certPEM, keyPEM: keyPEM, caPEM: caPEM}
+	copy(pair.hash[:], h.Sum(nil))
+	return pair
+}
+
+// readKeyPair 証明書と秘密鍵を読み込み、両方の内容のハッシュを返す
+// 読み込み中に証明書が差し替えられた場合は、異なる世代の組にならないよう読み直す
+func (m *Manager) readKeyPair() (*keyPair, error) {
+	if m.secret != nil {
+		return m.secret.readKeyPair()
+	}
+
+	const maxAttempts = 3
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		certPEM, err := os.ReadFile(m.certFile)
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := os.ReadFile(m.keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		// 秘密鍵の読み込み後も証明書が変わっていなければ同じ世代の組とみなす
+		recheck, err := os.ReadFile(m.certFile)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(certPEM, recheck) {
+			continue
+		}
+
+		return newKeyPair(certPEM, keyPEM, nil), nil
+	}
+
+	return nil, fmt.Errorf("証明書ファイルが読み込み中に更新され続けています: %s", m.certFile)
+}
+
 // checkCertificateExpiry checks certificate expiry and updates metrics
 func (m *Manager) checkCertificateExpiry() {
 	info, err := m.GetCertificateInfo()
 	if err != nil {
-		certificateMonitoringErrors.WithLabelValues(m.certFile, "expiry_check_failed").Inc()
+		certificateMonitoringErrors.WithLabelValues(m.source, "expiry_check_failed").Inc()
 		log.Printf("証明書の有効期限確認に失敗しました: %v", err)
 		return
 	}
@@ -225,28 +382,25 @@ func (m *Manager) updateCertificateMetrics(cert tls.Certificate) {
 	isValid := time.Now().Before(x509Cert.NotAfter) && time.Now().After(x509Cert.NotBefore)
 	
 	// メトリクスを更新
-	certificateExpiryDays.WithLabelValues(m.certFile, subject, issuer).Set(float64(daysUntilExpiry))
+	certificateExpiryDays.WithLabelValues(m.source, subject, issuer).Set(float64(daysUntilExpiry))
 	
 	if isValid {
-		certificateValid.WithLabelValues(m.certFile, subject).Set(1)
+		certificateValid.WithLabelValues(m.source, subject).Set(1)
 	} else {
-		certificateValid.WithLabelValues(m.certFile, subject).Set(0)
+		certificateValid.WithLabelValues(m.source, subject).Set(0)
 	}
 }
 
 // ValidateCertificateChain validates the certificate chain with CA
+// Secretから読み込む場合は、同じSecretのca.crtで検証する
 func (m *Manager) ValidateCertificateChain() error {
-	if m.caFile == "" {
-		log.Printf("CA証明書ファイルが指定されていません。チェーン検証をスキップします")
-		return nil
-	}
-	
-	log.Printf("証明書チェーンを検証中: %s", m.caFile)
-	
-	// CA証明書の読み込み
-	caCert, err := os.ReadFile(m.caFile)
+	caCert, err := m.readCABundle()
 	if err != nil {
-		return fmt.Errorf("CA証明書の読み込みに失敗しました: %w", err)
+		return err
+	}
+	if len(caCert) == 0 {
+		log.Printf("CA証明書が指定されていません。チェーン検証をスキップします")
+		return nil
 	}
 	
 	caCertPool := x509.NewCertPool()
@@ -255,21 +409,11 @@ func (m *Manager) ValidateCertificateChain() error {
 	}
 	
 	// サーバー証明書の読み込み
-	serverCert, err := os.ReadFile(m.certFile)
+	cert, err := m.servingCertificate()
 	if err != nil {
 		return fmt.Errorf("サーバー証明書の読み込みに失敗しました: %w", err)
 	}
 	
-	block, _ := pem.Decode(serverCert)
-	if block == nil {
-		return fmt.Errorf("サーバー証明書のデコードに失敗しました")
-	}
-	
-	cert, err := x509.ParseCertificate(block.Bytes)
-	if err != nil {
-		return fmt.Errorf("サーバー証明書のパースに失敗しました: %w", err)
-	}
-	
 	// 証明書チェーンの検証
 	opts := x509.VerifyOptions{
 		Roots: caCertPool,
@@ -284,8 +428,40 @@ func (m *Manager) ValidateCertificateChain() error {
 	return nil
 }
 
-// GetCertificateInfo returns certificate information
-func (m *Manager) GetCertificateInfo() (*CertificateInfo, error) {
+// readCABundle チェーン検証に使用するCA証明書を読み込む（指定されていない場合は空）
+func (m *Manager) readCABundle() ([]byte, error) {
+	if m.secret != nil {
+		m.loadMutex.Lock()
+		defer m.loadMutex.Unlock()
+		return m.loadedCA, nil
+	}
+	if m.caFile == "" {
+		return nil, nil
+	}
+
+	log.Printf("証明書チェーンを検証中: %s", m.caFile)
+	caCert, err := os.ReadFile(m.caFile)
+	if err != nil {
+		return nil, fmt.Errorf("CA証明書の読み込みに失敗しました: %w", err)
+	}
+	return caCert, nil
+}
+
+// servingCertificate サーバー証明書を取得
+// Secretから読み込む場合は、読み込み済みの現在の証明書を使用する
+func (m *Manager) servingCertificate() (*x509.Certificate, error) {
+	if m.secret != nil {
+		current := m.GetCurrentCertificate()
+		if current == nil || len(current.Certificate) == 0 {
+			return nil, fmt.Errorf("証明書が読み込まれていません: %s", m.source)
+		}
+		cert, err := x509.ParseCertificate(current.Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("証明書のパースに失敗しました: %w", err)
+		}
+		return cert, nil
+	}
+
 	// 証明書ファイルの読み込み
 	certData, err := os.ReadFile(m.certFile)
 	if err != nil {
@@ -301,6 +477,15 @@ func (m *Manager) GetCertificateInfo() (*CertificateInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("証明書のパースに失敗しました: %w", err)
 	}
+	return cert, nil
+}
+
+// GetCertificateInfo returns certificate information
+func (m *Manager) GetCertificateInfo() (*CertificateInfo, error) {
+	cert, err := m.servingCertificate()
+	if err != nil {
+		return nil, err
+	}
 	
 	return &CertificateInfo{
 		Subject:    cert.Subject.String(),
@@ -309,6 +494,7 @@ func (m *Manager) GetCertificateInfo() (*CertificateInfo, error) {
 		NotAfter:   cert.NotAfter,
 		DNSNames:   cert.DNSNames,
 		IPAddresses: cert.IPAddresses,
+		PublicKeyAlgorithm: cert.PublicKeyAlgorithm.String(),
 		IsExpired:  time.Now().After(cert.NotAfter),
 		DaysUntilExpiry: int(time.Until(cert.NotAfter).Hours() / 24),
 	}, nil
@@ -380,6 +566,8 @@ type CertificateInfo struct {
 	NotAfter        time.Time
 	DNSNames        []string
 	IPAddresses     []net.IP
+	// PublicKeyAlgorithm 公開鍵のアルゴリズム（RSA、ECDSA、Ed25519）
+	PublicKeyAlgorithm string
 	IsExpired       bool
 	DaysUntilExpiry int
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package cert
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
@@ -430,4 +431,270 @@ func generateExpiringCertificate(certFile, keyFile string, validFor time.Duratio
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+func TestManager_GetCertificate_ConcurrentReload(t *testing.T) {
+	tempDir := t.TempDir()
+	certFile := filepath.Join(tempDir, "tls.crt")
+	keyFile := filepath.Join(tempDir, "tls.key")
+	if err := generateTestCertificate(certFile, keyFile); err != nil {
+		t.Fatalf("テスト証明書の生成に失敗: %v", err)
+	}
+
+	manager := NewManager(certFile, keyFile, "")
+
+	// 読み込み前はハンドシェイク用の証明書を返さない
+	if _, err := manager.GetCertificate(nil); err == nil {
+		t.Error("証明書を読み込む前はエラーになるべきです")
+	}
+	if _, err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("証明書の読み込みに失敗: %v", err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{GetCertificate: manager.GetCertificate})
+	if err != nil {
+		t.Fatalf("TLSリスナーの作成に失敗: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				conn.(*tls.Conn).Handshake()
+			}(conn)
+		}
+	}()
+
+	handshake := func() ([]byte, error) {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Raw, nil
+	}
+
+	// 再読み込み中も並行するハンドシェイクが全て成功すること
+	stop := make(chan struct{})
+	errCh := make(chan error, 8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if _, err := handshake(); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 5; i++ {
+		// 新しい証明書を別の場所で生成してから置き換える
+		stagingDir := t.TempDir()
+		stagedCert := filepath.Join(stagingDir, "tls.crt")
+		stagedKey := filepath.Join(stagingDir, "tls.key")
+		if err := generateTestCertificate(stagedCert, stagedKey); err != nil {
+			t.Fatalf("新しいテスト証明書の生成に失敗: %v", err)
+		}
+		if err := os.Rename(stagedKey, keyFile); err != nil {
+			t.Fatalf("秘密鍵の置き換えに失敗: %v", err)
+		}
+		if err := os.Rename(stagedCert, certFile); err != nil {
+			t.Fatalf("証明書の置き換えに失敗: %v", err)
+		}
+		if _, err := manager.LoadCertificate(); err != nil {
+			t.Fatalf("証明書の再読み込みに失敗: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	close(stop)
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		t.Errorf("再読み込み中のハンドシェイクに失敗: %v", err)
+	}
+
+	// 再読み込み後のハンドシェイクでは新しい証明書が使われること
+	raw, err := handshake()
+	if err != nil {
+		t.Fatalf("ハンドシェイクに失敗: %v", err)
+	}
+	if !bytes.Equal(raw, manager.GetCurrentCertificate().Certificate[0]) {
+		t.Error("再読み込み後の証明書がハンドシェイクで使用されていません")
+	}
+}
+
+// publishSecretVolume kubeletのSecretボリュームと同じ構成で証明書を配置する
+// （<dir>/..<世代>/に実体を置き、..dataシンボリックリンクを差し替える）
+func publishSecretVolume(t *testing.T, dir, generation string, modTime time.Time) {
+	t.Helper()
+
+	genDir := filepath.Join(dir, ".."+generation)
+	if err := os.MkdirAll(genDir, 0755); err != nil {
+		t.Fatalf("世代ディレクトリの作成に失敗: %v", err)
+	}
+	certFile := filepath.Join(genDir, "tls.crt")
+	keyFile := filepath.Join(genDir, "tls.key")
+	if err := generateTestCertificate(certFile, keyFile); err != nil {
+		t.Fatalf("テスト証明書の生成に失敗: %v", err)
+	}
+	// 更新時刻が変わらない場合も検出できることを確認するため、全世代で同じ時刻にする
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatalf("更新時刻の設定に失敗: %v", err)
+		}
+	}
+
+	tmpLink := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(".."+generation, tmpLink); err != nil {
+		t.Fatalf("シンボリックリンクの作成に失敗: %v", err)
+	}
+	if err := os.Rename(tmpLink, filepath.Join(dir, "..data")); err != nil {
+		t.Fatalf("..dataの差し替えに失敗: %v", err)
+	}
+
+	for _, name := range []string{"tls.crt", "tls.key"} {
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); os.IsNotExist(err) {
+			if err := os.Symlink(filepath.Join("..data", name), link); err != nil {
+				t.Fatalf("シンボリックリンクの作成に失敗: %v", err)
+			}
+		}
+	}
+}
+
+func TestManager_ReloadIfChanged_SymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	publishSecretVolume(t, dir, "gen1", modTime)
+
+	manager := NewManager(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), "")
+	if _, err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("証明書の読み込みに失敗: %v", err)
+	}
+	initial := manager.GetCurrentCertificate()
+
+	reloads := 0
+	manager.SetReloadCallback(func(tls.Certificate) { reloads++ })
+
+	// 変更がない場合は再読み込みしない
+	manager.reloadIfChanged()
+	if reloads != 0 || manager.GetCurrentCertificate() != initial {
+		t.Fatal("変更がないのに証明書が再読み込みされました")
+	}
+
+	// ..dataの差し替えは更新時刻が同じでも検出する
+	publishSecretVolume(t, dir, "gen2", modTime)
+	manager.reloadIfChanged()
+	if reloads != 1 {
+		t.Fatalf("再読み込み回数 = %d, 期待値 1", reloads)
+	}
+	if bytes.Equal(manager.GetCurrentCertificate().Certificate[0], initial.Certificate[0]) {
+		t.Error("差し替え後の証明書が読み込まれていません")
+	}
+}
+
+func TestManager_ReloadIfChanged_KeepsPairOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	if err := generateTestCertificate(certFile, keyFile); err != nil {
+		t.Fatalf("テスト証明書の生成に失敗: %v", err)
+	}
+
+	manager := NewManager(certFile, keyFile, "")
+	if _, err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("証明書の読み込みに失敗: %v", err)
+	}
+	initial := manager.GetCurrentCertificate()
+
+	reloads := 0
+	manager.SetReloadCallback(func(tls.Certificate) { reloads++ })
+
+	// 新しい世代の秘密鍵のみが配置された状態（証明書は古い世代）
+	nextDir := t.TempDir()
+	nextCert := filepath.Join(nextDir, "tls.crt")
+	nextKey := filepath.Join(nextDir, "tls.key")
+	if err := generateTestCertificate(nextCert, nextKey); err != nil {
+		t.Fatalf("テスト証明書の生成に失敗: %v", err)
+	}
+	if err := os.Rename(nextKey, keyFile); err != nil {
+		t.Fatalf("秘密鍵の置き換えに失敗: %v", err)
+	}
+
+	// 秘密鍵の変更も検出するが、異なる世代の組は読み込まずに現在の証明書を使い続ける
+	manager.reloadIfChanged()
+	if reloads != 0 {
+		t.Fatal("異なる世代の証明書と秘密鍵の組が読み込まれました")
+	}
+	if manager.GetCurrentCertificate() != initial {
+		t.Fatal("再読み込みの失敗後に現在の証明書が変わりました")
+	}
+	if _, err := manager.GetCertificate(nil); err != nil {
+		t.Fatalf("再読み込みの失敗後も証明書を返すべきです: %v", err)
+	}
+
+	// 証明書も揃った時点で新しい組を読み込む
+	if err := os.Rename(nextCert, certFile); err != nil {
+		t.Fatalf("証明書の置き換えに失敗: %v", err)
+	}
+	manager.reloadIfChanged()
+	if reloads != 1 {
+		t.Fatalf("再読み込み回数 = %d, 期待値 1", reloads)
+	}
+	if manager.GetCurrentCertificate() == initial {
+		t.Error("新しい組が読み込まれていません")
+	}
+}
+
+func TestManager_FileWatch(t *testing.T) {
+	dir := t.TempDir()
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	publishSecretVolume(t, dir, "gen1", modTime)
+
+	if watcher, err := watchFiles(filepath.Join(dir, "tls.crt")); err != nil {
+		t.Skipf("ファイル変更通知に対応していません: %v", err)
+	} else {
+		watcher.Close()
+	}
+
+	manager := NewManager(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), "")
+	if _, err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("証明書の読み込みに失敗: %v", err)
+	}
+
+	reloaded := make(chan struct{}, 1)
+	manager.SetReloadCallback(func(tls.Certificate) {
+		select {
+		case reloaded <- struct{}{}:
+		default:
+		}
+	})
+	manager.SetFileWatch(true)
+
+	// 定期的な確認は行われない間隔で監視し、変更通知のみで再読み込みされることを確認
+	manager.StartMonitoring(time.Hour)
+	defer manager.StopMonitoring()
+	time.Sleep(100 * time.Millisecond)
+
+	publishSecretVolume(t, dir, "gen2", modTime)
+
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("タイムアウト: ファイル変更通知による再読み込みが行われませんでした")
+	}
+}
@@ -0,0 +1,115 @@
+package cert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Secretのキー（cert-managerやkubernetes.io/tls形式のSecretと同じ）
+const (
+	SecretCertKey = corev1.TLSCertKey
+	SecretKeyKey  = corev1.TLSPrivateKeyKey
+	SecretCAKey   = "ca.crt"
+)
+
+const (
+	// secretRequestTimeout Secretの取得のタイムアウト
+	secretRequestTimeout = 10 * time.Second
+
+	// secretWatchRetryInterval Secretのwatchが切断された場合に再接続するまでの待機時間
+	secretWatchRetryInterval = 5 * time.Second
+)
+
+// secretSource 証明書を読み込むKubernetesのSecret
+type secretSource struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewManagerFromSecret creates a certificate manager that reads tls.crt/tls.key/ca.crt from a Secret
+// Secretの変更はwatchで検出し、kubeletのボリューム同期を待たずに再読み込みする
+func NewManagerFromSecret(client kubernetes.Interface, namespace, name string) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		secret: &secretSource{
+			client:    client,
+			namespace: namespace,
+			name:      name,
+		},
+		source:         fmt.Sprintf("secret:%s/%s", namespace, name),
+		monitoringCtx:  ctx,
+		monitoringStop: cancel,
+	}
+}
+
+// readKeyPair Secretから証明書・秘密鍵・CA証明書を読み込む
+// Secretは1つのオブジェクトとして更新されるため、常に同じ世代の組になる
+func (s *secretSource) readKeyPair() (*keyPair, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretRequestTimeout)
+	defer cancel()
+
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Secretの取得に失敗しました (%s/%s): %w", s.namespace, s.name, err)
+	}
+
+	certPEM, ok := secret.Data[SecretCertKey]
+	if !ok || len(certPEM) == 0 {
+		return nil, fmt.Errorf("Secretに%sがありません (%s/%s)", SecretCertKey, s.namespace, s.name)
+	}
+	keyPEM, ok := secret.Data[SecretKeyKey]
+	if !ok || len(keyPEM) == 0 {
+		return nil, fmt.Errorf("Secretに%sがありません (%s/%s)", SecretKeyKey, s.namespace, s.name)
+	}
+
+	return newKeyPair(certPEM, keyPEM, secret.Data[SecretCAKey]), nil
+}
+
+// watch Secretの変更を通知する（ctxが終了するまで切断時は再接続する）
+func (s *secretSource) watch(ctx context.Context, source string) <-chan struct{} {
+	events := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case events <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		for {
+			w, err := s.client.CoreV1().Secrets(s.namespace).Watch(ctx, metav1.ListOptions{
+				FieldSelector: fields.OneTermEqualSelector("metadata.name", s.name).String(),
+			})
+			if err != nil {
+				log.Printf("Secretのwatchに失敗しました (%s/%s): %v", s.namespace, s.name, err)
+				certificateMonitoringErrors.WithLabelValues(source, "watch_failed").Inc()
+			} else {
+				for event := range w.ResultChan() {
+					if event.Type == watch.Added || event.Type == watch.Modified {
+						notify()
+					}
+				}
+				w.Stop()
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(secretWatchRetryInterval):
+				// 切断中の変更を取りこぼさないよう、再接続時にも確認する
+				notify()
+			}
+		}
+	}()
+
+	return events
+}
@@ -0,0 +1,201 @@
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newTLSSecretData 証明書生成関数で作成した証明書をSecretのデータとして返す
+func newTLSSecretData(t *testing.T, generate func(certFile, keyFile string) error) map[string][]byte {
+	t.Helper()
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	if err := generate(certFile, keyFile); err != nil {
+		t.Fatalf("テスト証明書の生成に失敗: %v", err)
+	}
+
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		t.Fatalf("証明書の読み込みに失敗: %v", err)
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		t.Fatalf("秘密鍵の読み込みに失敗: %v", err)
+	}
+	return map[string][]byte{SecretCertKey: certPEM, SecretKeyKey: keyPEM}
+}
+
+// newCASignedSecretData CA署名の証明書とCA証明書をSecretのデータとして返す
+func newCASignedSecretData(t *testing.T) map[string][]byte {
+	t.Helper()
+
+	dir := t.TempDir()
+	caCertFile := filepath.Join(dir, "ca.crt")
+	caKeyFile := filepath.Join(dir, "ca.key")
+	if err := generateCACertificate(caCertFile, caKeyFile); err != nil {
+		t.Fatalf("CA証明書の生成に失敗: %v", err)
+	}
+	data := newTLSSecretData(t, func(certFile, keyFile string) error {
+		return generateServerCertificate(certFile, keyFile, caCertFile, caKeyFile)
+	})
+
+	caPEM, err := os.ReadFile(caCertFile)
+	if err != nil {
+		t.Fatalf("CA証明書の読み込みに失敗: %v", err)
+	}
+	data[SecretCAKey] = caPEM
+	return data
+}
+
+func newTLSSecret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-tls", Namespace: "webhook-system"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       data,
+	}
+}
+
+func TestManagerFromSecret_LoadCertificate(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        func(t *testing.T) map[string][]byte
+		create      bool
+		expectError bool
+	}{
+		{
+			name:   "CA署名の証明書",
+			data:   newCASignedSecretData,
+			create: true,
+		},
+		{
+			name: "ca.crtなし",
+			data: func(t *testing.T) map[string][]byte {
+				return newTLSSecretData(t, generateTestCertificate)
+			},
+			create: true,
+		},
+		{
+			name: "tls.keyなし",
+			data: func(t *testing.T) map[string][]byte {
+				data := newTLSSecretData(t, generateTestCertificate)
+				delete(data, SecretKeyKey)
+				return data
+			},
+			create:      true,
+			expectError: true,
+		},
+		{
+			name: "期限切れの証明書",
+			data: func(t *testing.T) map[string][]byte {
+				return newTLSSecretData(t, generateExpiredCertificate)
+			},
+			create:      true,
+			expectError: true,
+		},
+		{
+			name:        "Secretが存在しない",
+			data:        func(t *testing.T) map[string][]byte { return nil },
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			if tt.create {
+				if _, err := client.CoreV1().Secrets("webhook-system").Create(
+					context.Background(), newTLSSecret(tt.data(t)), metav1.CreateOptions{}); err != nil {
+					t.Fatalf("Secretの作成に失敗: %v", err)
+				}
+			}
+
+			manager := NewManagerFromSecret(client, "webhook-system", "webhook-tls")
+			_, err := manager.LoadCertificate()
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("エラーが期待されましたが、エラーが発生しませんでした")
+				}
+				if manager.GetCurrentCertificate() != nil {
+					t.Error("読み込みに失敗した証明書が使用されています")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("証明書の読み込みに失敗: %v", err)
+			}
+
+			if _, err := manager.GetCertificateInfo(); err != nil {
+				t.Errorf("証明書情報の取得に失敗: %v", err)
+			}
+			if err := manager.ValidateCertificateChain(); err != nil {
+				t.Errorf("証明書チェーンの検証に失敗: %v", err)
+			}
+		})
+	}
+}
+
+func TestManagerFromSecret_WatchReload(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	secrets := client.CoreV1().Secrets("webhook-system")
+	if _, err := secrets.Create(context.Background(),
+		newTLSSecret(newTLSSecretData(t, generateTestCertificate)), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Secretの作成に失敗: %v", err)
+	}
+
+	manager := NewManagerFromSecret(client, "webhook-system", "webhook-tls")
+	if _, err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("証明書の読み込みに失敗: %v", err)
+	}
+	initial := manager.GetCurrentCertificate()
+
+	reloaded := make(chan tls.Certificate, 1)
+	manager.SetReloadCallback(func(cert tls.Certificate) {
+		reloaded <- cert
+	})
+
+	// 定期的な確認は行われない間隔で監視し、watchのみで再読み込みされることを確認
+	manager.StartMonitoring(time.Hour)
+	defer manager.StopMonitoring()
+	time.Sleep(100 * time.Millisecond)
+
+	// 秘密鍵が一致しない更新は読み込まず、現在の証明書を使い続ける
+	mismatched := newTLSSecretData(t, generateTestCertificate)
+	mismatched[SecretKeyKey] = newTLSSecretData(t, generateTestCertificate)[SecretKeyKey]
+	if _, err := secrets.Update(context.Background(), newTLSSecret(mismatched), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Secretの更新に失敗: %v", err)
+	}
+	select {
+	case <-reloaded:
+		t.Fatal("秘密鍵が一致しない証明書が読み込まれました")
+	case <-time.After(500 * time.Millisecond):
+	}
+	if manager.GetCurrentCertificate() != initial {
+		t.Fatal("再読み込みの失敗後に現在の証明書が変わりました")
+	}
+
+	// 正しい組への更新はwatchイベントで即座に読み込む
+	rotated := newTLSSecretData(t, generateTestCertificate)
+	if _, err := secrets.Update(context.Background(), newTLSSecret(rotated), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Secretの更新に失敗: %v", err)
+	}
+	select {
+	case cert := <-reloaded:
+		current := manager.GetCurrentCertificate()
+		if !bytes.Equal(cert.Certificate[0], current.Certificate[0]) || bytes.Equal(current.Certificate[0], initial.Certificate[0]) {
+			t.Error("更新後の証明書が読み込まれていません")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("タイムアウト: Secretの更新による再読み込みが行われませんでした")
+	}
+}
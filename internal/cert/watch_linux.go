@@ -0,0 +1,74 @@
+//go:build linux
+
+package cert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// inotifyMask 証明書のディレクトリで監視するイベント
+// ファイルの書き換えに加え、kubeletによる..dataシンボリックリンクの差し替え（作成・移動）を対象とする
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE |
+	syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM | syscall.IN_ATTRIB
+
+// fileWatcher inotifyによる証明書ファイルの変更通知
+type fileWatcher struct {
+	file   *os.File
+	events chan struct{}
+}
+
+// watchFiles 指定されたファイルを含むディレクトリの変更通知を開始
+func watchFiles(files ...string) (*fileWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotifyの初期化に失敗しました: %w", err)
+	}
+
+	watched := map[string]bool{}
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if watched[dir] {
+			continue
+		}
+		if _, err := syscall.InotifyAddWatch(fd, dir, inotifyMask); err != nil {
+			syscall.Close(fd)
+			return nil, fmt.Errorf("ディレクトリの監視に失敗しました (%s): %w", dir, err)
+		}
+		watched[dir] = true
+	}
+
+	// ノンブロッキングのfdはランタイムのポーラーに登録され、Closeで読み込みが中断される
+	w := &fileWatcher{
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan struct{}, 1),
+	}
+	go w.run()
+	return w, nil
+}
+
+// run inotifyのイベントを読み込み、変更を通知する（イベントの内容は問わない）
+func (w *fileWatcher) run() {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		if _, err := w.file.Read(buf); err != nil {
+			return
+		}
+		select {
+		case w.events <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Events 変更通知のチャネル
+func (w *fileWatcher) Events() <-chan struct{} {
+	return w.events
+}
+
+// Close 変更通知を停止
+func (w *fileWatcher) Close() error {
+	return w.file.Close()
+}
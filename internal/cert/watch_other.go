@@ -0,0 +1,23 @@
+//go:build !linux
+
+package cert
+
+import "errors"
+
+// fileWatcher ファイル変更通知（このプラットフォームでは未対応）
+type fileWatcher struct{}
+
+// watchFiles このプラットフォームではファイル変更通知に対応していないため、常にエラーを返す
+func watchFiles(files ...string) (*fileWatcher, error) {
+	return nil, errors.New("このプラットフォームはファイル変更通知に対応していません")
+}
+
+// Events 変更通知のチャネル
+func (w *fileWatcher) Events() <-chan struct{} {
+	return nil
+}
+
+// Close 変更通知を停止
+func (w *fileWatcher) Close() error {
+	return nil
+}
@@ -0,0 +1,210 @@
+// Package cloudevents はAdmissionRequestの判定をCloudEvents（v1.0）としてHTTPで送信する
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s-deployment-hpa-validator/internal/asyncqueue"
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/decisionlog"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+)
+
+const (
+	// SpecVersion 準拠するCloudEventsの仕様のバージョン
+	SpecVersion = "1.0"
+	// Source イベントの発生元（ClusterNameが設定されている場合は "/<クラスター名>" を付与する）
+	Source = "k8s-deployment-hpa-validator"
+	// TypePrefix イベントの種類の接頭辞
+	// 種類は "<接頭辞>.<denied|allowed>[.<エラーコード>]" とする（例: io.k8s-deployment-hpa-validator.admission.denied.VALIDATION_DEPLOYMENT_HPA_CONFLICT）
+	TypePrefix = "io.k8s-deployment-hpa-validator.admission"
+
+	// contentTypeJSON binaryモードのボディ（判定）の形式
+	contentTypeJSON = "application/json"
+	// contentTypeStructured structuredモードのボディ（属性と判定）の形式
+	contentTypeStructured = "application/cloudevents+json"
+
+	// sendTimeout 1件の送信の期限
+	sendTimeout = 10 * time.Second
+	// flushTimeout 停止時にバッファのCloudEventを送信する期限（超えた分は送信を中止する）
+	flushTimeout = 30 * time.Second
+)
+
+// Event 1件の判定のCloudEvent
+type Event struct {
+	SpecVersion     string            `json:"specversion"`
+	ID              string            `json:"id"`
+	Source          string            `json:"source"`
+	Type            string            `json:"type"`
+	Subject         string            `json:"subject,omitempty"`
+	Time            time.Time         `json:"time"`
+	DataContentType string            `json:"datacontenttype"`
+	Data            decisionlog.Entry `json:"data"`
+}
+
+// NewEvent 決定ログの1件からCloudEventを作成
+// IDはAdmissionRequestのUIDと適用モードから作成する（/validateと/mutateで同じUIDのリクエストを区別するため）
+func NewEvent(entry decisionlog.Entry, source string) Event {
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              entry.UID + "/" + entry.EnforcementMode,
+		Source:          source,
+		Type:            EventType(entry),
+		Subject:         fmt.Sprintf("%s/%s/%s", entry.Namespace, entry.Kind, entry.Name),
+		Time:            entry.Timestamp,
+		DataContentType: contentTypeJSON,
+		Data:            entry,
+	}
+}
+
+// EventType 判定と理由のエラーコード（違反したルールを優先）からイベントの種類を決定
+func EventType(entry decisionlog.Entry) string {
+	eventType := TypePrefix + ".allowed"
+	if entry.Decision == decisionlog.DecisionDeny {
+		eventType = TypePrefix + ".denied"
+	}
+	switch {
+	case len(entry.Rules) > 0:
+		eventType += "." + entry.Rules[0]
+	case len(entry.Codes) > 0:
+		eventType += "." + entry.Codes[0]
+	}
+	return eventType
+}
+
+// Emitter 判定をバッファに積み、別のgoroutineでCloudEventとして送信する
+// バッファが一杯のため破棄したCloudEventは webhook_cloudevents_dropped_total に記録する
+type Emitter struct {
+	queue        *asyncqueue.Queue[Event]
+	sinkURL      string
+	mode         string
+	allDecisions bool
+	source       string
+	httpClient   *http.Client
+	logger       *logging.Logger
+
+	// ctx 停止時の期限を超えた場合に送信を中止する
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewEmitter 設定された送信先にCloudEventを送信するEmitterを作成し、送信を開始する
+func NewEmitter(cfg *config.WebhookConfig, logger *logging.Logger) *Emitter {
+	source := Source
+	if cfg.ClusterName != "" {
+		source += "/" + cfg.ClusterName
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &Emitter{
+		queue:        newEventQueue(cfg.CloudEventsBufferSize),
+		sinkURL:      cfg.CloudEventsSinkURL,
+		mode:         cfg.CloudEventsMode,
+		allDecisions: cfg.CloudEventsAllDecisions,
+		source:       source,
+		httpClient:   &http.Client{Timeout: sendTimeout},
+		logger:       logger,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	e.queue.Start(e.run)
+	return e
+}
+
+// newEventQueue 送信を待つCloudEventのバッファを作成
+func newEventQueue(bufferSize int) *asyncqueue.Queue[Event] {
+	return asyncqueue.New[Event](bufferSize, metrics.RecordCloudEventDropped)
+}
+
+// Emit 判定を送信のバッファに追加（ブロックしない）
+// 許可した判定はCloudEventsAllDecisionsが有効な場合のみ送信する
+func (e *Emitter) Emit(entry decisionlog.Entry) {
+	if entry.Decision != decisionlog.DecisionDeny && !e.allDecisions {
+		return
+	}
+	e.queue.Enqueue(NewEvent(entry, e.source))
+}
+
+// run バッファのCloudEventを順に送信する
+func (e *Emitter) run(events <-chan Event) {
+	for event := range events {
+		err := e.send(e.ctx, event)
+		metrics.RecordCloudEvent(err == nil)
+		if err != nil {
+			e.logger.Warn("CloudEventの送信に失敗しました", map[string]interface{}{
+				"id":    event.ID,
+				"type":  event.Type,
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// send 送信モードに従い1件のCloudEventを送信
+func (e *Emitter) send(ctx context.Context, event Event) error {
+	req, err := e.newRequest(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("CloudEvents sink returned %s", resp.Status)
+	}
+	return nil
+}
+
+// newRequest CloudEventのHTTPリクエストを作成
+// binaryモードは属性をce-*ヘッダー、判定をボディとし、structuredモードは属性と判定を1つのJSONとする
+func (e *Emitter) newRequest(ctx context.Context, event Event) (*http.Request, error) {
+	var body []byte
+	var err error
+	if e.mode == config.CloudEventsModeStructured {
+		body, err = json.Marshal(event)
+	} else {
+		body, err = json.Marshal(event.Data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal CloudEvent: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.sinkURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CloudEvent request: %w", err)
+	}
+	if e.mode == config.CloudEventsModeStructured {
+		req.Header.Set("Content-Type", contentTypeStructured)
+		return req, nil
+	}
+
+	req.Header.Set("Content-Type", event.DataContentType)
+	req.Header.Set("ce-specversion", event.SpecVersion)
+	req.Header.Set("ce-id", event.ID)
+	req.Header.Set("ce-source", event.Source)
+	req.Header.Set("ce-type", event.Type)
+	if event.Subject != "" {
+		req.Header.Set("ce-subject", event.Subject)
+	}
+	req.Header.Set("ce-time", event.Time.Format(time.RFC3339Nano))
+	return req, nil
+}
+
+// Close バッファのCloudEventをすべて送信してから停止する（期限を超えた場合は残りの送信を中止する）
+func (e *Emitter) Close() {
+	timer := time.AfterFunc(flushTimeout, e.cancel)
+	defer timer.Stop()
+	e.queue.Close()
+	e.cancel()
+}
@@ -0,0 +1,215 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/decisionlog"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+)
+
+func init() {
+	// テストモードを有効にしてメトリクスの重複登録を防ぐ
+	metrics.EnableTestMode()
+}
+
+// receivedEvent 受信したCloudEventのリクエスト
+type receivedEvent struct {
+	header http.Header
+	body   []byte
+}
+
+// eventReceiver 受信したCloudEventを記録するテスト用の送信先
+type eventReceiver struct {
+	*httptest.Server
+	mu     sync.Mutex
+	events []receivedEvent
+	status int
+}
+
+func newEventReceiver(t *testing.T, status int) *eventReceiver {
+	r := &eventReceiver{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.events = append(r.events, receivedEvent{header: req.Header.Clone(), body: body})
+		r.mu.Unlock()
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *eventReceiver) received() []receivedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedEvent(nil), r.events...)
+}
+
+// newTestEmitter テスト用の送信先にCloudEventを送信するEmitter
+func newTestEmitter(sinkURL, mode string, allDecisions bool) *Emitter {
+	return NewEmitter(&config.WebhookConfig{
+		CloudEventsSinkURL:      sinkURL,
+		CloudEventsMode:         mode,
+		CloudEventsAllDecisions: allDecisions,
+		CloudEventsBufferSize:   16,
+		ClusterName:             "test-cluster",
+	}, logging.NewLogger("test-cloudevents"))
+}
+
+var (
+	denyEntry = decisionlog.Entry{
+		Timestamp:       time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC),
+		UID:             "uid-1",
+		Kind:            "Deployment",
+		Name:            "web",
+		Namespace:       "team-a",
+		Operation:       "CREATE",
+		Decision:        decisionlog.DecisionDeny,
+		EnforcementMode: decisionlog.ModeEnforce,
+		Rules:           []string{"VALIDATION_DEPLOYMENT_HPA_CONFLICT"},
+		Codes:           []string{"VALIDATION_DEPLOYMENT_HPA_CONFLICT"},
+	}
+	allowEntry = decisionlog.Entry{
+		UID:             "uid-2",
+		Kind:            "HorizontalPodAutoscaler",
+		Name:            "web-hpa",
+		Namespace:       "team-a",
+		Decision:        decisionlog.DecisionAllow,
+		EnforcementMode: decisionlog.ModeEnforce,
+	}
+)
+
+func TestEventType(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    decisionlog.Entry
+		expected string
+	}{
+		{name: "ルールの違反による拒否", entry: denyEntry, expected: "io.k8s-deployment-hpa-validator.admission.denied.VALIDATION_DEPLOYMENT_HPA_CONFLICT"},
+		{name: "内部エラーによる拒否", entry: decisionlog.Entry{Decision: decisionlog.DecisionDeny, Codes: []string{"API_TIMEOUT"}}, expected: "io.k8s-deployment-hpa-validator.admission.denied.API_TIMEOUT"},
+		{name: "違反のない許可", entry: allowEntry, expected: "io.k8s-deployment-hpa-validator.admission.allowed"},
+		{name: "検証の対象外による許可", entry: decisionlog.Entry{Decision: decisionlog.DecisionAllow, EnforcementMode: decisionlog.ModeExempt, Rules: []string{"VALIDATION_HPA_SINGLE_REPLICA"}, Codes: []string{"VALIDATION_HPA_SINGLE_REPLICA"}}, expected: "io.k8s-deployment-hpa-validator.admission.allowed.VALIDATION_HPA_SINGLE_REPLICA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EventType(tt.entry); got != tt.expected {
+				t.Errorf("EventType() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEmitter_Binary(t *testing.T) {
+	receiver := newEventReceiver(t, http.StatusAccepted)
+	sent := metrics.WebhookCloudEventsTotal.WithLabelValues("sent")
+	before := testutil.ToFloat64(sent)
+
+	emitter := newTestEmitter(receiver.URL, config.CloudEventsModeBinary, false)
+	emitter.Emit(denyEntry)
+	// 許可した判定はCloudEventsAllDecisionsが無効な場合は送信しない
+	emitter.Emit(allowEntry)
+	emitter.Close()
+
+	events := receiver.received()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	expectedHeaders := map[string]string{
+		"Content-Type":   "application/json",
+		"Ce-Specversion": "1.0",
+		"Ce-Id":          "uid-1/enforce",
+		"Ce-Source":      "k8s-deployment-hpa-validator/test-cluster",
+		"Ce-Type":        "io.k8s-deployment-hpa-validator.admission.denied.VALIDATION_DEPLOYMENT_HPA_CONFLICT",
+		"Ce-Subject":     "team-a/Deployment/web",
+		"Ce-Time":        "2026-10-18T14:00:00Z",
+	}
+	for name, expected := range expectedHeaders {
+		if got := events[0].header.Get(name); got != expected {
+			t.Errorf("%s = %q, expected %q", name, got, expected)
+		}
+	}
+	var data decisionlog.Entry
+	if err := json.Unmarshal(events[0].body, &data); err != nil {
+		t.Fatalf("Failed to unmarshal data: %v", err)
+	}
+	if data.UID != "uid-1" || data.Decision != decisionlog.DecisionDeny || len(data.Rules) != 1 {
+		t.Errorf("data = %+v, expected the decision log entry", data)
+	}
+	if got := testutil.ToFloat64(sent) - before; got != 1 {
+		t.Errorf("webhook_cloudevents_total{result=\"sent\"} increased by %v, expected 1", got)
+	}
+}
+
+func TestEmitter_Structured(t *testing.T) {
+	receiver := newEventReceiver(t, http.StatusOK)
+	emitter := newTestEmitter(receiver.URL, config.CloudEventsModeStructured, true)
+	emitter.Emit(denyEntry)
+	emitter.Emit(allowEntry)
+	emitter.Close()
+
+	events := receiver.received()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if got := events[0].header.Get("Content-Type"); got != "application/cloudevents+json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := events[0].header.Get("Ce-Id"); got != "" {
+		t.Errorf("Expected no ce-* headers in structured mode, got ce-id %q", got)
+	}
+	var event Event
+	if err := json.Unmarshal(events[1].body, &event); err != nil {
+		t.Fatalf("Failed to unmarshal event: %v", err)
+	}
+	if event.SpecVersion != "1.0" || event.ID != "uid-2/enforce" || event.Type != "io.k8s-deployment-hpa-validator.admission.allowed" ||
+		event.DataContentType != "application/json" || event.Data.Name != "web-hpa" || event.Data.Decision != decisionlog.DecisionAllow {
+		t.Errorf("event = %+v", event)
+	}
+}
+
+func TestEmitter_Failed(t *testing.T) {
+	receiver := newEventReceiver(t, http.StatusServiceUnavailable)
+	failed := metrics.WebhookCloudEventsTotal.WithLabelValues("failed")
+	before := testutil.ToFloat64(failed)
+
+	emitter := newTestEmitter(receiver.URL, config.CloudEventsModeBinary, false)
+	emitter.Emit(denyEntry)
+	emitter.Close()
+
+	if got := testutil.ToFloat64(failed) - before; got != 1 {
+		t.Errorf("webhook_cloudevents_total{result=\"failed\"} increased by %v, expected 1", got)
+	}
+}
+
+func TestEmitter_DropsWhenBufferFull(t *testing.T) {
+	// 送信用のgoroutineを開始せず、バッファが一杯の状態を再現する
+	emitter := &Emitter{queue: newEventQueue(1)}
+	before := testutil.ToFloat64(metrics.WebhookCloudEventsDropped)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		emitter.Emit(denyEntry)
+		emitter.Emit(denyEntry)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Emit() blocked on a full buffer")
+	}
+
+	if got := testutil.ToFloat64(metrics.WebhookCloudEventsDropped) - before; got != 1 {
+		t.Errorf("webhook_cloudevents_dropped_total increased by %v, expected 1", got)
+	}
+}
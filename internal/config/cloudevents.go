@@ -0,0 +1,18 @@
+package config
+
+const (
+	// CloudEventsModeBinary 属性をce-*ヘッダー、判定をボディとして送信する（HTTP binary content mode）
+	CloudEventsModeBinary = "binary"
+	// CloudEventsModeStructured 属性と判定を1つのJSON（application/cloudevents+json）として送信する（HTTP structured content mode）
+	CloudEventsModeStructured = "structured"
+)
+
+// IsValidCloudEventsMode CloudEventsの送信モードが有効かどうか
+func IsValidCloudEventsMode(mode string) bool {
+	return mode == CloudEventsModeBinary || mode == CloudEventsModeStructured
+}
+
+// ValidateCloudEventsSinkURL CloudEventsの送信先のURLがhttpまたはhttpsの絶対URLかどうかを検証
+func ValidateCloudEventsSinkURL(rawURL string) error {
+	return validateHTTPURL("CloudEventsの送信先", rawURL)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -11,14 +12,66 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+// 証明書の読み込み元
+const (
+	// TLSSourceFile 証明書ファイル（TLSCertFile、TLSKeyFile、TLSCAFile）から読み込む
+	TLSSourceFile = "file"
+	// TLSSourceSecret KubernetesのSecret（tls.crt、tls.key、ca.crt）からAPI経由で読み込む
+	TLSSourceSecret = "secret"
+)
+
+// DefaultMaxRequestBodyBytes リクエストボディの最大サイズの既定値（7MiB）
+// Kubernetesのオブジェクトの上限（約3MiB）の新旧オブジェクトを含むAdmissionReviewを受け付けられる大きさ
+const DefaultMaxRequestBodyBytes int64 = 7 << 20
+
 // WebhookConfig webhook設定構造体
 type WebhookConfig struct {
 	// サーバー設定
 	Port        int           `yaml:"port" env:"WEBHOOK_PORT" default:"8443"`
 	TLSCertFile string        `yaml:"tls_cert_file" env:"TLS_CERT_FILE" default:"/etc/certs/tls.crt"`
 	TLSKeyFile  string        `yaml:"tls_key_file" env:"TLS_KEY_FILE" default:"/etc/certs/tls.key"`
+	TLSCAFile   string        `yaml:"tls_ca_file" env:"TLS_CA_FILE"`
 	Timeout     time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT" default:"10s"`
 
+	// HTTPサーバーのタイムアウト（0は無制限）
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"WEBHOOK_READ_HEADER_TIMEOUT" default:"5s"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"WEBHOOK_READ_TIMEOUT" default:"10s"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"WEBHOOK_WRITE_TIMEOUT" default:"15s"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"WEBHOOK_IDLE_TIMEOUT" default:"60s"`
+
+	// MaxRequestBodyBytes AdmissionReviewのリクエストボディの最大サイズ（バイト）
+	MaxRequestBodyBytes int64 `yaml:"max_request_body_bytes" env:"WEBHOOK_MAX_REQUEST_BODY_BYTES" default:"7340032"`
+
+	// 証明書の読み込み元（file: ファイル、secret: KubernetesのSecret）
+	TLSSource          string `yaml:"tls_source" env:"TLS_SOURCE" default:"file"`
+	TLSSecretName      string `yaml:"tls_secret_name" env:"TLS_SECRET_NAME"`
+	TLSSecretNamespace string `yaml:"tls_secret_namespace" env:"TLS_SECRET_NAMESPACE"`
+
+	// TLSプロファイル（modern、intermediate、custom）。customの場合のみ最小バージョン・暗号スイート・楕円曲線を指定できる
+	TLSProfile          string   `yaml:"tls_profile" env:"TLS_PROFILE" default:"intermediate"`
+	TLSMinVersion       string   `yaml:"tls_min_version" env:"TLS_MIN_VERSION"`
+	TLSCipherSuites     []string `yaml:"tls_cipher_suites" env:"TLS_CIPHER_SUITES"`
+	TLSCurvePreferences []string `yaml:"tls_curve_preferences" env:"TLS_CURVE_PREFERENCES"`
+
+	// クライアント証明書による呼び出し元の認証（mTLS）。ClientAllowedNamesが空の場合はCAで検証できる全ての証明書を許可する
+	ClientAuthEnabled  bool     `yaml:"client_auth_enabled" env:"CLIENT_AUTH_ENABLED" default:"false"`
+	ClientCAFile       string   `yaml:"client_ca_file" env:"CLIENT_CA_FILE"`
+	ClientAllowedNames []string `yaml:"client_allowed_names" env:"CLIENT_ALLOWED_NAMES"`
+
+	// 証明書の監視設定
+	CertCheckInterval time.Duration `yaml:"cert_check_interval" env:"CERT_CHECK_INTERVAL" default:"1m"`
+	CertWatchEnabled  bool          `yaml:"cert_watch_enabled" env:"CERT_WATCH_ENABLED" default:"false"`
+
+	// 自己署名CAによる証明書の自動発行（tls_source が secret の場合のみ）
+	CertBootstrap        bool   `yaml:"cert_bootstrap" env:"CERT_BOOTSTRAP" default:"false"`
+	CertBootstrapService string `yaml:"cert_bootstrap_service" env:"CERT_BOOTSTRAP_SERVICE"`
+
+	// WebhookConfigurationName このwebhookを登録したValidatingWebhookConfiguration（と同名のMutatingWebhookConfiguration）の名前
+	WebhookConfigurationName string `yaml:"webhook_configuration_name" env:"WEBHOOK_CONFIGURATION_NAME"`
+
+	// CABundleCheckInterval webhook設定のcaBundleでサーバー証明書を検証できるかを確認する間隔（WebhookConfigurationNameが設定されている場合のみ）
+	CABundleCheckInterval time.Duration `yaml:"ca_bundle_check_interval" env:"CA_BUNDLE_CHECK_INTERVAL" default:"5m"`
+
 	// ログ設定
 	LogLevel  string `yaml:"log_level" env:"LOG_LEVEL" default:"info"`
 	LogFormat string `yaml:"log_format" env:"LOG_FORMAT" default:"json"`
@@ -26,11 +79,82 @@ type WebhookConfig struct {
 	// バリデーション設定
 	SkipNamespaces []string `yaml:"skip_namespaces" env:"SKIP_NAMESPACES"`
 	SkipLabels     []string `yaml:"skip_labels" env:"SKIP_LABELS"`
+	MinReplicas    int      `yaml:"min_replicas" env:"MIN_REPLICAS" default:"2"`
+
+	// 検証の対象外とする呼び出し元（globパターン）。違反しても警告付きで許可し、監査アノテーションに記録する
+	ExemptUsers  []string `yaml:"exempt_users" env:"EXEMPT_USERS"`
+	ExemptGroups []string `yaml:"exempt_groups" env:"EXEMPT_GROUPS"`
+	// ExemptServiceAccounts "<namespace>:<name>" 形式のサービスアカウント
+	ExemptServiceAccounts []string `yaml:"exempt_service_accounts" env:"EXEMPT_SERVICE_ACCOUNTS"`
+
+	// ミューテーション設定（違反を拒否せずに自動修正する）
+	MutationEnabled    bool     `yaml:"mutation_enabled" env:"MUTATION_ENABLED" default:"false"`
+	MutationNamespaces []string `yaml:"mutation_namespaces" env:"MUTATION_NAMESPACES"`
+
+	// メッセージ設定（namespaceのアノテーションで上書き可能）
+	Language string `yaml:"language" env:"WEBHOOK_LANGUAGE" default:"ja"`
+	// MessageTemplates エラーコードごとの拒否メッセージのテンプレート（text/template、"*"は全エラーコード）
+	MessageTemplates map[string]string `yaml:"message_templates"`
 
 	// 監視設定
 	MetricsEnabled bool `yaml:"metrics_enabled" env:"METRICS_ENABLED" default:"true"`
 	MetricsPort    int  `yaml:"metrics_port" env:"METRICS_PORT" default:"8080"`
 	HealthEnabled  bool `yaml:"health_enabled" env:"HEALTH_ENABLED" default:"true"`
+	// MetricsRequesterNamespace 拒否のメトリクスに呼び出し元のサービスアカウントのnamespaceのラベルを付与する
+	// ラベルの値はMetricsRequesterNamespaceLimit種類までとし、超えた分は"other"にまとめる
+	MetricsRequesterNamespace      bool `yaml:"metrics_requester_namespace" env:"METRICS_REQUESTER_NAMESPACE" default:"false"`
+	MetricsRequesterNamespaceLimit int  `yaml:"metrics_requester_namespace_limit" env:"METRICS_REQUESTER_NAMESPACE_LIMIT" default:"50"`
+	// EventsEnabled 拒否や警告付きの許可をKubernetesのWarning Eventとして記録する
+	EventsEnabled bool `yaml:"events_enabled" env:"EVENTS_ENABLED" default:"false"`
+
+	// 監査設定
+	// AuditEnabled webhookの導入前から存在するDeploymentとHPAの違反を定期的に検出する
+	AuditEnabled  bool          `yaml:"audit_enabled" env:"AUDIT_ENABLED" default:"false"`
+	AuditInterval time.Duration `yaml:"audit_interval" env:"AUDIT_INTERVAL" default:"10m"`
+	// PolicyReportEnabled 監査スキャンの結果をnamespaceごとのPolicyReport（wgpolicyk8s.io）に書き込む
+	PolicyReportEnabled bool `yaml:"policy_report_enabled" env:"POLICY_REPORT_ENABLED" default:"false"`
+	// LeaderElectionNamespace PolicyReportを書き込むレプリカを選出するLeaseのnamespace（空の場合はPodのnamespace）
+	LeaderElectionNamespace string `yaml:"leader_election_namespace" env:"LEADER_ELECTION_NAMESPACE"`
+
+	// 決定ログ設定
+	// DecisionLogEnabled リクエストごとの判定を1行1オブジェクトのJSON（JSON Lines）でファイルに記録する
+	DecisionLogEnabled bool   `yaml:"decision_log_enabled" env:"DECISION_LOG_ENABLED" default:"false"`
+	DecisionLogPath    string `yaml:"decision_log_path" env:"DECISION_LOG_PATH" default:"/var/log/webhook/decisions.jsonl"`
+	// DecisionLogMaxSizeMB・DecisionLogMaxAge ファイルのサイズ（MB）または書き込みを開始してからの経過時間を超えたらローテーションする
+	DecisionLogMaxSizeMB int           `yaml:"decision_log_max_size_mb" env:"DECISION_LOG_MAX_SIZE_MB" default:"100"`
+	DecisionLogMaxAge    time.Duration `yaml:"decision_log_max_age" env:"DECISION_LOG_MAX_AGE" default:"24h"`
+	// DecisionLogMaxBackups 保持するローテーション済み（gzip圧縮）のファイル数（0の場合は削除しない）
+	DecisionLogMaxBackups int `yaml:"decision_log_max_backups" env:"DECISION_LOG_MAX_BACKUPS" default:"5"`
+	// DecisionLogBufferSize ファイルへの書き込みを待つ判定の上限（ディスクの書き込みが遅い間に受け付けた判定を保持できる件数）
+	DecisionLogBufferSize int `yaml:"decision_log_buffer_size" env:"DECISION_LOG_BUFFER_SIZE" default:"1024"`
+
+	// 通知設定
+	// NotificationsEnabled 拒否の概要をHTTPエンドポイントに通知する
+	// 通知先はnamespaceのアノテーションで指定し、指定がない場合はNotificationURLに通知する（空の場合は通知しない）
+	NotificationsEnabled bool   `yaml:"notifications_enabled" env:"NOTIFICATIONS_ENABLED" default:"false"`
+	NotificationURL      string `yaml:"notification_url" env:"NOTIFICATION_URL"`
+	// NotificationFormat 通知の形式（json、slack）
+	NotificationFormat string `yaml:"notification_format" env:"NOTIFICATION_FORMAT" default:"json"`
+	// NotificationBatchWindow 拒否をまとめて通知する間隔（同じリソース・エラーコードの拒否は件数にまとめる）
+	NotificationBatchWindow time.Duration `yaml:"notification_batch_window" env:"NOTIFICATION_BATCH_WINDOW" default:"30s"`
+	// NotificationQueueSize 次の通知までに保持する拒否の上限（NotificationBatchWindowの間に受け付ける拒否の件数の目安）
+	NotificationQueueSize int `yaml:"notification_queue_size" env:"NOTIFICATION_QUEUE_SIZE" default:"256"`
+	// NotificationAllowedURLPrefixes namespaceのアノテーションで指定できる通知先のURLの接頭辞
+	// namespaceの利用者が任意の宛先（クラスター内部のサービスなど）へ送信させないよう、一致しないURLは使用しない（空の場合はアノテーションの通知先を使用しない）
+	NotificationAllowedURLPrefixes []string `yaml:"notification_allowed_url_prefixes" env:"NOTIFICATION_ALLOWED_URL_PREFIXES"`
+
+	// CloudEvents設定
+	// CloudEventsEnabled 拒否（CloudEventsAllDecisionsの場合はすべての判定）をCloudEventとしてCloudEventsSinkURLに送信する
+	CloudEventsEnabled bool   `yaml:"cloudevents_enabled" env:"CLOUDEVENTS_ENABLED" default:"false"`
+	CloudEventsSinkURL string `yaml:"cloudevents_sink_url" env:"CLOUDEVENTS_SINK_URL"`
+	// CloudEventsMode HTTPの送信モード（binary、structured）
+	CloudEventsMode         string `yaml:"cloudevents_mode" env:"CLOUDEVENTS_MODE" default:"binary"`
+	CloudEventsAllDecisions bool   `yaml:"cloudevents_all_decisions" env:"CLOUDEVENTS_ALL_DECISIONS" default:"false"`
+	// CloudEventsBufferSize 送信先への送信を待つCloudEventの上限（1件ずつ送信するため、送信先の応答が遅い間に溜まる件数）
+	CloudEventsBufferSize int `yaml:"cloudevents_buffer_size" env:"CLOUDEVENTS_BUFFER_SIZE" default:"1024"`
+
+	// Kubernetes API設定（空の場合はクラスター内設定、KUBECONFIG、~/.kube/configの順に試行）
+	Kubeconfig string `yaml:"kubeconfig" env:"KUBECONFIG"`
 
 	// 環境情報
 	Environment string `yaml:"environment" env:"ENVIRONMENT" default:"development"`
@@ -44,6 +168,7 @@ type WebhookConfig struct {
 type ConfigLoader struct {
 	configMapData map[string]string
 	configFile    string
+	overrides     func(*WebhookConfig) error
 }
 
 // NewConfigLoader 新しい設定ローダーを作成
@@ -76,6 +201,16 @@ func NewConfigLoaderWithFileAndConfigMap(configFile string, configMapData map[st
 	}
 }
 
+// NewConfigLoaderWithOverrides 設定ファイルとコマンドライン引数による上書きを使用して設定ローダーを作成
+// overridesは環境変数の読み込み後、検証の前に適用される（最優先）
+func NewConfigLoaderWithOverrides(configFile string, overrides func(*WebhookConfig) error) *ConfigLoader {
+	return &ConfigLoader{
+		configMapData: make(map[string]string),
+		configFile:    configFile,
+		overrides:     overrides,
+	}
+}
+
 // LoadConfig 設定を読み込み
 func (cl *ConfigLoader) LoadConfig() (*WebhookConfig, error) {
 	config := &WebhookConfig{}
@@ -100,6 +235,13 @@ func (cl *ConfigLoader) LoadConfig() (*WebhookConfig, error) {
 		return nil, fmt.Errorf("環境変数からの設定読み込みに失敗しました: %w", err)
 	}
 
+	// コマンドライン引数で上書き（最も優先度が高い）
+	if cl.overrides != nil {
+		if err := cl.overrides(config); err != nil {
+			return nil, fmt.Errorf("コマンドライン引数の適用に失敗しました: %w", err)
+		}
+	}
+
 	// 設定の妥当性を検証
 	if err := cl.validateConfig(config); err != nil {
 		return nil, fmt.Errorf("設定の検証に失敗しました: %w", err)
@@ -114,15 +256,50 @@ func (cl *ConfigLoader) setDefaults(config *WebhookConfig) error {
 	config.TLSCertFile = "/etc/certs/tls.crt"
 	config.TLSKeyFile = "/etc/certs/tls.key"
 	config.Timeout = 10 * time.Second
+	config.ReadHeaderTimeout = 5 * time.Second
+	config.ReadTimeout = 10 * time.Second
+	config.WriteTimeout = 15 * time.Second
+	config.IdleTimeout = 60 * time.Second
+	config.MaxRequestBodyBytes = DefaultMaxRequestBodyBytes
+	config.TLSSource = TLSSourceFile
+	config.TLSProfile = TLSProfileIntermediate
+	config.CertCheckInterval = time.Minute
+	config.CertWatchEnabled = false
+	config.ClientAuthEnabled = false
+	config.CertBootstrap = false
+	config.CABundleCheckInterval = 5 * time.Minute
 	config.LogLevel = "info"
 	config.LogFormat = "json"
 	config.MetricsEnabled = true
 	config.MetricsPort = 8080
 	config.HealthEnabled = true
+	config.MetricsRequesterNamespace = false
+	config.MetricsRequesterNamespaceLimit = 50
+	config.EventsEnabled = false
+	config.AuditEnabled = false
+	config.AuditInterval = 10 * time.Minute
+	config.PolicyReportEnabled = false
+	config.DecisionLogEnabled = false
+	config.DecisionLogPath = "/var/log/webhook/decisions.jsonl"
+	config.DecisionLogMaxSizeMB = 100
+	config.DecisionLogMaxAge = 24 * time.Hour
+	config.DecisionLogMaxBackups = 5
+	config.DecisionLogBufferSize = 1024
+	config.NotificationsEnabled = false
+	config.NotificationFormat = NotificationFormatJSON
+	config.NotificationBatchWindow = 30 * time.Second
+	config.NotificationQueueSize = 256
+	config.CloudEventsEnabled = false
+	config.CloudEventsMode = CloudEventsModeBinary
+	config.CloudEventsAllDecisions = false
+	config.CloudEventsBufferSize = 1024
 	config.Environment = "development"
 	config.FailurePolicy = "Fail"
 	config.SkipNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
 	config.SkipLabels = []string{"k8s-deployment-hpa-validator.io/skip-validation=true"}
+	config.MinReplicas = 2
+	config.MutationEnabled = false
+	config.Language = "ja"
 
 	return nil
 }
@@ -178,9 +355,121 @@ func (cl *ConfigLoader) loadFromYAMLFile(config *WebhookConfig) error {
 	if yamlConfig.TLSKeyFile != "" {
 		config.TLSKeyFile = yamlConfig.TLSKeyFile
 	}
+	if yamlConfig.TLSCAFile != "" {
+		config.TLSCAFile = yamlConfig.TLSCAFile
+	}
+	if yamlConfig.Kubeconfig != "" {
+		config.Kubeconfig = yamlConfig.Kubeconfig
+	}
 	if yamlConfig.Timeout != 0 {
 		config.Timeout = yamlConfig.Timeout
 	}
+	if yamlConfig.ReadHeaderTimeout != 0 {
+		config.ReadHeaderTimeout = yamlConfig.ReadHeaderTimeout
+	}
+	if yamlConfig.ReadTimeout != 0 {
+		config.ReadTimeout = yamlConfig.ReadTimeout
+	}
+	if yamlConfig.WriteTimeout != 0 {
+		config.WriteTimeout = yamlConfig.WriteTimeout
+	}
+	if yamlConfig.IdleTimeout != 0 {
+		config.IdleTimeout = yamlConfig.IdleTimeout
+	}
+	if yamlConfig.MaxRequestBodyBytes != 0 {
+		config.MaxRequestBodyBytes = yamlConfig.MaxRequestBodyBytes
+	}
+	if yamlConfig.TLSSource != "" {
+		config.TLSSource = yamlConfig.TLSSource
+	}
+	if yamlConfig.TLSSecretName != "" {
+		config.TLSSecretName = yamlConfig.TLSSecretName
+	}
+	if yamlConfig.TLSSecretNamespace != "" {
+		config.TLSSecretNamespace = yamlConfig.TLSSecretNamespace
+	}
+	if yamlConfig.TLSProfile != "" {
+		config.TLSProfile = yamlConfig.TLSProfile
+	}
+	if yamlConfig.TLSMinVersion != "" {
+		config.TLSMinVersion = yamlConfig.TLSMinVersion
+	}
+	if len(yamlConfig.TLSCipherSuites) > 0 {
+		config.TLSCipherSuites = yamlConfig.TLSCipherSuites
+	}
+	if len(yamlConfig.TLSCurvePreferences) > 0 {
+		config.TLSCurvePreferences = yamlConfig.TLSCurvePreferences
+	}
+	if yamlConfig.ClientCAFile != "" {
+		config.ClientCAFile = yamlConfig.ClientCAFile
+	}
+	if len(yamlConfig.ClientAllowedNames) > 0 {
+		config.ClientAllowedNames = yamlConfig.ClientAllowedNames
+	}
+	if yamlConfig.CertCheckInterval != 0 {
+		config.CertCheckInterval = yamlConfig.CertCheckInterval
+	}
+	if yamlConfig.CertBootstrapService != "" {
+		config.CertBootstrapService = yamlConfig.CertBootstrapService
+	}
+	if yamlConfig.WebhookConfigurationName != "" {
+		config.WebhookConfigurationName = yamlConfig.WebhookConfigurationName
+	}
+	if yamlConfig.MetricsRequesterNamespaceLimit != 0 {
+		config.MetricsRequesterNamespaceLimit = yamlConfig.MetricsRequesterNamespaceLimit
+	}
+	if yamlConfig.CABundleCheckInterval != 0 {
+		config.CABundleCheckInterval = yamlConfig.CABundleCheckInterval
+	}
+	if yamlConfig.AuditInterval != 0 {
+		config.AuditInterval = yamlConfig.AuditInterval
+	}
+	if yamlConfig.LeaderElectionNamespace != "" {
+		config.LeaderElectionNamespace = yamlConfig.LeaderElectionNamespace
+	}
+	if yamlConfig.DecisionLogPath != "" {
+		config.DecisionLogPath = yamlConfig.DecisionLogPath
+	}
+	if yamlConfig.DecisionLogMaxSizeMB != 0 {
+		config.DecisionLogMaxSizeMB = yamlConfig.DecisionLogMaxSizeMB
+	}
+	if yamlConfig.DecisionLogMaxAge != 0 {
+		config.DecisionLogMaxAge = yamlConfig.DecisionLogMaxAge
+	}
+	// DecisionLogMaxBackupsは0（削除しない）も指定できるため、キーの有無で判定する
+	var explicit struct {
+		DecisionLogMaxBackups *int `yaml:"decision_log_max_backups"`
+	}
+	if err := yaml.Unmarshal(data, &explicit); err == nil && explicit.DecisionLogMaxBackups != nil {
+		config.DecisionLogMaxBackups = *explicit.DecisionLogMaxBackups
+	}
+	if yamlConfig.DecisionLogBufferSize != 0 {
+		config.DecisionLogBufferSize = yamlConfig.DecisionLogBufferSize
+	}
+	if yamlConfig.NotificationURL != "" {
+		config.NotificationURL = yamlConfig.NotificationURL
+	}
+	if yamlConfig.NotificationFormat != "" {
+		config.NotificationFormat = yamlConfig.NotificationFormat
+	}
+	if yamlConfig.NotificationBatchWindow != 0 {
+		config.NotificationBatchWindow = yamlConfig.NotificationBatchWindow
+	}
+	if yamlConfig.NotificationQueueSize != 0 {
+		config.NotificationQueueSize = yamlConfig.NotificationQueueSize
+	}
+	if len(yamlConfig.NotificationAllowedURLPrefixes) > 0 {
+		config.NotificationAllowedURLPrefixes = yamlConfig.NotificationAllowedURLPrefixes
+	}
+	if yamlConfig.CloudEventsSinkURL != "" {
+		config.CloudEventsSinkURL = yamlConfig.CloudEventsSinkURL
+	}
+	if yamlConfig.CloudEventsMode != "" {
+		config.CloudEventsMode = yamlConfig.CloudEventsMode
+	}
+	if yamlConfig.CloudEventsBufferSize != 0 {
+		config.CloudEventsBufferSize = yamlConfig.CloudEventsBufferSize
+	}
 	if yamlConfig.LogLevel != "" {
 		config.LogLevel = yamlConfig.LogLevel
 	}
@@ -193,6 +482,30 @@ func (cl *ConfigLoader) loadFromYAMLFile(config *WebhookConfig) error {
 	if len(yamlConfig.SkipLabels) > 0 {
 		config.SkipLabels = append(config.SkipLabels, yamlConfig.SkipLabels...)
 	}
+	if yamlConfig.MinReplicas != 0 {
+		config.MinReplicas = yamlConfig.MinReplicas
+	}
+	if len(yamlConfig.ExemptUsers) > 0 {
+		config.ExemptUsers = yamlConfig.ExemptUsers
+	}
+	if len(yamlConfig.ExemptGroups) > 0 {
+		config.ExemptGroups = yamlConfig.ExemptGroups
+	}
+	if len(yamlConfig.ExemptServiceAccounts) > 0 {
+		config.ExemptServiceAccounts = yamlConfig.ExemptServiceAccounts
+	}
+	if len(yamlConfig.MutationNamespaces) > 0 {
+		config.MutationNamespaces = yamlConfig.MutationNamespaces
+	}
+	if yamlConfig.Language != "" {
+		config.Language = yamlConfig.Language
+	}
+	for code, tmpl := range yamlConfig.MessageTemplates {
+		if config.MessageTemplates == nil {
+			config.MessageTemplates = map[string]string{}
+		}
+		config.MessageTemplates[code] = tmpl
+	}
 	if yamlConfig.MetricsPort != 0 {
 		config.MetricsPort = yamlConfig.MetricsPort
 	}
@@ -210,6 +523,18 @@ func (cl *ConfigLoader) loadFromYAMLFile(config *WebhookConfig) error {
 	// YAMLファイルでブール値が設定されている場合は上書き
 	config.MetricsEnabled = yamlConfig.MetricsEnabled
 	config.HealthEnabled = yamlConfig.HealthEnabled
+	config.MetricsRequesterNamespace = yamlConfig.MetricsRequesterNamespace
+	config.EventsEnabled = yamlConfig.EventsEnabled
+	config.AuditEnabled = yamlConfig.AuditEnabled
+	config.PolicyReportEnabled = yamlConfig.PolicyReportEnabled
+	config.DecisionLogEnabled = yamlConfig.DecisionLogEnabled
+	config.NotificationsEnabled = yamlConfig.NotificationsEnabled
+	config.CloudEventsEnabled = yamlConfig.CloudEventsEnabled
+	config.CloudEventsAllDecisions = yamlConfig.CloudEventsAllDecisions
+	config.MutationEnabled = yamlConfig.MutationEnabled
+	config.CertWatchEnabled = yamlConfig.CertWatchEnabled
+	config.CertBootstrap = yamlConfig.CertBootstrap
+	config.ClientAuthEnabled = yamlConfig.ClientAuthEnabled
 
 	return nil
 }
@@ -234,11 +559,98 @@ func (cl *ConfigLoader) loadFromConfigMap(config *WebhookConfig) error {
 	if keyFile, exists := cl.configMapData["webhook.tls-key-file"]; exists {
 		config.TLSKeyFile = keyFile
 	}
+	if caFile, exists := cl.configMapData["webhook.tls-ca-file"]; exists {
+		config.TLSCAFile = caFile
+	}
+
+	// 証明書の読み込み元
+	if source, exists := cl.configMapData["webhook.tls-source"]; exists {
+		config.TLSSource = source
+	}
+	if secretName, exists := cl.configMapData["webhook.tls-secret-name"]; exists {
+		config.TLSSecretName = secretName
+	}
+	if secretNamespace, exists := cl.configMapData["webhook.tls-secret-namespace"]; exists {
+		config.TLSSecretNamespace = secretNamespace
+	}
+
+	// TLSプロファイル
+	if profile, exists := cl.configMapData["webhook.tls-profile"]; exists {
+		config.TLSProfile = profile
+	}
+	if minVersion, exists := cl.configMapData["webhook.tls-min-version"]; exists {
+		config.TLSMinVersion = minVersion
+	}
+	if cipherSuites, exists := cl.configMapData["webhook.tls-cipher-suites"]; exists {
+		config.TLSCipherSuites = splitAndTrim(cipherSuites)
+	}
+	if curves, exists := cl.configMapData["webhook.tls-curve-preferences"]; exists {
+		config.TLSCurvePreferences = splitAndTrim(curves)
+	}
+
+	// クライアント証明書による認証
+	if enabled, exists := cl.configMapData["webhook.client-auth-enabled"]; exists {
+		config.ClientAuthEnabled = strings.ToLower(enabled) == "true"
+	}
+	if caFile, exists := cl.configMapData["webhook.client-ca-file"]; exists {
+		config.ClientCAFile = caFile
+	}
+	if names, exists := cl.configMapData["webhook.client-allowed-names"]; exists {
+		config.ClientAllowedNames = splitAndTrim(names)
+	}
+
+	// 証明書の監視設定
+	if intervalStr, exists := cl.configMapData["webhook.cert-check-interval"]; exists {
+		if interval, err := time.ParseDuration(intervalStr); err == nil {
+			config.CertCheckInterval = interval
+		}
+	}
+	if watchEnabled, exists := cl.configMapData["webhook.cert-watch-enabled"]; exists {
+		config.CertWatchEnabled = strings.ToLower(watchEnabled) == "true"
+	}
+
+	// 証明書の自動発行
+	if bootstrap, exists := cl.configMapData["webhook.cert-bootstrap"]; exists {
+		config.CertBootstrap = strings.ToLower(bootstrap) == "true"
+	}
+	if service, exists := cl.configMapData["webhook.cert-bootstrap-service"]; exists {
+		config.CertBootstrapService = service
+	}
+	if name, exists := cl.configMapData["webhook.configuration-name"]; exists {
+		config.WebhookConfigurationName = name
+	}
+	if intervalStr, exists := cl.configMapData["webhook.ca-bundle-check-interval"]; exists {
+		if interval, err := time.ParseDuration(intervalStr); err == nil {
+			config.CABundleCheckInterval = interval
+		}
+	}
 
 	// タイムアウト設定
 	if timeoutStr, exists := cl.configMapData["webhook.timeout"]; exists {
-		if timeout, err := time.ParseDuration(timeoutStr + "s"); err == nil {
+		// 単位を省略した場合は秒として扱う
+		if timeout, err := time.ParseDuration(timeoutStr); err == nil {
 			config.Timeout = timeout
+		} else if timeout, err := time.ParseDuration(timeoutStr + "s"); err == nil {
+			config.Timeout = timeout
+		}
+	}
+	for key, target := range map[string]*time.Duration{
+		"webhook.read-header-timeout": &config.ReadHeaderTimeout,
+		"webhook.read-timeout":        &config.ReadTimeout,
+		"webhook.write-timeout":       &config.WriteTimeout,
+		"webhook.idle-timeout":        &config.IdleTimeout,
+	} {
+		if value, exists := cl.configMapData[key]; exists {
+			if timeout, err := time.ParseDuration(value); err == nil {
+				*target = timeout
+			}
+		}
+	}
+
+	// リクエストボディの最大サイズ
+	if maxBytesStr, exists := cl.configMapData["webhook.max-request-body-bytes"]; exists {
+		if maxBytes, err := strconv.ParseInt(maxBytesStr, 10, 64); err == nil {
+			config.MaxRequestBodyBytes = maxBytes
 		}
 	}
 
@@ -269,6 +681,40 @@ func (cl *ConfigLoader) loadFromConfigMap(config *WebhookConfig) error {
 		// デフォルト値に追加
 		config.SkipLabels = append(config.SkipLabels, additionalLabels...)
 	}
+	if minReplicasStr, exists := cl.configMapData["validation.min-replicas"]; exists {
+		if minReplicas, err := strconv.Atoi(minReplicasStr); err == nil {
+			config.MinReplicas = minReplicas
+		}
+	}
+
+	if exemptUsers, exists := cl.configMapData["validation.exempt-users"]; exists {
+		config.ExemptUsers = splitAndTrim(exemptUsers)
+	}
+	if exemptGroups, exists := cl.configMapData["validation.exempt-groups"]; exists {
+		config.ExemptGroups = splitAndTrim(exemptGroups)
+	}
+	if exemptServiceAccounts, exists := cl.configMapData["validation.exempt-service-accounts"]; exists {
+		config.ExemptServiceAccounts = splitAndTrim(exemptServiceAccounts)
+	}
+
+	// ミューテーション設定
+	if mutationEnabled, exists := cl.configMapData["mutation.enabled"]; exists {
+		config.MutationEnabled = strings.ToLower(mutationEnabled) == "true"
+	}
+	if mutationNamespaces, exists := cl.configMapData["mutation.namespaces"]; exists {
+		config.MutationNamespaces = splitAndTrim(mutationNamespaces)
+	}
+
+	// メッセージ設定
+	if language, exists := cl.configMapData["message.language"]; exists {
+		config.Language = language
+	}
+	for code, tmpl := range messageTemplatesFromConfigMap(cl.configMapData) {
+		if config.MessageTemplates == nil {
+			config.MessageTemplates = map[string]string{}
+		}
+		config.MessageTemplates[code] = tmpl
+	}
 
 	// 監視設定
 	if metricsEnabled, exists := cl.configMapData["metrics.enabled"]; exists {
@@ -282,6 +728,104 @@ func (cl *ConfigLoader) loadFromConfigMap(config *WebhookConfig) error {
 	if healthEnabled, exists := cl.configMapData["health.enabled"]; exists {
 		config.HealthEnabled = strings.ToLower(healthEnabled) == "true"
 	}
+	if requesterNamespace, exists := cl.configMapData["metrics.requester-namespace"]; exists {
+		config.MetricsRequesterNamespace = strings.ToLower(requesterNamespace) == "true"
+	}
+	if limitStr, exists := cl.configMapData["metrics.requester-namespace-limit"]; exists {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			config.MetricsRequesterNamespaceLimit = limit
+		}
+	}
+	if eventsEnabled, exists := cl.configMapData["events.enabled"]; exists {
+		config.EventsEnabled = strings.ToLower(eventsEnabled) == "true"
+	}
+
+	// 監査設定
+	if auditEnabled, exists := cl.configMapData["audit.enabled"]; exists {
+		config.AuditEnabled = strings.ToLower(auditEnabled) == "true"
+	}
+	if intervalStr, exists := cl.configMapData["audit.interval"]; exists {
+		if interval, err := time.ParseDuration(intervalStr); err == nil {
+			config.AuditInterval = interval
+		}
+	}
+	if policyReport, exists := cl.configMapData["audit.policy-report-enabled"]; exists {
+		config.PolicyReportEnabled = strings.ToLower(policyReport) == "true"
+	}
+	if namespace, exists := cl.configMapData["audit.leader-election-namespace"]; exists {
+		config.LeaderElectionNamespace = namespace
+	}
+
+	// 決定ログ設定
+	if decisionLog, exists := cl.configMapData["decision-log.enabled"]; exists {
+		config.DecisionLogEnabled = strings.ToLower(decisionLog) == "true"
+	}
+	if path, exists := cl.configMapData["decision-log.path"]; exists {
+		config.DecisionLogPath = path
+	}
+	if maxSizeStr, exists := cl.configMapData["decision-log.max-size-mb"]; exists {
+		if maxSize, err := strconv.Atoi(maxSizeStr); err == nil {
+			config.DecisionLogMaxSizeMB = maxSize
+		}
+	}
+	if maxAgeStr, exists := cl.configMapData["decision-log.max-age"]; exists {
+		if maxAge, err := time.ParseDuration(maxAgeStr); err == nil {
+			config.DecisionLogMaxAge = maxAge
+		}
+	}
+	if maxBackupsStr, exists := cl.configMapData["decision-log.max-backups"]; exists {
+		if maxBackups, err := strconv.Atoi(maxBackupsStr); err == nil {
+			config.DecisionLogMaxBackups = maxBackups
+		}
+	}
+	if bufferSizeStr, exists := cl.configMapData["decision-log.buffer-size"]; exists {
+		if bufferSize, err := strconv.Atoi(bufferSizeStr); err == nil {
+			config.DecisionLogBufferSize = bufferSize
+		}
+	}
+
+	// 通知設定
+	if notificationsEnabled, exists := cl.configMapData["notifications.enabled"]; exists {
+		config.NotificationsEnabled = strings.ToLower(notificationsEnabled) == "true"
+	}
+	if url, exists := cl.configMapData["notifications.url"]; exists {
+		config.NotificationURL = url
+	}
+	if format, exists := cl.configMapData["notifications.format"]; exists {
+		config.NotificationFormat = format
+	}
+	if windowStr, exists := cl.configMapData["notifications.batch-window"]; exists {
+		if window, err := time.ParseDuration(windowStr); err == nil {
+			config.NotificationBatchWindow = window
+		}
+	}
+	if queueSizeStr, exists := cl.configMapData["notifications.queue-size"]; exists {
+		if queueSize, err := strconv.Atoi(queueSizeStr); err == nil {
+			config.NotificationQueueSize = queueSize
+		}
+	}
+	if prefixes, exists := cl.configMapData["notifications.allowed-url-prefixes"]; exists {
+		config.NotificationAllowedURLPrefixes = splitAndTrim(prefixes)
+	}
+
+	// CloudEvents設定
+	if cloudEventsEnabled, exists := cl.configMapData["cloudevents.enabled"]; exists {
+		config.CloudEventsEnabled = strings.ToLower(cloudEventsEnabled) == "true"
+	}
+	if sinkURL, exists := cl.configMapData["cloudevents.sink-url"]; exists {
+		config.CloudEventsSinkURL = sinkURL
+	}
+	if mode, exists := cl.configMapData["cloudevents.mode"]; exists {
+		config.CloudEventsMode = mode
+	}
+	if allDecisions, exists := cl.configMapData["cloudevents.all-decisions"]; exists {
+		config.CloudEventsAllDecisions = strings.ToLower(allDecisions) == "true"
+	}
+	if bufferSizeStr, exists := cl.configMapData["cloudevents.buffer-size"]; exists {
+		if bufferSize, err := strconv.Atoi(bufferSizeStr); err == nil {
+			config.CloudEventsBufferSize = bufferSize
+		}
+	}
 
 	// 環境情報
 	if environment, exists := cl.configMapData["environment"]; exists {
@@ -317,6 +861,80 @@ func (cl *ConfigLoader) loadFromEnv(config *WebhookConfig) error {
 	if keyFile := os.Getenv("TLS_KEY_FILE"); keyFile != "" {
 		config.TLSKeyFile = keyFile
 	}
+	if caFile := os.Getenv("TLS_CA_FILE"); caFile != "" {
+		config.TLSCAFile = caFile
+	}
+
+	// 証明書の読み込み元
+	if source := os.Getenv("TLS_SOURCE"); source != "" {
+		config.TLSSource = source
+	}
+	if secretName := os.Getenv("TLS_SECRET_NAME"); secretName != "" {
+		config.TLSSecretName = secretName
+	}
+	if secretNamespace := os.Getenv("TLS_SECRET_NAMESPACE"); secretNamespace != "" {
+		config.TLSSecretNamespace = secretNamespace
+	}
+
+	// TLSプロファイル
+	if profile := os.Getenv("TLS_PROFILE"); profile != "" {
+		config.TLSProfile = profile
+	}
+	if minVersion := os.Getenv("TLS_MIN_VERSION"); minVersion != "" {
+		config.TLSMinVersion = minVersion
+	}
+	if cipherSuites := os.Getenv("TLS_CIPHER_SUITES"); cipherSuites != "" {
+		config.TLSCipherSuites = splitAndTrim(cipherSuites)
+	}
+	if curves := os.Getenv("TLS_CURVE_PREFERENCES"); curves != "" {
+		config.TLSCurvePreferences = splitAndTrim(curves)
+	}
+
+	// クライアント証明書による認証
+	if enabled := os.Getenv("CLIENT_AUTH_ENABLED"); enabled != "" {
+		config.ClientAuthEnabled = strings.ToLower(enabled) == "true"
+	}
+	if caFile := os.Getenv("CLIENT_CA_FILE"); caFile != "" {
+		config.ClientCAFile = caFile
+	}
+	if names := os.Getenv("CLIENT_ALLOWED_NAMES"); names != "" {
+		config.ClientAllowedNames = splitAndTrim(names)
+	}
+
+	// 証明書の監視設定
+	if intervalStr := os.Getenv("CERT_CHECK_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil {
+			config.CertCheckInterval = interval
+		} else {
+			return fmt.Errorf("無効なCERT_CHECK_INTERVAL値: %s", intervalStr)
+		}
+	}
+	if watchEnabled := os.Getenv("CERT_WATCH_ENABLED"); watchEnabled != "" {
+		config.CertWatchEnabled = strings.ToLower(watchEnabled) == "true"
+	}
+
+	// 証明書の自動発行
+	if bootstrap := os.Getenv("CERT_BOOTSTRAP"); bootstrap != "" {
+		config.CertBootstrap = strings.ToLower(bootstrap) == "true"
+	}
+	if service := os.Getenv("CERT_BOOTSTRAP_SERVICE"); service != "" {
+		config.CertBootstrapService = service
+	}
+	if name := os.Getenv("WEBHOOK_CONFIGURATION_NAME"); name != "" {
+		config.WebhookConfigurationName = name
+	}
+	if intervalStr := os.Getenv("CA_BUNDLE_CHECK_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil {
+			config.CABundleCheckInterval = interval
+		} else {
+			return fmt.Errorf("無効なCA_BUNDLE_CHECK_INTERVAL値: %s", intervalStr)
+		}
+	}
+
+	// Kubernetes API設定
+	if kubeconfig := os.Getenv("KUBECONFIG"); kubeconfig != "" {
+		config.Kubeconfig = kubeconfig
+	}
 
 	// タイムアウト設定
 	if timeoutStr := os.Getenv("WEBHOOK_TIMEOUT"); timeoutStr != "" {
@@ -326,6 +944,32 @@ func (cl *ConfigLoader) loadFromEnv(config *WebhookConfig) error {
 			return fmt.Errorf("無効なWEBHOOK_TIMEOUT値: %s", timeoutStr)
 		}
 	}
+	for _, env := range []struct {
+		name   string
+		target *time.Duration
+	}{
+		{"WEBHOOK_READ_HEADER_TIMEOUT", &config.ReadHeaderTimeout},
+		{"WEBHOOK_READ_TIMEOUT", &config.ReadTimeout},
+		{"WEBHOOK_WRITE_TIMEOUT", &config.WriteTimeout},
+		{"WEBHOOK_IDLE_TIMEOUT", &config.IdleTimeout},
+	} {
+		if value := os.Getenv(env.name); value != "" {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("無効な%s値: %s", env.name, value)
+			}
+			*env.target = timeout
+		}
+	}
+
+	// リクエストボディの最大サイズ
+	if maxBytesStr := os.Getenv("WEBHOOK_MAX_REQUEST_BODY_BYTES"); maxBytesStr != "" {
+		if maxBytes, err := strconv.ParseInt(maxBytesStr, 10, 64); err == nil {
+			config.MaxRequestBodyBytes = maxBytes
+		} else {
+			return fmt.Errorf("無効なWEBHOOK_MAX_REQUEST_BODY_BYTES値: %s", maxBytesStr)
+		}
+	}
 
 	// ログ設定
 	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
@@ -354,6 +998,36 @@ func (cl *ConfigLoader) loadFromEnv(config *WebhookConfig) error {
 		// デフォルト値に追加
 		config.SkipLabels = append(config.SkipLabels, additionalLabels...)
 	}
+	if minReplicasStr := os.Getenv("MIN_REPLICAS"); minReplicasStr != "" {
+		if minReplicas, err := strconv.Atoi(minReplicasStr); err == nil {
+			config.MinReplicas = minReplicas
+		} else {
+			return fmt.Errorf("無効なMIN_REPLICAS値: %s", minReplicasStr)
+		}
+	}
+
+	if exemptUsers := os.Getenv("EXEMPT_USERS"); exemptUsers != "" {
+		config.ExemptUsers = splitAndTrim(exemptUsers)
+	}
+	if exemptGroups := os.Getenv("EXEMPT_GROUPS"); exemptGroups != "" {
+		config.ExemptGroups = splitAndTrim(exemptGroups)
+	}
+	if exemptServiceAccounts := os.Getenv("EXEMPT_SERVICE_ACCOUNTS"); exemptServiceAccounts != "" {
+		config.ExemptServiceAccounts = splitAndTrim(exemptServiceAccounts)
+	}
+
+	// ミューテーション設定
+	if mutationEnabled := os.Getenv("MUTATION_ENABLED"); mutationEnabled != "" {
+		config.MutationEnabled = strings.ToLower(mutationEnabled) == "true"
+	}
+	if mutationNamespaces := os.Getenv("MUTATION_NAMESPACES"); mutationNamespaces != "" {
+		config.MutationNamespaces = splitAndTrim(mutationNamespaces)
+	}
+
+	// メッセージ設定
+	if language := os.Getenv("WEBHOOK_LANGUAGE"); language != "" {
+		config.Language = language
+	}
 
 	// 監視設定
 	if metricsEnabled := os.Getenv("METRICS_ENABLED"); metricsEnabled != "" {
@@ -369,6 +1043,122 @@ func (cl *ConfigLoader) loadFromEnv(config *WebhookConfig) error {
 	if healthEnabled := os.Getenv("HEALTH_ENABLED"); healthEnabled != "" {
 		config.HealthEnabled = strings.ToLower(healthEnabled) == "true"
 	}
+	if requesterNamespace := os.Getenv("METRICS_REQUESTER_NAMESPACE"); requesterNamespace != "" {
+		config.MetricsRequesterNamespace = strings.ToLower(requesterNamespace) == "true"
+	}
+	if limitStr := os.Getenv("METRICS_REQUESTER_NAMESPACE_LIMIT"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			config.MetricsRequesterNamespaceLimit = limit
+		} else {
+			return fmt.Errorf("無効なMETRICS_REQUESTER_NAMESPACE_LIMIT値: %s", limitStr)
+		}
+	}
+	if eventsEnabled := os.Getenv("EVENTS_ENABLED"); eventsEnabled != "" {
+		config.EventsEnabled = strings.ToLower(eventsEnabled) == "true"
+	}
+
+	// 監査設定
+	if auditEnabled := os.Getenv("AUDIT_ENABLED"); auditEnabled != "" {
+		config.AuditEnabled = strings.ToLower(auditEnabled) == "true"
+	}
+	if intervalStr := os.Getenv("AUDIT_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil {
+			config.AuditInterval = interval
+		} else {
+			return fmt.Errorf("無効なAUDIT_INTERVAL値: %s", intervalStr)
+		}
+	}
+	if policyReport := os.Getenv("POLICY_REPORT_ENABLED"); policyReport != "" {
+		config.PolicyReportEnabled = strings.ToLower(policyReport) == "true"
+	}
+	if namespace := os.Getenv("LEADER_ELECTION_NAMESPACE"); namespace != "" {
+		config.LeaderElectionNamespace = namespace
+	}
+
+	// 決定ログ設定
+	if decisionLog := os.Getenv("DECISION_LOG_ENABLED"); decisionLog != "" {
+		config.DecisionLogEnabled = strings.ToLower(decisionLog) == "true"
+	}
+	if path := os.Getenv("DECISION_LOG_PATH"); path != "" {
+		config.DecisionLogPath = path
+	}
+	if maxSizeStr := os.Getenv("DECISION_LOG_MAX_SIZE_MB"); maxSizeStr != "" {
+		if maxSize, err := strconv.Atoi(maxSizeStr); err == nil {
+			config.DecisionLogMaxSizeMB = maxSize
+		} else {
+			return fmt.Errorf("無効なDECISION_LOG_MAX_SIZE_MB値: %s", maxSizeStr)
+		}
+	}
+	if maxAgeStr := os.Getenv("DECISION_LOG_MAX_AGE"); maxAgeStr != "" {
+		if maxAge, err := time.ParseDuration(maxAgeStr); err == nil {
+			config.DecisionLogMaxAge = maxAge
+		} else {
+			return fmt.Errorf("無効なDECISION_LOG_MAX_AGE値: %s", maxAgeStr)
+		}
+	}
+	if maxBackupsStr := os.Getenv("DECISION_LOG_MAX_BACKUPS"); maxBackupsStr != "" {
+		if maxBackups, err := strconv.Atoi(maxBackupsStr); err == nil {
+			config.DecisionLogMaxBackups = maxBackups
+		} else {
+			return fmt.Errorf("無効なDECISION_LOG_MAX_BACKUPS値: %s", maxBackupsStr)
+		}
+	}
+	if bufferSizeStr := os.Getenv("DECISION_LOG_BUFFER_SIZE"); bufferSizeStr != "" {
+		if bufferSize, err := strconv.Atoi(bufferSizeStr); err == nil {
+			config.DecisionLogBufferSize = bufferSize
+		} else {
+			return fmt.Errorf("無効なDECISION_LOG_BUFFER_SIZE値: %s", bufferSizeStr)
+		}
+	}
+
+	// 通知設定
+	if notificationsEnabled := os.Getenv("NOTIFICATIONS_ENABLED"); notificationsEnabled != "" {
+		config.NotificationsEnabled = strings.ToLower(notificationsEnabled) == "true"
+	}
+	if url := os.Getenv("NOTIFICATION_URL"); url != "" {
+		config.NotificationURL = url
+	}
+	if format := os.Getenv("NOTIFICATION_FORMAT"); format != "" {
+		config.NotificationFormat = format
+	}
+	if windowStr := os.Getenv("NOTIFICATION_BATCH_WINDOW"); windowStr != "" {
+		if window, err := time.ParseDuration(windowStr); err == nil {
+			config.NotificationBatchWindow = window
+		} else {
+			return fmt.Errorf("無効なNOTIFICATION_BATCH_WINDOW値: %s", windowStr)
+		}
+	}
+	if queueSizeStr := os.Getenv("NOTIFICATION_QUEUE_SIZE"); queueSizeStr != "" {
+		if queueSize, err := strconv.Atoi(queueSizeStr); err == nil {
+			config.NotificationQueueSize = queueSize
+		} else {
+			return fmt.Errorf("無効なNOTIFICATION_QUEUE_SIZE値: %s", queueSizeStr)
+		}
+	}
+	if prefixes := os.Getenv("NOTIFICATION_ALLOWED_URL_PREFIXES"); prefixes != "" {
+		config.NotificationAllowedURLPrefixes = splitAndTrim(prefixes)
+	}
+
+	// CloudEvents設定
+	if cloudEventsEnabled := os.Getenv("CLOUDEVENTS_ENABLED"); cloudEventsEnabled != "" {
+		config.CloudEventsEnabled = strings.ToLower(cloudEventsEnabled) == "true"
+	}
+	if sinkURL := os.Getenv("CLOUDEVENTS_SINK_URL"); sinkURL != "" {
+		config.CloudEventsSinkURL = sinkURL
+	}
+	if mode := os.Getenv("CLOUDEVENTS_MODE"); mode != "" {
+		config.CloudEventsMode = mode
+	}
+	if allDecisions := os.Getenv("CLOUDEVENTS_ALL_DECISIONS"); allDecisions != "" {
+		config.CloudEventsAllDecisions = strings.ToLower(allDecisions) == "true"
+	}
+	if bufferSizeStr := os.Getenv("CLOUDEVENTS_BUFFER_SIZE"); bufferSizeStr != "" {
+		if bufferSize, err := strconv.Atoi(bufferSizeStr); err == nil {
+			config.CloudEventsBufferSize = bufferSize
+		} else {
+			return fmt.Errorf("無効なCLOUDEVENTS_BUFFER_SIZE値: %s", bufferSizeStr)
+		}
+	}
 
 	// 環境情報
 	if environment := os.Getenv("ENVIRONMENT"); environment != "" {
@@ -425,11 +1215,162 @@ func (cl *ConfigLoader) validateConfig(config *WebhookConfig) error {
 		return fmt.Errorf("無効な失敗ポリシー: %s (有効な値: %v)", config.FailurePolicy, validFailurePolicies)
 	}
 
+	// replica数の下限の検証（HPAは2以上でなければ機能しない、0は未指定としてデフォルト値を使用）
+	if config.MinReplicas != 0 && config.MinReplicas < 2 {
+		return fmt.Errorf("無効なreplica数の下限: %d (2以上を指定してください)", config.MinReplicas)
+	}
+
+	// タイムアウトの検証
+	if config.Timeout < 0 {
+		return fmt.Errorf("無効なタイムアウト: %v", config.Timeout)
+	}
+	for name, timeout := range map[string]time.Duration{
+		"read_header_timeout": config.ReadHeaderTimeout,
+		"read_timeout":        config.ReadTimeout,
+		"write_timeout":       config.WriteTimeout,
+		"idle_timeout":        config.IdleTimeout,
+	} {
+		if timeout < 0 {
+			return fmt.Errorf("無効な%s: %v", name, timeout)
+		}
+	}
+	// レスポンスを書き込む前に接続が切断されないよう、書き込みタイムアウトは処理期限より長くする
+	if config.WriteTimeout > 0 && config.Timeout > 0 && config.WriteTimeout <= config.Timeout {
+		return fmt.Errorf("write_timeout (%v) はtimeout (%v) より長く設定してください", config.WriteTimeout, config.Timeout)
+	}
+
+	// 証明書の読み込み元の検証
+	switch config.TLSSource {
+	case TLSSourceFile:
+	case TLSSourceSecret:
+		if config.TLSSecretName == "" || config.TLSSecretNamespace == "" {
+			return fmt.Errorf("tls_source が secret の場合は tls_secret_name と tls_secret_namespace を指定してください")
+		}
+	default:
+		return fmt.Errorf("無効な証明書の読み込み元: %s (有効な値: %v)", config.TLSSource, []string{TLSSourceFile, TLSSourceSecret})
+	}
+
+	// TLSプロファイルの検証
+	if _, err := config.ResolveTLSSettings(); err != nil {
+		return err
+	}
+
+	// クライアント証明書による認証の検証
+	if config.ClientAuthEnabled && config.ClientCAFile == "" {
+		return fmt.Errorf("client_auth_enabled を有効にする場合は client_ca_file を指定してください")
+	}
+	for _, name := range config.ClientAllowedNames {
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("無効なクライアント名のパターン: %s", name)
+		}
+	}
+
+	// 検証の対象外とする呼び出し元の検証
+	if err := config.validateExemptions(); err != nil {
+		return err
+	}
+
+	// 証明書の自動発行の検証（発行した証明書はSecretに保存し、Secretから読み込む）
+	if config.CertBootstrap {
+		if config.TLSSource != TLSSourceSecret {
+			return fmt.Errorf("cert_bootstrap を有効にする場合は tls_source に secret を指定してください")
+		}
+		if config.CertBootstrapService == "" || config.WebhookConfigurationName == "" {
+			return fmt.Errorf("cert_bootstrap を有効にする場合は cert_bootstrap_service と webhook_configuration_name を指定してください")
+		}
+	}
+
+	// 証明書の確認間隔の検証
+	if config.CertCheckInterval <= 0 {
+		return fmt.Errorf("無効な証明書の確認間隔: %v", config.CertCheckInterval)
+	}
+	if config.WebhookConfigurationName != "" && config.CABundleCheckInterval <= 0 {
+		return fmt.Errorf("無効なcaBundleの確認間隔: %v", config.CABundleCheckInterval)
+	}
+
+	// リクエストボディの最大サイズの検証
+	if config.MaxRequestBodyBytes <= 0 {
+		return fmt.Errorf("無効なリクエストボディの最大サイズ: %d (1以上を指定してください)", config.MaxRequestBodyBytes)
+	}
+
+	// メッセージ言語の検証（空の場合はデフォルトの日本語）
+	validLanguages := []string{"ja", "en"}
+	if config.Language != "" && !contains(validLanguages, strings.ToLower(config.Language)) {
+		return fmt.Errorf("無効なメッセージ言語: %s (有効な値: %v)", config.Language, validLanguages)
+	}
+
+	// メッセージテンプレートの検証
+	if _, err := ParseMessageTemplates(config.MessageTemplates); err != nil {
+		return err
+	}
+
 	// メトリクスポートの検証
 	if config.MetricsPort <= 0 || config.MetricsPort > 65535 {
 		return fmt.Errorf("無効なメトリクスポート番号: %d", config.MetricsPort)
 	}
 
+	if config.MetricsRequesterNamespace && config.MetricsRequesterNamespaceLimit <= 0 {
+		return fmt.Errorf("metrics_requester_namespace_limit は1以上を指定してください: %d", config.MetricsRequesterNamespaceLimit)
+	}
+	if config.AuditEnabled && config.AuditInterval <= 0 {
+		return fmt.Errorf("無効な監査スキャンの間隔: %v", config.AuditInterval)
+	}
+	if config.PolicyReportEnabled && !config.AuditEnabled {
+		return fmt.Errorf("policy_report_enabled を有効にする場合は audit_enabled も有効にしてください")
+	}
+	if config.DecisionLogEnabled {
+		if config.DecisionLogPath == "" {
+			return fmt.Errorf("decision_log_enabled を有効にする場合は decision_log_path を指定してください")
+		}
+		if config.DecisionLogMaxSizeMB <= 0 {
+			return fmt.Errorf("decision_log_max_size_mb は1以上を指定してください: %d", config.DecisionLogMaxSizeMB)
+		}
+		if config.DecisionLogMaxAge <= 0 {
+			return fmt.Errorf("無効な決定ログのローテーション間隔: %v", config.DecisionLogMaxAge)
+		}
+		if config.DecisionLogMaxBackups < 0 {
+			return fmt.Errorf("decision_log_max_backups は0以上を指定してください: %d", config.DecisionLogMaxBackups)
+		}
+		if config.DecisionLogBufferSize <= 0 {
+			return fmt.Errorf("decision_log_buffer_size は1以上を指定してください: %d", config.DecisionLogBufferSize)
+		}
+	}
+	if config.NotificationsEnabled {
+		if !IsValidNotificationFormat(config.NotificationFormat) {
+			return fmt.Errorf("無効な通知の形式: %s (json, slack のいずれかを指定してください)", config.NotificationFormat)
+		}
+		if config.NotificationURL != "" {
+			if err := ValidateNotificationURL(config.NotificationURL); err != nil {
+				return err
+			}
+		}
+		if config.NotificationBatchWindow <= 0 {
+			return fmt.Errorf("無効な通知の間隔: %v", config.NotificationBatchWindow)
+		}
+		if config.NotificationQueueSize <= 0 {
+			return fmt.Errorf("notification_queue_size は1以上を指定してください: %d", config.NotificationQueueSize)
+		}
+		for _, prefix := range config.NotificationAllowedURLPrefixes {
+			if err := validateHTTPURL("通知先の接頭辞", prefix); err != nil {
+				return err
+			}
+		}
+	}
+	if config.CloudEventsEnabled {
+		if config.CloudEventsSinkURL == "" {
+			return fmt.Errorf("cloudevents_enabled が有効な場合は cloudevents_sink_url を指定してください")
+		}
+		if err := ValidateCloudEventsSinkURL(config.CloudEventsSinkURL); err != nil {
+			return err
+		}
+		if !IsValidCloudEventsMode(config.CloudEventsMode) {
+			return fmt.Errorf("無効なCloudEventsの送信モード: %s (binary, structured のいずれかを指定してください)", config.CloudEventsMode)
+		}
+		if config.CloudEventsBufferSize <= 0 {
+			return fmt.Errorf("cloudevents_buffer_size は1以上を指定してください: %d", config.CloudEventsBufferSize)
+		}
+	}
+
 	// ポートの重複チェック
 	if config.Port == config.MetricsPort {
 		return fmt.Errorf("webhookポートとメトリクスポートが重複しています: %d", config.Port)
@@ -438,6 +1379,17 @@ func (cl *ConfigLoader) validateConfig(config *WebhookConfig) error {
 	return nil
 }
 
+// splitAndTrim カンマ区切りの文字列を分割し、空白と空要素を除去
+func splitAndTrim(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 // contains スライスに指定された値が含まれているかチェック
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -454,14 +1406,67 @@ func (config *WebhookConfig) GetConfigSummary() map[string]interface{} {
 		"port":             config.Port,
 		"tls_cert_file":    config.TLSCertFile,
 		"tls_key_file":     config.TLSKeyFile,
+		"tls_ca_file":      config.TLSCAFile,
+		"tls_source":       config.TLSSource,
+		"tls_secret_name":  config.TLSSecretName,
+		"tls_secret_namespace": config.TLSSecretNamespace,
+		"tls_profile":      config.TLSProfile,
+		"tls_min_version":  config.TLSMinVersion,
+		"tls_cipher_suites": config.TLSCipherSuites,
+		"tls_curve_preferences": config.TLSCurvePreferences,
+		"client_auth_enabled": config.ClientAuthEnabled,
+		"client_ca_file":   config.ClientCAFile,
+		"client_allowed_names": config.ClientAllowedNames,
+		"cert_check_interval": config.CertCheckInterval.String(),
+		"cert_watch_enabled": config.CertWatchEnabled,
+		"cert_bootstrap":   config.CertBootstrap,
+		"cert_bootstrap_service": config.CertBootstrapService,
+		"webhook_configuration_name": config.WebhookConfigurationName,
+		"ca_bundle_check_interval": config.CABundleCheckInterval.String(),
+		"kubeconfig":       config.Kubeconfig,
 		"timeout":          config.Timeout.String(),
+		"read_header_timeout": config.ReadHeaderTimeout.String(),
+		"read_timeout":     config.ReadTimeout.String(),
+		"write_timeout":    config.WriteTimeout.String(),
+		"idle_timeout":     config.IdleTimeout.String(),
+		"max_request_body_bytes": config.MaxRequestBodyBytes,
 		"log_level":        config.LogLevel,
 		"log_format":       config.LogFormat,
 		"skip_namespaces":  config.SkipNamespaces,
 		"skip_labels":      config.SkipLabels,
+		"min_replicas":     config.MinReplicas,
+		"exempt_users": config.ExemptUsers,
+		"exempt_groups": config.ExemptGroups,
+		"exempt_service_accounts": config.ExemptServiceAccounts,
+		"mutation_enabled": config.MutationEnabled,
+		"mutation_namespaces": config.MutationNamespaces,
+		"language":         config.Language,
 		"metrics_enabled":  config.MetricsEnabled,
 		"metrics_port":     config.MetricsPort,
 		"health_enabled":   config.HealthEnabled,
+		"metrics_requester_namespace": config.MetricsRequesterNamespace,
+		"metrics_requester_namespace_limit": config.MetricsRequesterNamespaceLimit,
+		"events_enabled": config.EventsEnabled,
+		"audit_enabled": config.AuditEnabled,
+		"audit_interval": config.AuditInterval.String(),
+		"policy_report_enabled": config.PolicyReportEnabled,
+		"leader_election_namespace": config.LeaderElectionNamespace,
+		"decision_log_enabled": config.DecisionLogEnabled,
+		"decision_log_path": config.DecisionLogPath,
+		"decision_log_max_size_mb": config.DecisionLogMaxSizeMB,
+		"decision_log_max_age": config.DecisionLogMaxAge.String(),
+		"decision_log_max_backups": config.DecisionLogMaxBackups,
+		"decision_log_buffer_size": config.DecisionLogBufferSize,
+		"notifications_enabled": config.NotificationsEnabled,
+		"notification_url_set": config.NotificationURL != "",
+		"notification_format": config.NotificationFormat,
+		"notification_batch_window": config.NotificationBatchWindow.String(),
+		"notification_queue_size": config.NotificationQueueSize,
+		"notification_allowed_url_prefixes": config.NotificationAllowedURLPrefixes,
+		"cloudevents_enabled": config.CloudEventsEnabled,
+		"cloudevents_mode": config.CloudEventsMode,
+		"cloudevents_all_decisions": config.CloudEventsAllDecisions,
+		"cloudevents_buffer_size": config.CloudEventsBufferSize,
 		"environment":      config.Environment,
 		"cluster_name":     config.ClusterName,
 		"failure_policy":   config.FailurePolicy,
@@ -488,6 +1493,18 @@ func (config *WebhookConfig) ShouldSkipNamespace(namespace string) bool {
 	return false
 }
 
+// ShouldMutateNamespace 指定されたnamespaceでミューテーションを行うかどうかを判定
+// MutationNamespacesが空の場合は全てのnamespaceが対象
+func (config *WebhookConfig) ShouldMutateNamespace(namespace string) bool {
+	if !config.MutationEnabled {
+		return false
+	}
+	if len(config.MutationNamespaces) == 0 {
+		return true
+	}
+	return contains(config.MutationNamespaces, namespace)
+}
+
 // ShouldSkipByLabel 指定されたラベルでスキップするかどうかを判定
 func (config *WebhookConfig) ShouldSkipByLabel(labels map[string]string) bool {
 	for _, skipLabel := range config.SkipLabels {
@@ -27,6 +27,11 @@ func TestConfigLoader_LoadConfig_Defaults(t *testing.T) {
 	if config.Timeout != 10*time.Second {
 		t.Errorf("期待されるタイムアウト: 10s, 実際: %v", config.Timeout)
 	}
+	if config.ReadHeaderTimeout != 5*time.Second || config.ReadTimeout != 10*time.Second ||
+		config.WriteTimeout != 15*time.Second || config.IdleTimeout != 60*time.Second {
+		t.Errorf("期待されるHTTPタイムアウト: 5s/10s/15s/60s, 実際: %v/%v/%v/%v",
+			config.ReadHeaderTimeout, config.ReadTimeout, config.WriteTimeout, config.IdleTimeout)
+	}
 	if config.LogLevel != "info" {
 		t.Errorf("期待されるログレベル: info, 実際: %s", config.LogLevel)
 	}
@@ -93,6 +98,7 @@ func TestConfigLoader_LoadConfig_FromConfigMap(t *testing.T) {
 		"metrics.port":                "9090",
 		"environment":                 "staging",
 		"webhook.failure-policy":      "Ignore",
+		"audit.leader-election-namespace": "webhook-system",
 	}
 
 	loader := NewConfigLoaderWithConfigMap(configMapData)
@@ -123,6 +129,9 @@ func TestConfigLoader_LoadConfig_FromConfigMap(t *testing.T) {
 	if config.FailurePolicy != "Ignore" {
 		t.Errorf("期待される失敗ポリシー: Ignore, 実際: %s", config.FailurePolicy)
 	}
+	if config.LeaderElectionNamespace != "webhook-system" {
+		t.Errorf("期待されるLeaseのnamespace: webhook-system, 実際: %s", config.LeaderElectionNamespace)
+	}
 }
 
 func TestConfigLoader_LoadConfig_EnvOverridesConfigMap(t *testing.T) {
@@ -210,6 +219,174 @@ func TestConfigLoader_ValidateConfig_InvalidValues(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "無効なreplica数の下限",
+			setupConfig: func(c *WebhookConfig) {
+				c.MinReplicas = 1
+			},
+			expectError: true,
+		},
+		{
+			name: "無効なメッセージ言語",
+			setupConfig: func(c *WebhookConfig) {
+				c.Language = "fr"
+			},
+			expectError: true,
+		},
+		{
+			name: "負のHTTPタイムアウト",
+			setupConfig: func(c *WebhookConfig) {
+				c.ReadHeaderTimeout = -time.Second
+			},
+			expectError: true,
+		},
+		{
+			name: "書き込みタイムアウトが処理期限以下",
+			setupConfig: func(c *WebhookConfig) {
+				c.Timeout = 10 * time.Second
+				c.WriteTimeout = 10 * time.Second
+			},
+			expectError: true,
+		},
+		{
+			name: "無効なリクエストボディの最大サイズ",
+			setupConfig: func(c *WebhookConfig) {
+				c.MaxRequestBodyBytes = 0
+			},
+			expectError: true,
+		},
+		{
+			name: "無効な証明書の読み込み元",
+			setupConfig: func(c *WebhookConfig) {
+				c.TLSSource = "vault"
+			},
+			expectError: true,
+		},
+		{
+			name: "Secret名のない証明書の読み込み元",
+			setupConfig: func(c *WebhookConfig) {
+				c.TLSSource = TLSSourceSecret
+				c.TLSSecretNamespace = "webhook-system"
+			},
+			expectError: true,
+		},
+		{
+			name: "ファイルからの読み込みでの証明書の自動発行",
+			setupConfig: func(c *WebhookConfig) {
+				c.CertBootstrap = true
+				c.CertBootstrapService = "k8s-deployment-hpa-validator"
+				c.WebhookConfigurationName = "k8s-deployment-hpa-validator"
+			},
+			expectError: true,
+		},
+		{
+			name: "Service名のない証明書の自動発行",
+			setupConfig: func(c *WebhookConfig) {
+				c.TLSSource = TLSSourceSecret
+				c.TLSSecretName = "webhook-tls"
+				c.TLSSecretNamespace = "webhook-system"
+				c.CertBootstrap = true
+				c.WebhookConfigurationName = "k8s-deployment-hpa-validator"
+			},
+			expectError: true,
+		},
+		{
+			name: "CA証明書のないクライアント認証",
+			setupConfig: func(c *WebhookConfig) {
+				c.ClientAuthEnabled = true
+			},
+			expectError: true,
+		},
+		{
+			name: "無効なクライアント名のパターン",
+			setupConfig: func(c *WebhookConfig) {
+				c.ClientAuthEnabled = true
+				c.ClientCAFile = "/etc/webhook/client-ca/ca.crt"
+				c.ClientAllowedNames = []string{"kube-apiserver", "[invalid"}
+			},
+			expectError: true,
+		},
+		{
+			name: "呼び出し元のnamespaceのラベルの上限が0",
+			setupConfig: func(c *WebhookConfig) {
+				c.MetricsRequesterNamespace = true
+				c.MetricsRequesterNamespaceLimit = 0
+			},
+			expectError: true,
+		},
+		{
+			name: "監査スキャンの間隔が0",
+			setupConfig: func(c *WebhookConfig) {
+				c.AuditEnabled = true
+				c.AuditInterval = 0
+			},
+			expectError: true,
+		},
+		{
+			name: "監査スキャンなしのPolicyReport",
+			setupConfig: func(c *WebhookConfig) {
+				c.AuditEnabled = false
+				c.PolicyReportEnabled = true
+			},
+			expectError: true,
+		},
+		{
+			name: "決定ログのバッファサイズが0",
+			setupConfig: func(c *WebhookConfig) {
+				c.DecisionLogEnabled = true
+				c.DecisionLogBufferSize = 0
+			},
+			expectError: true,
+		},
+		{
+			name: "無効な通知の形式",
+			setupConfig: func(c *WebhookConfig) {
+				c.NotificationsEnabled = true
+				c.NotificationFormat = "teams"
+			},
+			expectError: true,
+		},
+		{
+			name: "通知先のURLがhttpでない",
+			setupConfig: func(c *WebhookConfig) {
+				c.NotificationsEnabled = true
+				c.NotificationURL = "ftp://example.com/hook"
+			},
+			expectError: true,
+		},
+		{
+			name: "通知先の接頭辞がURLでない",
+			setupConfig: func(c *WebhookConfig) {
+				c.NotificationsEnabled = true
+				c.NotificationAllowedURLPrefixes = []string{"hooks.slack.com"}
+			},
+			expectError: true,
+		},
+		{
+			name: "CloudEventsの送信先が未指定",
+			setupConfig: func(c *WebhookConfig) {
+				c.CloudEventsEnabled = true
+				c.CloudEventsSinkURL = ""
+			},
+			expectError: true,
+		},
+		{
+			name: "無効なCloudEventsの送信モード",
+			setupConfig: func(c *WebhookConfig) {
+				c.CloudEventsEnabled = true
+				c.CloudEventsSinkURL = "http://broker.knative-eventing.svc.cluster.local/default"
+				c.CloudEventsMode = "batched"
+			},
+			expectError: true,
+		},
+		{
+			name: "決定ログのパスが空",
+			setupConfig: func(c *WebhookConfig) {
+				c.DecisionLogEnabled = true
+				c.DecisionLogPath = ""
+			},
+			expectError: true,
+		},
 		{
 			name: "ポートの重複",
 			setupConfig: func(c *WebhookConfig) {
@@ -278,6 +455,52 @@ func TestWebhookConfig_HelperMethods(t *testing.T) {
 	}
 }
 
+func TestWebhookConfig_ShouldMutateNamespace(t *testing.T) {
+	config := &WebhookConfig{}
+	if config.ShouldMutateNamespace("default") {
+		t.Error("ミューテーションが無効な場合はfalseを返すべきです")
+	}
+
+	config.MutationEnabled = true
+	if !config.ShouldMutateNamespace("default") {
+		t.Error("MutationNamespacesが空の場合は全てのnamespaceが対象です")
+	}
+
+	config.MutationNamespaces = []string{"team-a", "team-b"}
+	if !config.ShouldMutateNamespace("team-b") {
+		t.Error("team-bはミューテーションの対象です")
+	}
+	if config.ShouldMutateNamespace("default") {
+		t.Error("defaultはミューテーションの対象外です")
+	}
+}
+
+func TestIsAllowedNotificationURL(t *testing.T) {
+	prefixes := []string{"https://hooks.slack.com/services/", "https://alerts.example.com/hooks"}
+	tests := []struct {
+		url      string
+		expected bool
+	}{
+		{url: "https://hooks.slack.com/services/T000/B000/XXX", expected: true},
+		{url: "https://alerts.example.com/hooks", expected: true},
+		{url: "https://alerts.example.com/hooks/team-a", expected: true},
+		{url: "https://alerts.example.com/hooks-other", expected: false},
+		{url: "https://hooks.slack.com.attacker.test/services/T000", expected: false},
+		{url: "https://hooks.slack.com@attacker.test/services/T000", expected: false},
+		{url: "http://hooks.slack.com/services/T000", expected: false},
+		{url: "http://kube-apiserver.default.svc/api", expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsAllowedNotificationURL(tt.url, prefixes); got != tt.expected {
+			t.Errorf("IsAllowedNotificationURL(%q) = %v, expected %v", tt.url, got, tt.expected)
+		}
+	}
+	if IsAllowedNotificationURL("https://hooks.slack.com/services/T000", nil) {
+		t.Error("接頭辞が空の場合はどのURLも許可しないべきです")
+	}
+}
+
 func TestConfigLoader_LoadConfig_InvalidEnvValues(t *testing.T) {
 	testCases := []struct {
 		name   string
@@ -286,6 +509,8 @@ func TestConfigLoader_LoadConfig_InvalidEnvValues(t *testing.T) {
 	}{
 		{"無効なポート", "WEBHOOK_PORT", "invalid"},
 		{"無効なタイムアウト", "WEBHOOK_TIMEOUT", "invalid"},
+		{"無効な書き込みタイムアウト", "WEBHOOK_WRITE_TIMEOUT", "invalid"},
+		{"無効なリクエストボディの最大サイズ", "WEBHOOK_MAX_REQUEST_BODY_BYTES", "invalid"},
 		{"無効なメトリクスポート", "METRICS_PORT", "invalid"},
 	}
 
@@ -434,4 +659,32 @@ func TestConfigLoader_LoadFromYAMLFile_NonExistentFile(t *testing.T) {
 	if config.Environment != "development" {
 		t.Errorf("期待される環境: development, 実際: %s", config.Environment)
 	}
-}
\ No newline at end of file
+}
+func TestConfigLoader_LoadFromYAMLFile_DecisionLogMaxBackups(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected int
+	}{
+		{name: "未指定の場合はデフォルト値", yaml: "environment: development\n", expected: 5},
+		{name: "0を指定した場合は削除しない", yaml: "environment: development\ndecision_log_max_backups: 0\n", expected: 0},
+		{name: "値を指定した場合はその値", yaml: "environment: development\ndecision_log_max_backups: 10\n", expected: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.yaml), 0644); err != nil {
+				t.Fatalf("テスト設定ファイルの作成に失敗しました: %v", err)
+			}
+
+			config, err := NewConfigLoaderWithFile(configPath).LoadConfig()
+			if err != nil {
+				t.Fatalf("設定の読み込みに失敗しました: %v", err)
+			}
+			if config.DecisionLogMaxBackups != tt.expected {
+				t.Errorf("期待されるdecision_log_max_backups: %d, 実際: %d", tt.expected, config.DecisionLogMaxBackups)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	// ExemptionKindUser ExemptUsersに一致した呼び出し元
+	ExemptionKindUser = "user"
+	// ExemptionKindGroup ExemptGroupsに一致した呼び出し元
+	ExemptionKindGroup = "group"
+	// ExemptionKindServiceAccount ExemptServiceAccountsに一致した呼び出し元
+	ExemptionKindServiceAccount = "serviceaccount"
+)
+
+// serviceAccountUsernamePrefix サービスアカウントのユーザー名の接頭辞（system:serviceaccount:<namespace>:<name>）
+const serviceAccountUsernamePrefix = "system:serviceaccount:"
+
+// Exemption 呼び出し元が一致した検証の対象外の設定
+type Exemption struct {
+	Kind string
+	// Pattern 一致した設定のパターン
+	Pattern string
+}
+
+// String "<種類>:<パターン>" 形式の文字列（メトリクスのラベルと監査アノテーションに使用）
+func (e Exemption) String() string {
+	return e.Kind + ":" + e.Pattern
+}
+
+// MatchExemption 呼び出し元のユーザー名とグループが検証の対象外の設定に一致するかどうか
+// ユーザー、サービスアカウント、グループの順に確認し、最初に一致した設定を返す
+func (config *WebhookConfig) MatchExemption(username string, groups []string) (Exemption, bool) {
+	for _, pattern := range config.ExemptUsers {
+		if matchPattern(pattern, username) {
+			return Exemption{Kind: ExemptionKindUser, Pattern: pattern}, true
+		}
+	}
+
+	if namespace, name, ok := ParseServiceAccountUsername(username); ok {
+		for _, pattern := range config.ExemptServiceAccounts {
+			if matchServiceAccount(pattern, namespace, name) {
+				return Exemption{Kind: ExemptionKindServiceAccount, Pattern: pattern}, true
+			}
+		}
+	}
+
+	for _, pattern := range config.ExemptGroups {
+		for _, group := range groups {
+			if matchPattern(pattern, group) {
+				return Exemption{Kind: ExemptionKindGroup, Pattern: pattern}, true
+			}
+		}
+	}
+
+	return Exemption{}, false
+}
+
+// validateExemptions 検証の対象外の設定のパターンを検証
+func (config *WebhookConfig) validateExemptions() error {
+	for _, pattern := range append(append([]string{}, config.ExemptUsers...), config.ExemptGroups...) {
+		if pattern == "" {
+			return fmt.Errorf("exempt_users・exempt_groups に空のパターンは指定できません")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("無効なユーザー・グループのパターン: %s", pattern)
+		}
+	}
+
+	for _, pattern := range config.ExemptServiceAccounts {
+		namespace, name, ok := strings.Cut(pattern, ":")
+		if !ok || namespace == "" || name == "" || strings.Contains(name, ":") {
+			return fmt.Errorf("無効なサービスアカウント: %s (<namespace>:<name> の形式で指定してください)", pattern)
+		}
+		if _, err := path.Match(namespace, ""); err != nil {
+			return fmt.Errorf("無効なサービスアカウントのパターン: %s", pattern)
+		}
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("無効なサービスアカウントのパターン: %s", pattern)
+		}
+	}
+	return nil
+}
+
+// ParseServiceAccountUsername サービスアカウントのユーザー名（system:serviceaccount:<namespace>:<name>）からnamespaceと名前を取得
+func ParseServiceAccountUsername(username string) (namespace, name string, ok bool) {
+	serviceAccount, ok := strings.CutPrefix(username, serviceAccountUsernamePrefix)
+	if !ok {
+		return "", "", false
+	}
+	namespace, name, ok = strings.Cut(serviceAccount, ":")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, ":") {
+		return "", "", false
+	}
+	return namespace, name, true
+}
+
+// matchServiceAccount "<namespace>:<name>" のパターンとサービスアカウントを、namespaceと名前ごとに照合
+func matchServiceAccount(pattern, namespace, name string) bool {
+	patternNamespace, patternName, ok := strings.Cut(pattern, ":")
+	if !ok {
+		return false
+	}
+	return matchPattern(patternNamespace, namespace) && matchPattern(patternName, name)
+}
+
+// matchPattern globパターン（path.Matchの形式）に一致するかどうか
+func matchPattern(pattern, value string) bool {
+	if value == "" {
+		return false
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
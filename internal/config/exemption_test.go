@@ -0,0 +1,69 @@
+package config
+
+import "testing"
+
+func TestWebhookConfig_MatchExemption(t *testing.T) {
+	config := &WebhookConfig{
+		ExemptUsers:           []string{"admin@example.com", "oncall-*@example.com"},
+		ExemptGroups:          []string{"system:masters", "sre-*"},
+		ExemptServiceAccounts: []string{"ops:break-glass", "*:emergency-*"},
+	}
+
+	tests := []struct {
+		name      string
+		username  string
+		groups    []string
+		expected  string
+		expectHit bool
+	}{
+		{name: "ユーザー名の完全一致", username: "admin@example.com", expected: "user:admin@example.com", expectHit: true},
+		{name: "ユーザー名のglob", username: "oncall-alice@example.com", expected: "user:oncall-*@example.com", expectHit: true},
+		{name: "グループ", username: "alice", groups: []string{"system:authenticated", "system:masters"}, expected: "group:system:masters", expectHit: true},
+		{name: "グループのglob", username: "bob", groups: []string{"sre-tokyo"}, expected: "group:sre-*", expectHit: true},
+		{name: "サービスアカウント", username: "system:serviceaccount:ops:break-glass", expected: "serviceaccount:ops:break-glass", expectHit: true},
+		{name: "サービスアカウントのnamespaceのglob", username: "system:serviceaccount:payments:emergency-deployer", expected: "serviceaccount:*:emergency-*", expectHit: true},
+		{name: "ユーザーがグループより優先", username: "admin@example.com", groups: []string{"system:masters"}, expected: "user:admin@example.com", expectHit: true},
+		{name: "別のnamespaceのサービスアカウント", username: "system:serviceaccount:default:break-glass"},
+		{name: "サービスアカウントの名前の一部のみ一致", username: "system:serviceaccount:ops:break-glass-2"},
+		{name: "サービスアカウントでないユーザー名", username: "ops:break-glass"},
+		{name: "一致しないユーザー", username: "developer@example.com", groups: []string{"system:authenticated"}},
+		{name: "空のユーザー名", username: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exemption, ok := config.MatchExemption(tt.username, tt.groups)
+			if ok != tt.expectHit {
+				t.Fatalf("MatchExemption() = %v, %v, expected match %v", exemption, ok, tt.expectHit)
+			}
+			if ok && exemption.String() != tt.expected {
+				t.Errorf("MatchExemption() = %s, expected %s", exemption, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWebhookConfig_validateExemptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      WebhookConfig
+		expectError bool
+	}{
+		{name: "未指定", config: WebhookConfig{}},
+		{name: "有効なパターン", config: WebhookConfig{ExemptUsers: []string{"admin*"}, ExemptGroups: []string{"system:masters"}, ExemptServiceAccounts: []string{"ops:*"}}},
+		{name: "無効なユーザーのパターン", config: WebhookConfig{ExemptUsers: []string{"[admin"}}, expectError: true},
+		{name: "空のグループ", config: WebhookConfig{ExemptGroups: []string{""}}, expectError: true},
+		{name: "namespaceのないサービスアカウント", config: WebhookConfig{ExemptServiceAccounts: []string{"break-glass"}}, expectError: true},
+		{name: "ユーザー名形式のサービスアカウント", config: WebhookConfig{ExemptServiceAccounts: []string{"system:serviceaccount:ops:break-glass"}}, expectError: true},
+		{name: "無効なサービスアカウントのパターン", config: WebhookConfig{ExemptServiceAccounts: []string{"ops:[break"}}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validateExemptions()
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateExemptions() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
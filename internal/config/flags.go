@@ -0,0 +1,225 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Flags コマンドライン引数による設定
+//
+// 優先順位（後のものが優先）:
+//  1. デフォルト値
+//  2. 設定ファイル（--config、CONFIG_FILE、configs/<環境>.yamlの順に決定）
+//  3. 環境変数
+//  4. コマンドライン引数（明示的に指定されたもののみ）
+type Flags struct {
+	// ConfigFile 設定ファイルのパス（--config）
+	ConfigFile string
+
+	// Environment 環境名（--environment）。設定ファイルの選択にも使用する
+	Environment string
+
+	// overrides 明示的に指定された引数の適用処理（指定順）
+	overrides []func(*WebhookConfig)
+}
+
+// RegisterFlags WebhookConfigの全フィールドを上書きする引数をFlagSetに登録
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+
+	fs.StringVar(&f.ConfigFile, "config", "", "設定ファイル（YAML）のパス")
+	fs.Func("environment", "環境 (development, staging, production)。未指定時は configs/<環境>.yaml を読み込む", func(value string) error {
+		f.Environment = value
+		f.set(func(c *WebhookConfig) { c.Environment = value })
+		return nil
+	})
+
+	// サーバー設定
+	f.intFlag(fs, "port", "webhookサーバーのポート番号", func(c *WebhookConfig, v int) { c.Port = v })
+	f.stringFlag(fs, "cert-file", "TLS証明書ファイル", func(c *WebhookConfig, v string) { c.TLSCertFile = v })
+	f.stringFlag(fs, "key-file", "TLS秘密鍵ファイル", func(c *WebhookConfig, v string) { c.TLSKeyFile = v })
+	f.stringFlag(fs, "ca-file", "証明書チェーン検証用のCA証明書ファイル", func(c *WebhookConfig, v string) { c.TLSCAFile = v })
+	f.stringFlag(fs, "tls-source", "証明書の読み込み元 (file, secret)", func(c *WebhookConfig, v string) { c.TLSSource = v })
+	f.stringFlag(fs, "tls-secret-name", "証明書を読み込むSecretの名前（tls-source=secretの場合）", func(c *WebhookConfig, v string) { c.TLSSecretName = v })
+	f.stringFlag(fs, "tls-secret-namespace", "証明書を読み込むSecretのnamespace（tls-source=secretの場合）", func(c *WebhookConfig, v string) { c.TLSSecretNamespace = v })
+	f.stringFlag(fs, "tls-profile", "TLSプロファイル (modern, intermediate, custom)", func(c *WebhookConfig, v string) { c.TLSProfile = v })
+	f.stringFlag(fs, "tls-min-version", "TLSの最小バージョン (1.2, 1.3)（tls-profile=customの場合）", func(c *WebhookConfig, v string) { c.TLSMinVersion = v })
+	f.listFlag(fs, "tls-cipher-suites", "TLS 1.2の暗号スイート（カンマ区切り、tls-profile=customの場合）", func(c *WebhookConfig, v []string) { c.TLSCipherSuites = v })
+	f.listFlag(fs, "tls-curve-preferences", "鍵交換に使用する楕円曲線 (X25519, P256, P384, P521)（カンマ区切り、tls-profile=customの場合）", func(c *WebhookConfig, v []string) { c.TLSCurvePreferences = v })
+	f.boolFlag(fs, "client-auth-enabled", "クライアント証明書（mTLS）で呼び出し元を認証する", func(c *WebhookConfig, v bool) { c.ClientAuthEnabled = v })
+	f.stringFlag(fs, "client-ca-file", "クライアント証明書の検証に使用するCA証明書ファイル", func(c *WebhookConfig, v string) { c.ClientCAFile = v })
+	f.listFlag(fs, "client-allowed-names", "許可するクライアント証明書のCN・SAN（globパターン、カンマ区切り）", func(c *WebhookConfig, v []string) { c.ClientAllowedNames = v })
+	f.durationFlag(fs, "cert-check-interval", "証明書ファイルの変更を確認する間隔", func(c *WebhookConfig, v time.Duration) { c.CertCheckInterval = v })
+	f.boolFlag(fs, "cert-watch-enabled", "ファイル変更通知（inotify）で証明書を即時に再読み込みする", func(c *WebhookConfig, v bool) { c.CertWatchEnabled = v })
+	f.boolFlag(fs, "cert-bootstrap", "自己署名CAで証明書を発行してSecretに保存し、caBundleを設定する（tls-source=secretの場合）", func(c *WebhookConfig, v bool) { c.CertBootstrap = v })
+	f.stringFlag(fs, "cert-bootstrap-service", "発行する証明書の対象となるService名", func(c *WebhookConfig, v string) { c.CertBootstrapService = v })
+	f.stringFlag(fs, "webhook-configuration-name", "このwebhookのValidatingWebhookConfigurationの名前", func(c *WebhookConfig, v string) { c.WebhookConfigurationName = v })
+	f.durationFlag(fs, "ca-bundle-check-interval", "webhook設定のcaBundleでサーバー証明書を検証できるかを確認する間隔", func(c *WebhookConfig, v time.Duration) { c.CABundleCheckInterval = v })
+	f.durationFlag(fs, "timeout", "リクエストごとの処理期限（例: 10s）", func(c *WebhookConfig, v time.Duration) { c.Timeout = v })
+	f.durationFlag(fs, "read-header-timeout", "リクエストヘッダーの読み込みタイムアウト", func(c *WebhookConfig, v time.Duration) { c.ReadHeaderTimeout = v })
+	f.durationFlag(fs, "read-timeout", "リクエストの読み込みタイムアウト", func(c *WebhookConfig, v time.Duration) { c.ReadTimeout = v })
+	f.durationFlag(fs, "write-timeout", "レスポンスの書き込みタイムアウト", func(c *WebhookConfig, v time.Duration) { c.WriteTimeout = v })
+	f.durationFlag(fs, "idle-timeout", "キープアライブ接続のアイドルタイムアウト", func(c *WebhookConfig, v time.Duration) { c.IdleTimeout = v })
+	f.intFlag(fs, "max-request-body-bytes", "リクエストボディの最大サイズ（バイト）", func(c *WebhookConfig, v int) { c.MaxRequestBodyBytes = int64(v) })
+
+	// Kubernetes API設定
+	f.stringFlag(fs, "kubeconfig", "kubeconfigのパス（未指定時はクラスター内設定を使用）", func(c *WebhookConfig, v string) { c.Kubeconfig = v })
+
+	// ログ設定
+	f.stringFlag(fs, "log-level", "ログレベル (debug, info, warn, error)", func(c *WebhookConfig, v string) { c.LogLevel = v })
+	f.stringFlag(fs, "log-format", "ログフォーマット (json, text)", func(c *WebhookConfig, v string) { c.LogFormat = v })
+
+	// バリデーション設定
+	f.listFlag(fs, "skip-namespaces", "バリデーションをスキップする名前空間（カンマ区切り、設定を置き換える）", func(c *WebhookConfig, v []string) { c.SkipNamespaces = v })
+	f.listFlag(fs, "skip-labels", "バリデーションをスキップするラベル（key=value、カンマ区切り、設定を置き換える）", func(c *WebhookConfig, v []string) { c.SkipLabels = v })
+	f.intFlag(fs, "min-replicas", "HPAの対象となるDeploymentに要求されるreplica数の下限", func(c *WebhookConfig, v int) { c.MinReplicas = v })
+	f.listFlag(fs, "exempt-users", "検証の対象外とするユーザー（globパターン、カンマ区切り）", func(c *WebhookConfig, v []string) { c.ExemptUsers = v })
+	f.listFlag(fs, "exempt-groups", "検証の対象外とするグループ（globパターン、カンマ区切り）", func(c *WebhookConfig, v []string) { c.ExemptGroups = v })
+	f.listFlag(fs, "exempt-service-accounts", "検証の対象外とするサービスアカウント（<namespace>:<name>、globパターン、カンマ区切り）", func(c *WebhookConfig, v []string) { c.ExemptServiceAccounts = v })
+
+	// ミューテーション設定
+	f.boolFlag(fs, "mutation-enabled", "違反の自動修正を有効にする", func(c *WebhookConfig, v bool) { c.MutationEnabled = v })
+	f.listFlag(fs, "mutation-namespaces", "自動修正の対象とする名前空間（カンマ区切り）", func(c *WebhookConfig, v []string) { c.MutationNamespaces = v })
+
+	// メッセージ設定
+	f.stringFlag(fs, "language", "メッセージの既定の言語 (ja, en)", func(c *WebhookConfig, v string) { c.Language = v })
+	fs.Func("message-template", "エラーコードごとのメッセージテンプレート（<エラーコード>=<テンプレート>、複数指定可）", func(value string) error {
+		code, tmpl, ok := strings.Cut(value, "=")
+		if !ok || code == "" {
+			return fmt.Errorf("<エラーコード>=<テンプレート> の形式で指定してください: %s", value)
+		}
+		f.set(func(c *WebhookConfig) {
+			if c.MessageTemplates == nil {
+				c.MessageTemplates = map[string]string{}
+			}
+			c.MessageTemplates[code] = tmpl
+		})
+		return nil
+	})
+
+	// 監視設定
+	f.boolFlag(fs, "metrics-enabled", "Prometheusメトリクスを有効にする", func(c *WebhookConfig, v bool) { c.MetricsEnabled = v })
+	f.intFlag(fs, "metrics-port", "メトリクスエンドポイントのポート番号", func(c *WebhookConfig, v int) { c.MetricsPort = v })
+	f.boolFlag(fs, "health-enabled", "ヘルスチェックエンドポイントを有効にする", func(c *WebhookConfig, v bool) { c.HealthEnabled = v })
+	f.boolFlag(fs, "metrics-requester-namespace", "拒否のメトリクスに呼び出し元のサービスアカウントのnamespaceのラベルを付与する", func(c *WebhookConfig, v bool) { c.MetricsRequesterNamespace = v })
+	f.intFlag(fs, "metrics-requester-namespace-limit", "呼び出し元のnamespaceのラベルの値の種類の上限", func(c *WebhookConfig, v int) { c.MetricsRequesterNamespaceLimit = v })
+	f.boolFlag(fs, "events-enabled", "拒否や警告付きの許可をKubernetesのWarning Eventとして記録する", func(c *WebhookConfig, v bool) { c.EventsEnabled = v })
+
+	// 監査設定
+	f.boolFlag(fs, "audit-enabled", "既存のDeploymentとHPAの違反を定期的に検出する", func(c *WebhookConfig, v bool) { c.AuditEnabled = v })
+	f.durationFlag(fs, "audit-interval", "既存のリソースの違反を検出する間隔", func(c *WebhookConfig, v time.Duration) { c.AuditInterval = v })
+	f.boolFlag(fs, "policy-report-enabled", "監査スキャンの結果をnamespaceごとのPolicyReportに書き込む", func(c *WebhookConfig, v bool) { c.PolicyReportEnabled = v })
+	f.stringFlag(fs, "leader-election-namespace", "PolicyReportを書き込むレプリカを選出するLeaseのnamespace", func(c *WebhookConfig, v string) { c.LeaderElectionNamespace = v })
+
+	// 決定ログ設定
+	f.boolFlag(fs, "decision-log-enabled", "リクエストごとの判定をJSON Lines形式でファイルに記録する", func(c *WebhookConfig, v bool) { c.DecisionLogEnabled = v })
+	f.stringFlag(fs, "decision-log-path", "決定ログのファイルパス", func(c *WebhookConfig, v string) { c.DecisionLogPath = v })
+	f.intFlag(fs, "decision-log-max-size-mb", "決定ログをローテーションするサイズ（MB）", func(c *WebhookConfig, v int) { c.DecisionLogMaxSizeMB = v })
+	f.durationFlag(fs, "decision-log-max-age", "決定ログをローテーションする経過時間", func(c *WebhookConfig, v time.Duration) { c.DecisionLogMaxAge = v })
+	f.intFlag(fs, "decision-log-max-backups", "保持するローテーション済みの決定ログの数", func(c *WebhookConfig, v int) { c.DecisionLogMaxBackups = v })
+	f.intFlag(fs, "decision-log-buffer-size", "書き込みを待つ判定の上限", func(c *WebhookConfig, v int) { c.DecisionLogBufferSize = v })
+	f.boolFlag(fs, "notifications-enabled", "拒否の概要をHTTPエンドポイントに通知する", func(c *WebhookConfig, v bool) { c.NotificationsEnabled = v })
+	f.stringFlag(fs, "notification-url", "既定の通知先のURL", func(c *WebhookConfig, v string) { c.NotificationURL = v })
+	f.stringFlag(fs, "notification-format", "既定の通知の形式 (json, slack)", func(c *WebhookConfig, v string) { c.NotificationFormat = v })
+	f.durationFlag(fs, "notification-batch-window", "拒否をまとめて通知する間隔", func(c *WebhookConfig, v time.Duration) { c.NotificationBatchWindow = v })
+	f.intFlag(fs, "notification-queue-size", "通知を待つ拒否の上限", func(c *WebhookConfig, v int) { c.NotificationQueueSize = v })
+	f.listFlag(fs, "notification-allowed-url-prefixes", "namespaceのアノテーションで指定できる通知先のURLの接頭辞（カンマ区切り）", func(c *WebhookConfig, v []string) { c.NotificationAllowedURLPrefixes = v })
+	f.boolFlag(fs, "cloudevents-enabled", "拒否をCloudEventとして送信する", func(c *WebhookConfig, v bool) { c.CloudEventsEnabled = v })
+	f.stringFlag(fs, "cloudevents-sink-url", "CloudEventsの送信先のURL", func(c *WebhookConfig, v string) { c.CloudEventsSinkURL = v })
+	f.stringFlag(fs, "cloudevents-mode", "CloudEventsの送信モード (binary, structured)", func(c *WebhookConfig, v string) { c.CloudEventsMode = v })
+	f.boolFlag(fs, "cloudevents-all-decisions", "拒否に加えて許可した判定もCloudEventとして送信する", func(c *WebhookConfig, v bool) { c.CloudEventsAllDecisions = v })
+	f.intFlag(fs, "cloudevents-buffer-size", "送信を待つCloudEventの上限", func(c *WebhookConfig, v int) { c.CloudEventsBufferSize = v })
+
+	// 環境情報・失敗ポリシー
+	f.stringFlag(fs, "cluster-name", "クラスター名", func(c *WebhookConfig, v string) { c.ClusterName = v })
+	f.stringFlag(fs, "failure-policy", "失敗ポリシー (Fail, Ignore)", func(c *WebhookConfig, v string) { c.FailurePolicy = v })
+
+	return f
+}
+
+// LoadConfig 設定ファイル・環境変数・コマンドライン引数から設定を読み込み、検証する
+func (f *Flags) LoadConfig() (*WebhookConfig, error) {
+	configFile := f.ConfigFile
+	if configFile != "" {
+		// 明示的に指定された設定ファイルが存在しない場合はエラー
+		if _, err := os.Stat(configFile); err != nil {
+			return nil, fmt.Errorf("設定ファイルを読み込めません (%s): %w", configFile, err)
+		}
+	} else if f.Environment != "" && os.Getenv("CONFIG_FILE") == "" {
+		configFile = filepath.Join("configs", f.Environment+".yaml")
+	}
+
+	loader := NewConfigLoaderWithOverrides(configFile, f.Apply)
+	return loader.LoadConfig()
+}
+
+// Apply 明示的に指定されたコマンドライン引数を設定に適用
+func (f *Flags) Apply(config *WebhookConfig) error {
+	for _, override := range f.overrides {
+		override(config)
+	}
+	return nil
+}
+
+// set 引数の適用処理を追加
+func (f *Flags) set(override func(*WebhookConfig)) {
+	f.overrides = append(f.overrides, override)
+}
+
+// stringFlag 文字列の引数を登録
+func (f *Flags) stringFlag(fs *flag.FlagSet, name, usage string, apply func(*WebhookConfig, string)) {
+	fs.Func(name, usage, func(value string) error {
+		f.set(func(c *WebhookConfig) { apply(c, value) })
+		return nil
+	})
+}
+
+// intFlag 整数の引数を登録
+func (f *Flags) intFlag(fs *flag.FlagSet, name, usage string, apply func(*WebhookConfig, int)) {
+	fs.Func(name, usage, func(value string) error {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("整数を指定してください: %s", value)
+		}
+		f.set(func(c *WebhookConfig) { apply(c, v) })
+		return nil
+	})
+}
+
+// boolFlag ブール値の引数を登録（値を省略した場合はtrue）
+func (f *Flags) boolFlag(fs *flag.FlagSet, name, usage string, apply func(*WebhookConfig, bool)) {
+	fs.BoolFunc(name, usage, func(value string) error {
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("true または false を指定してください: %s", value)
+		}
+		f.set(func(c *WebhookConfig) { apply(c, v) })
+		return nil
+	})
+}
+
+// durationFlag 時間の引数を登録
+func (f *Flags) durationFlag(fs *flag.FlagSet, name, usage string, apply func(*WebhookConfig, time.Duration)) {
+	fs.Func(name, usage, func(value string) error {
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("時間を指定してください（例: 10s）: %s", value)
+		}
+		f.set(func(c *WebhookConfig) { apply(c, v) })
+		return nil
+	})
+}
+
+// listFlag カンマ区切りのリストの引数を登録
+func (f *Flags) listFlag(fs *flag.FlagSet, name, usage string, apply func(*WebhookConfig, []string)) {
+	fs.Func(name, usage, func(value string) error {
+		v := splitAndTrim(value)
+		f.set(func(c *WebhookConfig) { apply(c, v) })
+		return nil
+	})
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestFlags(t *testing.T, args ...string) (*Flags, error) {
+	t.Helper()
+	fs := flag.NewFlagSet("webhook", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	flags := RegisterFlags(fs)
+	return flags, fs.Parse(args)
+}
+
+func TestFlags_Precedence(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	yamlContent := `port: 7443
+log_level: warn
+metrics_port: 9090
+failure_policy: Ignore
+`
+	if err := os.WriteFile(configFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("設定ファイルの作成に失敗しました: %v", err)
+	}
+
+	// 環境変数は設定ファイルより優先
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("METRICS_PORT", "9091")
+
+	// コマンドライン引数は環境変数より優先
+	flags, err := newTestFlags(t,
+		"--config", configFile,
+		"--metrics-port", "9092",
+		"--ca-file", "/etc/certs/ca.crt",
+		"--timeout", "5s",
+		"--mutation-enabled",
+		"--skip-namespaces", "ns-a, ns-b",
+		"--message-template", "VALIDATION_HPA_SINGLE_REPLICA={{.Message}} 連絡先: team@example.com",
+	)
+	if err != nil {
+		t.Fatalf("引数の解析に失敗しました: %v", err)
+	}
+
+	config, err := flags.LoadConfig()
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+
+	if config.Port != 7443 {
+		t.Errorf("期待されるポート（設定ファイル）: 7443, 実際: %d", config.Port)
+	}
+	if config.FailurePolicy != "Ignore" {
+		t.Errorf("期待される失敗ポリシー（設定ファイル）: Ignore, 実際: %s", config.FailurePolicy)
+	}
+	if config.LogLevel != "error" {
+		t.Errorf("期待されるログレベル（環境変数）: error, 実際: %s", config.LogLevel)
+	}
+	if config.MetricsPort != 9092 {
+		t.Errorf("期待されるメトリクスポート（引数）: 9092, 実際: %d", config.MetricsPort)
+	}
+	if config.TLSCAFile != "/etc/certs/ca.crt" {
+		t.Errorf("期待されるCAファイル: /etc/certs/ca.crt, 実際: %s", config.TLSCAFile)
+	}
+	if config.Timeout != 5*time.Second {
+		t.Errorf("期待されるタイムアウト: 5s, 実際: %v", config.Timeout)
+	}
+	if !config.MutationEnabled {
+		t.Error("ミューテーションが有効になっていません")
+	}
+	if len(config.SkipNamespaces) != 2 || config.SkipNamespaces[1] != "ns-b" {
+		t.Errorf("期待されるスキップnamespace: [ns-a ns-b], 実際: %v", config.SkipNamespaces)
+	}
+	if _, ok := config.MessageTemplates["VALIDATION_HPA_SINGLE_REPLICA"]; !ok {
+		t.Errorf("メッセージテンプレートが設定されていません: %v", config.MessageTemplates)
+	}
+}
+
+func TestFlags_UnsetFlagsDoNotOverride(t *testing.T) {
+	t.Setenv("WEBHOOK_PORT", "9443")
+
+	flags, err := newTestFlags(t, "--config", filepath.Join("..", "..", "configs", "production.yaml"))
+	if err != nil {
+		t.Fatalf("引数の解析に失敗しました: %v", err)
+	}
+	config, err := flags.LoadConfig()
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if config.Port != 9443 {
+		t.Errorf("期待されるポート（環境変数）: 9443, 実際: %d", config.Port)
+	}
+}
+
+func TestFlags_FailFast(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		parseError bool
+	}{
+		{name: "整数でないポート", args: []string{"--port", "abc"}, parseError: true},
+		{name: "不正な時間", args: []string{"--timeout", "ten"}, parseError: true},
+		{name: "不正なブール値", args: []string{"--metrics-enabled=maybe"}, parseError: true},
+		{name: "不正なメッセージテンプレート形式", args: []string{"--message-template", "no-separator"}, parseError: true},
+		{name: "存在しない設定ファイル", args: []string{"--config", "/nonexistent/config.yaml"}},
+		{name: "不正なログレベル", args: []string{"--log-level", "verbose"}},
+		{name: "不正な環境", args: []string{"--environment", "qa"}},
+		{name: "ポートの重複", args: []string{"--port", "8080", "--metrics-port", "8080"}},
+		{name: "不正なテンプレート", args: []string{"--message-template", "X={{.Unknown}}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := newTestFlags(t, tt.args...)
+			if tt.parseError {
+				if err == nil {
+					t.Error("引数の解析エラーが期待されましたが、エラーが発生しませんでした")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("引数の解析に失敗しました: %v", err)
+			}
+			if _, err := flags.LoadConfig(); err == nil {
+				t.Error("設定の読み込みエラーが期待されましたが、エラーが発生しませんでした")
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+// MessageTemplateAllCodes 全てのエラーコードに適用するメッセージテンプレートのキー
+// エラーコード個別のテンプレートが優先される
+const MessageTemplateAllCodes = "*"
+
+// messageTemplateConfigMapPrefix ConfigMapでメッセージテンプレートを指定するキーの接頭辞
+const messageTemplateConfigMapPrefix = "message.template."
+
+// MessageTemplateData カスタムメッセージテンプレートに渡すパラメータ
+type MessageTemplateData struct {
+	// Code エラーコード
+	Code string
+	// Message 既定のメッセージ（本番環境では制限されたメッセージ）
+	Message string
+	// Kind, Name, Namespace 拒否されたリソース
+	Kind      string
+	Name      string
+	Namespace string
+	// RelatedKind, RelatedName 違反の原因となった関連リソース
+	RelatedKind string
+	RelatedName string
+	// Threshold Deploymentに要求されるreplica数の下限
+	Threshold int32
+	// Current 現在のreplica数
+	Current int32
+	// Annotations リソースのnamespaceのアノテーション（担当者の連絡先など）
+	Annotations map[string]string
+}
+
+// MessageTemplateSet 解析済みのカスタムメッセージテンプレート
+type MessageTemplateSet map[string]*template.Template
+
+// ParseMessageTemplates エラーコードごとのメッセージテンプレートを解析
+// 未定義のエラーコード、構文エラーや存在しないフィールドの参照は読み込み時にエラーとする
+func ParseMessageTemplates(templates map[string]string) (MessageTemplateSet, error) {
+	parsed := MessageTemplateSet{}
+
+	codes := make([]string, 0, len(templates))
+	for code := range templates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if code == "" {
+			return nil, fmt.Errorf("メッセージテンプレートのエラーコードが空です")
+		}
+		if !isKnownErrorCode(code) {
+			return nil, fmt.Errorf("メッセージテンプレートのエラーコードが定義されていません (%s)", code)
+		}
+
+		tmpl, err := template.New(code).Parse(templates[code])
+		if err != nil {
+			return nil, fmt.Errorf("メッセージテンプレートの解析に失敗しました (%s): %w", code, err)
+		}
+
+		// サンプルデータで描画してフィールド参照を検証
+		sample := MessageTemplateData{
+			Code:        code,
+			Message:     "sample",
+			Kind:        "Deployment",
+			Name:        "sample",
+			Namespace:   "default",
+			RelatedKind: "HorizontalPodAutoscaler",
+			RelatedName: "sample",
+			Threshold:   2,
+			Current:     1,
+			Annotations: map[string]string{},
+		}
+		if err := tmpl.Execute(&bytes.Buffer{}, sample); err != nil {
+			return nil, fmt.Errorf("メッセージテンプレートの検証に失敗しました (%s): %w", code, err)
+		}
+
+		parsed[code] = tmpl
+	}
+
+	return parsed, nil
+}
+
+// isKnownErrorCode メッセージテンプレートのキーが定義済みのエラーコードまたは"*"であるかを確認
+// 誤ったキーのテンプレートは適用されないため、読み込み時に検出する
+func isKnownErrorCode(code string) bool {
+	if code == MessageTemplateAllCodes {
+		return true
+	}
+	for _, known := range validator.AllErrorCodes {
+		if code == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Render エラーコードに対応するテンプレートでメッセージを描画
+// テンプレートが存在しない場合はfalseを返す
+func (t MessageTemplateSet) Render(data MessageTemplateData) (string, bool, error) {
+	tmpl, ok := t[data.Code]
+	if !ok {
+		if tmpl, ok = t[MessageTemplateAllCodes]; !ok {
+			return "", false, nil
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", true, fmt.Errorf("メッセージテンプレートの描画に失敗しました (%s): %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(buf.String()), true, nil
+}
+
+// messageTemplatesFromConfigMap ConfigMapの"message.template.<エラーコード>"キーからテンプレートを取得
+func messageTemplatesFromConfigMap(configMapData map[string]string) map[string]string {
+	templates := map[string]string{}
+	for key, value := range configMapData {
+		if code := strings.TrimPrefix(key, messageTemplateConfigMapPrefix); code != key {
+			templates[code] = value
+		}
+	}
+	return templates
+}
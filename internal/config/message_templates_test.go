@@ -0,0 +1,112 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMessageTemplates(t *testing.T) {
+	tests := []struct {
+		name        string
+		templates   map[string]string
+		expectError bool
+	}{
+		{name: "テンプレートなし", templates: nil},
+		{
+			name: "有効なテンプレート",
+			templates: map[string]string{
+				"VALIDATION_DEPLOYMENT_HPA_CONFLICT": `{{.Message}} 担当: {{index .Annotations "owner"}}`,
+				MessageTemplateAllCodes:              "{{.Message}} ({{.Code}})",
+			},
+		},
+		{name: "構文エラー", templates: map[string]string{"VALIDATION_HPA_SINGLE_REPLICA": "{{.Message"}, expectError: true},
+		{name: "存在しないフィールド", templates: map[string]string{"VALIDATION_HPA_SINGLE_REPLICA": "{{.Owner}}"}, expectError: true},
+		{name: "空のエラーコード", templates: map[string]string{"": "{{.Message}}"}, expectError: true},
+		{name: "未定義のエラーコード", templates: map[string]string{"VALIDATION_DEPLOYMENT_HPA_CONFLCT": "{{.Message}}"}, expectError: true},
+		{name: "小文字のエラーコード", templates: map[string]string{"validation_hpa_single_replica": "{{.Message}}"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseMessageTemplates(tt.templates)
+			if tt.expectError {
+				if err == nil {
+					t.Error("エラーが期待されましたが、エラーが発生しませんでした")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("予期しないエラー: %v", err)
+			}
+			if len(parsed) != len(tt.templates) {
+				t.Errorf("期待されるテンプレート数: %d, 実際: %d", len(tt.templates), len(parsed))
+			}
+		})
+	}
+}
+
+func TestMessageTemplateSet_Render(t *testing.T) {
+	templates, err := ParseMessageTemplates(map[string]string{
+		"VALIDATION_DEPLOYMENT_HPA_CONFLICT": `{{.Kind}}/{{.Name}} ({{.Namespace}}) と {{.RelatedKind}}/{{.RelatedName}}: replicasを{{.Threshold}}以上に。担当: {{index .Annotations "owner"}}`,
+		MessageTemplateAllCodes:              "{{.Message}} 手順書: https://runbooks.example.com/{{.Code}}",
+	})
+	if err != nil {
+		t.Fatalf("テンプレートの解析に失敗しました: %v", err)
+	}
+
+	data := MessageTemplateData{
+		Code:        "VALIDATION_DEPLOYMENT_HPA_CONFLICT",
+		Message:     "既定のメッセージ",
+		Kind:        "Deployment",
+		Name:        "web",
+		Namespace:   "team-a",
+		RelatedKind: "HorizontalPodAutoscaler",
+		RelatedName: "web-hpa",
+		Threshold:   3,
+		Annotations: map[string]string{"owner": "team-a@example.com"},
+	}
+	message, ok, err := templates.Render(data)
+	if err != nil || !ok {
+		t.Fatalf("描画に失敗しました: ok=%v, err=%v", ok, err)
+	}
+	want := "Deployment/web (team-a) と HorizontalPodAutoscaler/web-hpa: replicasを3以上に。担当: team-a@example.com"
+	if message != want {
+		t.Errorf("期待されるメッセージ: %q, 実際: %q", want, message)
+	}
+
+	// 個別のテンプレートがない場合は"*"を使用
+	data.Code = "VALIDATION_HPA_SINGLE_REPLICA"
+	message, ok, err = templates.Render(data)
+	if err != nil || !ok {
+		t.Fatalf("描画に失敗しました: ok=%v, err=%v", ok, err)
+	}
+	if !strings.HasPrefix(message, "既定のメッセージ") || !strings.HasSuffix(message, "/VALIDATION_HPA_SINGLE_REPLICA") {
+		t.Errorf("予期しないメッセージ: %q", message)
+	}
+
+	// テンプレートがない場合は描画しない
+	if _, ok, _ := (MessageTemplateSet{}).Render(data); ok {
+		t.Error("テンプレートがない場合はfalseを返すべきです")
+	}
+}
+
+func TestConfigLoader_LoadConfig_MessageTemplates(t *testing.T) {
+	loader := NewConfigLoaderWithConfigMap(map[string]string{
+		"message.template.VALIDATION_DEPLOYMENT_HPA_CONFLICT": "{{.Message}} 連絡先: {{index .Annotations \"owner\"}}",
+	})
+	config, err := loader.LoadConfig()
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if _, ok := config.MessageTemplates["VALIDATION_DEPLOYMENT_HPA_CONFLICT"]; !ok {
+		t.Errorf("ConfigMapのテンプレートが読み込まれていません: %v", config.MessageTemplates)
+	}
+
+	// 無効なテンプレートは読み込み時にエラー
+	loader = NewConfigLoaderWithConfigMap(map[string]string{
+		"message.template.VALIDATION_HPA_SINGLE_REPLICA": "{{.Unknown}}",
+	})
+	if _, err := loader.LoadConfig(); err == nil {
+		t.Error("無効なテンプレートでエラーが期待されましたが、エラーが発生しませんでした")
+	}
+}
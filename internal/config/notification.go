@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// NotificationFormatJSON 拒否の一覧をJSONで通知する
+	NotificationFormatJSON = "json"
+	// NotificationFormatSlack SlackのIncoming Webhook形式（text）で通知する
+	NotificationFormatSlack = "slack"
+)
+
+// IsValidNotificationFormat 通知の形式が有効かどうか
+func IsValidNotificationFormat(format string) bool {
+	return format == NotificationFormatJSON || format == NotificationFormatSlack
+}
+
+// ValidateNotificationURL 通知先のURLがhttpまたはhttpsの絶対URLかどうかを検証
+func ValidateNotificationURL(rawURL string) error {
+	return validateHTTPURL("通知先", rawURL)
+}
+
+// IsAllowedNotificationURL 通知先のURLが許可した接頭辞のいずれかに一致するかどうか
+// 文字列の前方一致では "https://hooks.example.com.attacker.test" なども一致するため、スキーム・ホストは完全に一致させ、パスのみ前方一致で比較する
+func IsAllowedNotificationURL(rawURL string, prefixes []string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || ValidateNotificationURL(rawURL) != nil || u.User != nil {
+		return false
+	}
+	for _, prefix := range prefixes {
+		p, err := url.Parse(prefix)
+		if err != nil {
+			continue
+		}
+		if !strings.EqualFold(u.Scheme, p.Scheme) || !strings.EqualFold(u.Host, p.Host) {
+			continue
+		}
+		if hasPathPrefix(u.EscapedPath(), p.EscapedPath()) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPathPrefix パスが接頭辞のパスと一致するか、その配下かどうか（"/hooks" は "/hooks/a" に一致し、"/hooks-other" には一致しない）
+func hasPathPrefix(path, prefix string) bool {
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// validateHTTPURL URLがhttpまたはhttpsの絶対URLかどうかを検証
+func validateHTTPURL(target, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("無効な%sのURL: %s: %w", target, rawURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("無効な%sのURL: %s (http または https のURLを指定してください)", target, rawURL)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+const (
+	// TLSProfileModern TLS 1.3のみを許可する（暗号スイートはGoの既定値、TLS 1.3では変更できない）
+	TLSProfileModern = "modern"
+	// TLSProfileIntermediate TLS 1.2以上で、前方秘匿性のあるAEAD暗号スイートのみを許可する（RSA・ECDSA証明書の両方に対応）
+	TLSProfileIntermediate = "intermediate"
+	// TLSProfileCustom TLSMinVersion・TLSCipherSuites・TLSCurvePreferencesで個別に指定する（未指定の項目はintermediateと同じ）
+	TLSProfileCustom = "custom"
+)
+
+// TLSSettings TLSプロファイルから決定したTLSの設定
+type TLSSettings struct {
+	MinVersion uint16
+	// CipherSuites TLS 1.2の暗号スイート（nilの場合はGoの既定値）
+	CipherSuites     []uint16
+	CurvePreferences []tls.CurveID
+}
+
+// intermediateCipherSuites intermediateプロファイルの暗号スイート（ECDSA証明書用とRSA証明書用）
+var intermediateCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+}
+
+// defaultCurvePreferences modern・intermediateプロファイルの楕円曲線
+var defaultCurvePreferences = []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384}
+
+// tlsVersions TLSMinVersionに指定できるバージョン
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsCurves TLSCurvePreferencesに指定できる楕円曲線
+var tlsCurves = map[string]tls.CurveID{
+	"X25519": tls.X25519,
+	"P256":   tls.CurveP256,
+	"P384":   tls.CurveP384,
+	"P521":   tls.CurveP521,
+}
+
+// ResolveTLSSettings TLSプロファイルからTLSの設定を決定
+func (config *WebhookConfig) ResolveTLSSettings() (*TLSSettings, error) {
+	profile := config.TLSProfile
+	if profile == "" {
+		profile = TLSProfileIntermediate
+	}
+
+	custom := config.TLSMinVersion != "" || len(config.TLSCipherSuites) > 0 || len(config.TLSCurvePreferences) > 0
+	if custom && profile != TLSProfileCustom {
+		return nil, fmt.Errorf("tls_min_version・tls_cipher_suites・tls_curve_preferences は tls_profile が custom の場合のみ指定できます (現在: %s)", profile)
+	}
+
+	switch profile {
+	case TLSProfileModern:
+		return &TLSSettings{
+			MinVersion:       tls.VersionTLS13,
+			CurvePreferences: append([]tls.CurveID{}, defaultCurvePreferences...),
+		}, nil
+	case TLSProfileIntermediate:
+		return &TLSSettings{
+			MinVersion:       tls.VersionTLS12,
+			CipherSuites:     append([]uint16{}, intermediateCipherSuites...),
+			CurvePreferences: append([]tls.CurveID{}, defaultCurvePreferences...),
+		}, nil
+	case TLSProfileCustom:
+		return config.resolveCustomTLSSettings()
+	default:
+		return nil, fmt.Errorf("無効なTLSプロファイル: %s (有効な値: %v)", profile, []string{TLSProfileModern, TLSProfileIntermediate, TLSProfileCustom})
+	}
+}
+
+// resolveCustomTLSSettings customプロファイルの設定を検証して決定
+func (config *WebhookConfig) resolveCustomTLSSettings() (*TLSSettings, error) {
+	settings := &TLSSettings{
+		MinVersion:       tls.VersionTLS12,
+		CipherSuites:     append([]uint16{}, intermediateCipherSuites...),
+		CurvePreferences: append([]tls.CurveID{}, defaultCurvePreferences...),
+	}
+
+	if config.TLSMinVersion != "" {
+		version, ok := tlsVersions[config.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("無効なTLSの最小バージョン: %s (有効な値: 1.2, 1.3)", config.TLSMinVersion)
+		}
+		settings.MinVersion = version
+	}
+
+	if len(config.TLSCipherSuites) > 0 {
+		// 安全な暗号スイートのみ許可する（tls.InsecureCipherSuitesに含まれるものは指定できない）
+		available := make(map[string]*tls.CipherSuite)
+		for _, suite := range tls.CipherSuites() {
+			available[suite.Name] = suite
+		}
+
+		settings.CipherSuites = nil
+		for _, name := range config.TLSCipherSuites {
+			suite, ok := available[strings.TrimSpace(name)]
+			if !ok {
+				return nil, fmt.Errorf("無効または安全でない暗号スイート: %s", name)
+			}
+			if !supportsTLS12(suite) {
+				// TLS 1.3の暗号スイートはGoでは変更できないため、TLS 1.2用のみ指定できる
+				return nil, fmt.Errorf("TLS 1.3の暗号スイートは指定できません: %s", name)
+			}
+			settings.CipherSuites = append(settings.CipherSuites, suite.ID)
+		}
+	}
+
+	if len(config.TLSCurvePreferences) > 0 {
+		settings.CurvePreferences = nil
+		for _, name := range config.TLSCurvePreferences {
+			curve, ok := tlsCurves[strings.TrimSpace(name)]
+			if !ok {
+				return nil, fmt.Errorf("無効な楕円曲線: %s (有効な値: X25519, P256, P384, P521)", name)
+			}
+			settings.CurvePreferences = append(settings.CurvePreferences, curve)
+		}
+	}
+
+	return settings, nil
+}
+
+// supportsTLS12 暗号スイートがTLS 1.2で使用できるかどうか
+func supportsTLS12(suite *tls.CipherSuite) bool {
+	for _, version := range suite.SupportedVersions {
+		if version == tls.VersionTLS12 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+)
+
+func TestWebhookConfig_ResolveTLSSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       WebhookConfig
+		expectError  bool
+		minVersion   uint16
+		cipherSuites []uint16
+		curves       []tls.CurveID
+	}{
+		{
+			name:         "既定値はintermediate",
+			config:       WebhookConfig{},
+			minVersion:   tls.VersionTLS12,
+			cipherSuites: intermediateCipherSuites,
+			curves:       defaultCurvePreferences,
+		},
+		{
+			name:       "modern",
+			config:     WebhookConfig{TLSProfile: TLSProfileModern},
+			minVersion: tls.VersionTLS13,
+			curves:     defaultCurvePreferences,
+		},
+		{
+			name:         "customの未指定の項目はintermediateと同じ",
+			config:       WebhookConfig{TLSProfile: TLSProfileCustom},
+			minVersion:   tls.VersionTLS12,
+			cipherSuites: intermediateCipherSuites,
+			curves:       defaultCurvePreferences,
+		},
+		{
+			name: "customで全ての項目を指定",
+			config: WebhookConfig{
+				TLSProfile:          TLSProfileCustom,
+				TLSMinVersion:       "1.2",
+				TLSCipherSuites:     []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384", " TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+				TLSCurvePreferences: []string{"P384", "P521"},
+			},
+			minVersion:   tls.VersionTLS12,
+			cipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384},
+			curves:       []tls.CurveID{tls.CurveP384, tls.CurveP521},
+		},
+		{
+			name:        "無効なプロファイル",
+			config:      WebhookConfig{TLSProfile: "old"},
+			expectError: true,
+		},
+		{
+			name:        "custom以外での個別指定",
+			config:      WebhookConfig{TLSProfile: TLSProfileModern, TLSMinVersion: "1.2"},
+			expectError: true,
+		},
+		{
+			name:        "TLS 1.1",
+			config:      WebhookConfig{TLSProfile: TLSProfileCustom, TLSMinVersion: "1.1"},
+			expectError: true,
+		},
+		{
+			name:        "安全でない暗号スイート",
+			config:      WebhookConfig{TLSProfile: TLSProfileCustom, TLSCipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			expectError: true,
+		},
+		{
+			name:        "TLS 1.3の暗号スイート",
+			config:      WebhookConfig{TLSProfile: TLSProfileCustom, TLSCipherSuites: []string{"TLS_AES_128_GCM_SHA256"}},
+			expectError: true,
+		},
+		{
+			name:        "無効な楕円曲線",
+			config:      WebhookConfig{TLSProfile: TLSProfileCustom, TLSCurvePreferences: []string{"P224"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := tt.config.ResolveTLSSettings()
+			if tt.expectError {
+				if err == nil {
+					t.Fatalf("エラーが期待されましたが、%+v が返されました", settings)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("予期しないエラー: %v", err)
+			}
+
+			if settings.MinVersion != tt.minVersion {
+				t.Errorf("MinVersion = %x, expected %x", settings.MinVersion, tt.minVersion)
+			}
+			if !reflect.DeepEqual(settings.CipherSuites, tt.cipherSuites) {
+				t.Errorf("CipherSuites = %v, expected %v", settings.CipherSuites, tt.cipherSuites)
+			}
+			if !reflect.DeepEqual(settings.CurvePreferences, tt.curves) {
+				t.Errorf("CurvePreferences = %v, expected %v", settings.CurvePreferences, tt.curves)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package decisionlog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s-deployment-hpa-validator/internal/logging"
+)
+
+// backupTimeFormat ローテーションしたファイル名に付与する時刻（名前順が時刻順になる形式）
+const backupTimeFormat = "20060102T150405.000"
+
+// rotatingFile サイズまたは経過時間でローテーションし、古いファイルをgzipで圧縮するファイル
+// Writerの書き込み用のgoroutineからのみ呼び出す
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	logger     *logging.Logger
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+
+	// compressing 圧縮と古いファイルの削除の完了を待つ
+	compressing sync.WaitGroup
+}
+
+// openRotatingFile ファイルを追記モードで開く（ディレクトリがない場合は作成）
+func openRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int, logger *logging.Logger) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		logger:     logger,
+		now:        time.Now,
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create decision log directory: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open ファイルを開き、既存の内容のサイズから書き込みを再開する
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open decision log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat decision log file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+	return nil
+}
+
+// Write 1行を書き込む（書き込むとサイズを超える場合や経過時間を超えた場合は先にローテーションする）
+func (f *rotatingFile) Write(line []byte) (int, error) {
+	if f.size > 0 && (f.size+int64(len(line)) > f.maxSize || f.now().Sub(f.openedAt) >= f.maxAge) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(line)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate 現在のファイルを時刻付きの名前に変更して新しいファイルを開き、別のgoroutineで圧縮する
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close decision log file: %w", err)
+	}
+	backup := f.backupName(f.now())
+	if err := os.Rename(f.path, backup); err != nil {
+		// 名前を変更できない場合は、同じファイルへの書き込みを続ける
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate decision log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.compressing.Add(1)
+	go func() {
+		defer f.compressing.Done()
+		if err := compressFile(backup); err != nil {
+			f.logger.Warn("ローテーションした決定ログの圧縮に失敗しました", map[string]interface{}{
+				"file":  backup,
+				"error": err.Error(),
+			})
+		}
+		f.removeOldBackups()
+	}()
+	return nil
+}
+
+// backupName ローテーションしたファイルの名前（例: decisions-20261018T140000.000.jsonl）
+func (f *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(f.path, ext), t.UTC().Format(backupTimeFormat), ext)
+}
+
+// removeOldBackups 保持するファイル数を超えた古い圧縮済みのファイルを削除（0の場合は削除しない）
+func (f *rotatingFile) removeOldBackups() {
+	if f.maxBackups <= 0 {
+		return
+	}
+	ext := filepath.Ext(f.path)
+	backups, err := filepath.Glob(strings.TrimSuffix(f.path, ext) + "-*" + ext + ".gz")
+	if err != nil || len(backups) <= f.maxBackups {
+		return
+	}
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-f.maxBackups] {
+		if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+			f.logger.Warn("古い決定ログの削除に失敗しました", map[string]interface{}{
+				"file":  backup,
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// Close ファイルを閉じ、圧縮の完了を待つ
+func (f *rotatingFile) Close() error {
+	err := f.file.Close()
+	f.compressing.Wait()
+	return err
+}
+
+// compressFile ファイルをgzipで圧縮し、元のファイルを削除する
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,115 @@
+package decisionlog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-deployment-hpa-validator/internal/logging"
+)
+
+// backups 圧縮済みのローテーションしたファイル（名前順）
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(strings.TrimSuffix(path, ".jsonl") + "-*.jsonl.gz")
+	if err != nil {
+		t.Fatalf("Failed to list backups: %v", err)
+	}
+	return matches
+}
+
+// readGzip 圧縮済みのファイルの内容
+func readGzip(t *testing.T, path string) string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Failed to read gzip %s: %v", path, err)
+	}
+	content, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("Failed to read gzip %s: %v", path, err)
+	}
+	return string(content)
+}
+
+func TestRotatingFile_Size(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decisions.jsonl")
+	file, err := openRotatingFile(path, 10, time.Hour, 2, logging.NewLogger("test-decisionlog"))
+	if err != nil {
+		t.Fatalf("openRotatingFile() error = %v", err)
+	}
+	now := time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC)
+	file.now = func() time.Time { return now }
+
+	// 1行ごとにサイズを超えるため、2行目以降は書き込む前にローテーションする
+	for _, line := range []string{"line-1\n", "line-2\n", "line-3\n", "line-4\n"} {
+		now = now.Add(time.Second)
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil || string(current) != "line-4\n" {
+		t.Errorf("current file = %q, %v, expected the last line", current, err)
+	}
+	// 保持する2ファイルを超えた最も古いファイル（line-1）は削除する
+	files := backups(t, path)
+	if len(files) != 2 {
+		t.Fatalf("backups = %v, expected 2", files)
+	}
+	if content := readGzip(t, files[0]); content != "line-2\n" {
+		t.Errorf("oldest backup = %q, expected line-2", content)
+	}
+	if content := readGzip(t, files[1]); content != "line-3\n" {
+		t.Errorf("newest backup = %q, expected line-3", content)
+	}
+}
+
+func TestRotatingFile_Age(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decisions.jsonl")
+	// 既存の内容には追記する
+	if err := os.WriteFile(path, []byte("existing\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write existing file: %v", err)
+	}
+	file, err := openRotatingFile(path, 1024*1024, time.Hour, 0, logging.NewLogger("test-decisionlog"))
+	if err != nil {
+		t.Fatalf("openRotatingFile() error = %v", err)
+	}
+	now := time.Now()
+	file.now = func() time.Time { return now }
+
+	if _, err := file.Write([]byte("line-1\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	now = now.Add(time.Hour)
+	if _, err := file.Write([]byte("line-2\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	files := backups(t, path)
+	if len(files) != 1 {
+		t.Fatalf("backups = %v, expected 1", files)
+	}
+	if content := readGzip(t, files[0]); content != "existing\nline-1\n" {
+		t.Errorf("backup = %q, expected the existing content and line-1", content)
+	}
+	if current, _ := os.ReadFile(path); string(current) != "line-2\n" {
+		t.Errorf("current file = %q, expected line-2", current)
+	}
+}
@@ -0,0 +1,122 @@
+// Package decisionlog はAdmissionRequestごとの判定をJSON Lines形式でファイルに記録する
+package decisionlog
+
+import (
+	"encoding/json"
+	"time"
+
+	"k8s-deployment-hpa-validator/internal/asyncqueue"
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+)
+
+const (
+	// DecisionAllow リクエストを許可した
+	DecisionAllow = "allow"
+	// DecisionDeny リクエストを拒否した
+	DecisionDeny = "deny"
+)
+
+const (
+	// ModeEnforce ルールを適用した（違反がある場合は拒否）
+	ModeEnforce = "enforce"
+	// ModeExempt 検証の対象外の呼び出し元のため、違反があっても警告付きで許可した
+	ModeExempt = "exempt"
+	// ModeMutate ミューテーションでルールの違反を修正した
+	ModeMutate = "mutate"
+	// ModeFailOpen 処理期限を超過したため、失敗ポリシー(Ignore)に従い許可した
+	ModeFailOpen = "fail-open"
+)
+
+const (
+	// dropReasonBufferFull 書き込みを待つ判定が上限に達した
+	dropReasonBufferFull = "buffer_full"
+	// dropReasonWriteError ファイルへの書き込みに失敗した
+	dropReasonWriteError = "write_error"
+)
+
+// Entry 1件のAdmissionRequestの判定
+type Entry struct {
+	Timestamp       time.Time `json:"timestamp"`
+	UID             string    `json:"uid"`
+	User            string    `json:"user"`
+	Groups          []string  `json:"groups,omitempty"`
+	Kind            string    `json:"kind"`
+	Name            string    `json:"name"`
+	Namespace       string    `json:"namespace"`
+	Operation       string    `json:"operation"`
+	DryRun          bool      `json:"dry_run"`
+	Decision        string    `json:"decision"`
+	EnforcementMode string    `json:"enforcement_mode"`
+	// Rules 違反したルール（ルールの違反を表すエラーコード）
+	Rules []string `json:"rules,omitempty"`
+	// Codes 判定の理由となったエラーコード（内部エラーや処理期限の超過を含む）
+	Codes     []string `json:"codes,omitempty"`
+	LatencyMs float64  `json:"latency_ms"`
+}
+
+// Writer 判定をバッファに積み、別のgoroutineでファイルに書き込む
+// バッファが一杯のため破棄した判定は webhook_decision_log_dropped_total{reason="buffer_full"} に記録する
+type Writer struct {
+	queue  *asyncqueue.Queue[Entry]
+	file   *rotatingFile
+	logger *logging.Logger
+}
+
+// NewWriter 設定されたファイルに判定を書き込むWriterを作成し、書き込みを開始する
+func NewWriter(cfg *config.WebhookConfig, logger *logging.Logger) (*Writer, error) {
+	file, err := openRotatingFile(cfg.DecisionLogPath, int64(cfg.DecisionLogMaxSizeMB)*1024*1024, cfg.DecisionLogMaxAge, cfg.DecisionLogMaxBackups, logger)
+	if err != nil {
+		return nil, err
+	}
+	return newWriter(file, cfg.DecisionLogBufferSize, logger), nil
+}
+
+// newWriter ファイルとバッファサイズを指定してWriterを作成
+func newWriter(file *rotatingFile, bufferSize int, logger *logging.Logger) *Writer {
+	w := &Writer{
+		queue:  newEntryQueue(bufferSize),
+		file:   file,
+		logger: logger,
+	}
+	w.queue.Start(w.run)
+	return w
+}
+
+// newEntryQueue 書き込みを待つ判定のバッファを作成
+func newEntryQueue(bufferSize int) *asyncqueue.Queue[Entry] {
+	return asyncqueue.New[Entry](bufferSize, func() {
+		metrics.RecordDecisionLogDropped(dropReasonBufferFull)
+	})
+}
+
+// Write 判定を書き込みのバッファに追加（ブロックしない）
+func (w *Writer) Write(entry Entry) {
+	w.queue.Enqueue(entry)
+}
+
+// run バッファの判定を順にファイルに書き込む
+func (w *Writer) run(entries <-chan Entry) {
+	for entry := range entries {
+		line, err := json.Marshal(entry)
+		if err == nil {
+			_, err = w.file.Write(append(line, '\n'))
+		}
+		if err != nil {
+			metrics.RecordDecisionLogDropped(dropReasonWriteError)
+			w.logger.Warn("決定ログの書き込みに失敗しました", map[string]interface{}{
+				"uid":   entry.UID,
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// Close バッファの判定をすべて書き込んでからファイルを閉じる
+func (w *Writer) Close() error {
+	if !w.queue.Close() {
+		return nil
+	}
+	return w.file.Close()
+}
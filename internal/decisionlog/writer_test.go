@@ -0,0 +1,99 @@
+package decisionlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+)
+
+func init() {
+	// テストモードを有効にしてメトリクスの重複登録を防ぐ
+	metrics.EnableTestMode()
+}
+
+// readEntries JSON Lines形式のファイルから判定を読み込む
+func readEntries(t *testing.T, path string) []Entry {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open decision log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to unmarshal line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "decisions.jsonl")
+	cfg := &config.WebhookConfig{
+		DecisionLogPath:       path,
+		DecisionLogMaxSizeMB:  1,
+		DecisionLogMaxAge:     time.Hour,
+		DecisionLogMaxBackups: 1,
+		DecisionLogBufferSize: 16,
+	}
+	writer, err := NewWriter(cfg, logging.NewLogger("test-decisionlog"))
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+
+	writer.Write(Entry{UID: "uid-1", Kind: "Deployment", Name: "web", Decision: DecisionDeny, EnforcementMode: ModeEnforce, Rules: []string{"VALIDATION_DEPLOYMENT_HPA_CONFLICT"}})
+	writer.Write(Entry{UID: "uid-2", Kind: "HorizontalPodAutoscaler", Name: "web-hpa", Decision: DecisionAllow, EnforcementMode: ModeExempt})
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	// 閉じた後の書き込みは無視する
+	writer.Write(Entry{UID: "uid-3"})
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v", entries)
+	}
+	if entries[0].UID != "uid-1" || entries[0].Decision != DecisionDeny || len(entries[0].Rules) != 1 {
+		t.Errorf("entries[0] = %+v", entries[0])
+	}
+	if entries[1].UID != "uid-2" || entries[1].EnforcementMode != ModeExempt {
+		t.Errorf("entries[1] = %+v", entries[1])
+	}
+}
+
+func TestWriter_DropsWhenBufferFull(t *testing.T) {
+	// 書き込み用のgoroutineを開始せず、バッファが一杯の状態を再現する
+	writer := &Writer{queue: newEntryQueue(1)}
+	dropped := metrics.WebhookDecisionLogDropped.WithLabelValues(dropReasonBufferFull)
+	before := testutil.ToFloat64(dropped)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		writer.Write(Entry{UID: "uid-1"})
+		writer.Write(Entry{UID: "uid-2"})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write() blocked on a full buffer")
+	}
+
+	if got := testutil.ToFloat64(dropped) - before; got != 1 {
+		t.Errorf("webhook_decision_log_dropped_total{reason=%q} increased by %v, expected 1", dropReasonBufferFull, got)
+	}
+}
@@ -68,6 +68,22 @@ func NewLogger(component string) *Logger {
 	}
 }
 
+// NewLoggerWithSettings は設定で指定されたレベル・フォーマット・環境でLoggerを作成
+// 空の値は環境変数の値を使用
+func NewLoggerWithSettings(component, level, format, environment string) *Logger {
+	logger := NewLogger(component)
+	if level != "" {
+		logger.level = LogLevel(strings.ToLower(level))
+	}
+	if format != "" {
+		logger.jsonFormat = strings.ToLower(format) == "json"
+	}
+	if environment != "" {
+		logger.environment = environment
+	}
+	return logger
+}
+
 // shouldLog はログレベルに基づいてログ出力の可否を判定
 func (l *Logger) shouldLog(level LogLevel) bool {
 	levels := map[LogLevel]int{
@@ -0,0 +1,41 @@
+package metrics
+
+import "sync"
+
+// LabelValueOther 上限を超えたラベルの値をまとめる値
+const LabelValueOther = "other"
+
+// LabelLimiter ラベルの値の種類を上限までに制限し、メトリクスのカーディナリティを抑える
+// 上限に達した後の新しい値はLabelValueOtherにまとめる
+type LabelLimiter struct {
+	mu    sync.Mutex
+	limit int
+	seen  map[string]struct{}
+}
+
+// NewLabelLimiter ラベルの値の種類の上限を指定してLabelLimiterを作成
+func NewLabelLimiter(limit int) *LabelLimiter {
+	return &LabelLimiter{
+		limit: limit,
+		seen:  make(map[string]struct{}),
+	}
+}
+
+// Value メトリクスに使用するラベルの値（空の値はそのまま返す）
+func (l *LabelLimiter) Value(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.seen[value]; ok {
+		return value
+	}
+	if len(l.seen) >= l.limit {
+		return LabelValueOther
+	}
+	l.seen[value] = struct{}{}
+	return value
+}
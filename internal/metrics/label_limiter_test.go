@@ -0,0 +1,25 @@
+package metrics
+
+import "testing"
+
+func TestLabelLimiter(t *testing.T) {
+	limiter := NewLabelLimiter(2)
+
+	steps := []struct {
+		value    string
+		expected string
+	}{
+		{value: "team-a", expected: "team-a"},
+		{value: "", expected: ""},
+		{value: "team-b", expected: "team-b"},
+		{value: "team-c", expected: LabelValueOther},
+		{value: "team-a", expected: "team-a"},
+		{value: "team-d", expected: LabelValueOther},
+	}
+
+	for _, step := range steps {
+		if got := limiter.Value(step.value); got != step.expected {
+			t.Errorf("Value(%q) = %q, expected %q", step.value, got, step.expected)
+		}
+	}
+}
@@ -30,6 +30,18 @@ var (
 	WebhookCertificateExpiryDays prometheus.Gauge
 	WebhookKubernetesAPIRequests *prometheus.CounterVec
 	WebhookUp                    prometheus.Gauge
+	WebhookMutationsTotal        *prometheus.CounterVec
+	WebhookClientAuthFailures    *prometheus.CounterVec
+	WebhookExemptedRequests      *prometheus.CounterVec
+	WebhookDenialsTotal          *prometheus.CounterVec
+	WebhookAuditViolations       *prometheus.GaugeVec
+	WebhookAuditScansTotal       *prometheus.CounterVec
+	WebhookAuditLastScan         prometheus.Gauge
+	WebhookDecisionLogDropped    *prometheus.CounterVec
+	WebhookNotificationsTotal    *prometheus.CounterVec
+	WebhookNotificationsDropped  prometheus.Counter
+	WebhookCloudEventsTotal      *prometheus.CounterVec
+	WebhookCloudEventsDropped    prometheus.Counter
 )
 
 // RequestMetrics はリクエストメトリクスを記録するための構造体
@@ -89,6 +101,79 @@ func RecordKubernetesAPIRequest(method, resource, status string) {
 	WebhookKubernetesAPIRequests.WithLabelValues(method, resource, status).Inc()
 }
 
+// RecordMutation は自動修正のメトリクスを記録
+func RecordMutation(resourceType, errorCode string) {
+	WebhookMutationsTotal.WithLabelValues(resourceType, errorCode).Inc()
+}
+
+// RecordClientAuthFailure はクライアント証明書による認証の失敗を記録
+func RecordClientAuthFailure(reason string) {
+	WebhookClientAuthFailures.WithLabelValues(reason).Inc()
+}
+
+// RecordExemptedRequest は検証の対象外の呼び出し元により許可した違反を記録
+func RecordExemptedRequest(exemption, rule, resourceType string) {
+	WebhookExemptedRequests.WithLabelValues(exemption, rule, resourceType).Inc()
+}
+
+// RecordDenial は拒否したリクエストを記録（requesterNamespaceは無効な場合や対象外の場合は空）
+func RecordDenial(resourceType, errorType, requesterNamespace string) {
+	WebhookDenialsTotal.WithLabelValues(resourceType, errorType, requesterNamespace).Inc()
+}
+
+// SetAuditViolations は監査スキャンで検出したnamespace・ルールごとの既存の違反の数を設定
+func SetAuditViolations(namespace, rule string, count int) {
+	WebhookAuditViolations.WithLabelValues(namespace, rule).Set(float64(count))
+}
+
+// DeleteAuditViolations は違反がなくなったnamespace・ルールの系列を削除
+func DeleteAuditViolations(namespace, rule string) {
+	WebhookAuditViolations.DeleteLabelValues(namespace, rule)
+}
+
+// RecordAuditScan は監査スキャンの結果を記録（成功した場合は完了時刻も記録）
+func RecordAuditScan(success bool) {
+	if !success {
+		WebhookAuditScansTotal.WithLabelValues("error").Inc()
+		return
+	}
+	WebhookAuditScansTotal.WithLabelValues("success").Inc()
+	WebhookAuditLastScan.SetToCurrentTime()
+}
+
+// RecordDecisionLogDropped は記録できなかった決定ログを記録（reason: buffer_full, write_error）
+func RecordDecisionLogDropped(reason string) {
+	WebhookDecisionLogDropped.WithLabelValues(reason).Inc()
+}
+
+// RecordNotification は拒否の通知の送信結果を記録
+func RecordNotification(success bool) {
+	if success {
+		WebhookNotificationsTotal.WithLabelValues("sent").Inc()
+		return
+	}
+	WebhookNotificationsTotal.WithLabelValues("failed").Inc()
+}
+
+// RecordNotificationDropped は送信待ちの上限に達したため通知しなかった拒否を記録
+func RecordNotificationDropped() {
+	WebhookNotificationsDropped.Inc()
+}
+
+// RecordCloudEvent はCloudEventの送信結果を記録
+func RecordCloudEvent(success bool) {
+	if success {
+		WebhookCloudEventsTotal.WithLabelValues("sent").Inc()
+		return
+	}
+	WebhookCloudEventsTotal.WithLabelValues("failed").Inc()
+}
+
+// RecordCloudEventDropped は送信待ちの上限に達したため送信しなかったCloudEventを記録
+func RecordCloudEventDropped() {
+	WebhookCloudEventsDropped.Inc()
+}
+
 // SetWebhookUp はwebhookの稼働状態を設定
 func SetWebhookUp(up bool) {
 	if up {
@@ -184,6 +269,111 @@ func initializeMetrics() {
 		},
 	)
 	
+	// webhook_mutations_total - 自動修正したルール違反の総数
+	WebhookMutationsTotal = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_mutations_total",
+			Help: "自動修正したルール違反の総数",
+		},
+		[]string{"resource_type", "error_code"},
+	)
+
+	// webhook_client_auth_failures_total - クライアント証明書による認証の失敗の総数
+	WebhookClientAuthFailures = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_client_auth_failures_total",
+			Help: "クライアント証明書による認証の失敗の総数（reason: missing, untrusted, not_allowed）",
+		},
+		[]string{"reason"},
+	)
+
+	// webhook_exempted_requests_total - 検証の対象外の呼び出し元のため許可した違反の総数
+	WebhookExemptedRequests = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_exempted_requests_total",
+			Help: "検証の対象外の呼び出し元（exemption: 一致した設定）のため許可した違反（rule: エラーコード）の総数",
+		},
+		[]string{"exemption", "rule", "resource_type"},
+	)
+	
+	// webhook_denials_total - 拒否したリクエストの総数
+	WebhookDenialsTotal = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_denials_total",
+			Help: "拒否したリクエストの総数（requester_namespace: 呼び出し元のサービスアカウントのnamespace、有効な場合のみ）",
+		},
+		[]string{"resource_type", "error_type", "requester_namespace"},
+	)
+
+	// webhook_audit_violations - 監査スキャンで検出した既存のリソースの違反の数
+	WebhookAuditViolations = factory.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "webhook_audit_violations",
+			Help: "監査スキャンで検出した既存のリソースの違反の数（rule: エラーコード）",
+		},
+		[]string{"namespace", "rule"},
+	)
+
+	// webhook_audit_scans_total - 監査スキャンの実行回数
+	WebhookAuditScansTotal = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_audit_scans_total",
+			Help: "監査スキャンの実行回数（result: success, error）",
+		},
+		[]string{"result"},
+	)
+
+	// webhook_audit_last_scan_timestamp_seconds - 最後に成功した監査スキャンの完了時刻
+	WebhookAuditLastScan = factory.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "webhook_audit_last_scan_timestamp_seconds",
+			Help: "最後に成功した監査スキャンの完了時刻（UNIX時間）",
+		},
+	)
+
+	// webhook_decision_log_dropped_total - 記録できなかった決定ログ
+	WebhookDecisionLogDropped = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_decision_log_dropped_total",
+			Help: "記録できなかった決定ログの数（reason: buffer_full, write_error）",
+		},
+		[]string{"reason"},
+	)
+
+	// webhook_notifications_total - 拒否の通知の送信回数
+	WebhookNotificationsTotal = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_notifications_total",
+			Help: "拒否の通知の送信回数（result: sent, failed）",
+		},
+		[]string{"result"},
+	)
+
+	// webhook_notifications_dropped_total - 送信待ちの上限に達したため通知しなかった拒否
+	WebhookNotificationsDropped = factory.NewCounter(
+		prometheus.CounterOpts{
+			Name: "webhook_notifications_dropped_total",
+			Help: "送信待ちの上限に達したため通知しなかった拒否の数",
+		},
+	)
+
+	// webhook_cloudevents_total - 判定のCloudEventの送信回数
+	WebhookCloudEventsTotal = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_cloudevents_total",
+			Help: "判定のCloudEventの送信回数（result: sent, failed）",
+		},
+		[]string{"result"},
+	)
+
+	// webhook_cloudevents_dropped_total - 送信待ちの上限に達したため送信しなかったCloudEvent
+	WebhookCloudEventsDropped = factory.NewCounter(
+		prometheus.CounterOpts{
+			Name: "webhook_cloudevents_dropped_total",
+			Help: "送信待ちの上限に達したため送信しなかったCloudEventの数",
+		},
+	)
+
 	// 初期状態でwebhookを稼働中に設定
 	SetWebhookUp(true)
 	metricsInitialized = true
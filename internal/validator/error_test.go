@@ -2,6 +2,8 @@ package validator
 
 import (
 	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestWebhookError(t *testing.T) {
@@ -123,6 +125,55 @@ func TestWebhookErrorWithSuggestions(t *testing.T) {
 	}
 }
 
+func TestWebhookErrorWithCauses(t *testing.T) {
+	err := NewDeploymentHPAConflictError().
+		WithRelatedObject("HorizontalPodAutoscaler", "test-hpa", "default").
+		WithCause("spec.replicas", "replicasが不足しています")
+
+	if len(err.Causes) != 1 || err.Causes[0].Field != "spec.replicas" {
+		t.Errorf("Causes = %+v, want spec.replicas", err.Causes)
+	}
+
+	if err.RelatedObject == nil || err.RelatedObject.String() != "HorizontalPodAutoscaler/test-hpa" {
+		t.Errorf("RelatedObject = %v, want HorizontalPodAutoscaler/test-hpa", err.RelatedObject)
+	}
+}
+
+func TestNewSuggestedFix(t *testing.T) {
+	fix := NewSuggestedFix("HorizontalPodAutoscaler", "test-hpa", "default", map[string]interface{}{
+		"spec": map[string]interface{}{"minReplicas": 2},
+	})
+
+	if fix.MergePatch != `{"spec":{"minReplicas":2}}` {
+		t.Errorf("MergePatch = %s", fix.MergePatch)
+	}
+	want := `kubectl patch horizontalpodautoscaler test-hpa -n default --type merge -p '{"spec":{"minReplicas":2}}'`
+	if fix.Command != want {
+		t.Errorf("Command = %s, want %s", fix.Command, want)
+	}
+}
+
+func TestGetStatusReason(t *testing.T) {
+	tests := []struct {
+		err  *WebhookError
+		want metav1.StatusReason
+	}{
+		{NewDeploymentHPAConflictError(), metav1.StatusReasonInvalid},
+		{NewWebhookError(ErrorTypeAuth, CodeAuthFailed, "auth"), metav1.StatusReasonForbidden},
+		{NewWebhookError(ErrorTypeKubernetesAPI, CodeAPIConnection, "api"), metav1.StatusReasonServiceUnavailable},
+		{NewWebhookError(ErrorTypeKubernetesAPI, CodeAPITimeout, "timeout"), metav1.StatusReasonTimeout},
+		{NewWebhookError(ErrorTypeInternal, CodeInternalUnknown, "internal"), metav1.StatusReasonInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Code, func(t *testing.T) {
+			if got := tt.err.GetStatusReason(); got != tt.want {
+				t.Errorf("GetStatusReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPredefinedErrors(t *testing.T) {
 	t.Run("DeploymentHPAConflictError", func(t *testing.T) {
 		err := NewDeploymentHPAConflictError()
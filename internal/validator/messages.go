@@ -0,0 +1,409 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Language メッセージの言語
+type Language string
+
+const (
+	LanguageJapanese Language = "ja"
+	LanguageEnglish  Language = "en"
+
+	// DefaultLanguage 言語が指定されていない場合に使用する言語
+	DefaultLanguage = LanguageJapanese
+)
+
+// SupportedLanguages メッセージカタログがサポートする言語
+var SupportedLanguages = []Language{LanguageJapanese, LanguageEnglish}
+
+// ParseLanguage 文字列をLanguageに変換（"en-US"のような地域指定は無視）
+func ParseLanguage(value string) (Language, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if i := strings.IndexAny(value, "-_"); i >= 0 {
+		value = value[:i]
+	}
+	for _, lang := range SupportedLanguages {
+		if Language(value) == lang {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// languageContextKey コンテキストに言語を保存するためのキー
+type languageContextKey struct{}
+
+// ContextWithLanguage 言語をコンテキストに設定
+func ContextWithLanguage(ctx context.Context, lang Language) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, lang)
+}
+
+// LanguageFromContext コンテキストから言語を取得（未設定の場合はDefaultLanguage）
+func LanguageFromContext(ctx context.Context) Language {
+	if lang, ok := ctx.Value(languageContextKey{}).(Language); ok {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// MessageData メッセージテンプレートに渡すパラメータ
+type MessageData struct {
+	ResourceKind string
+	ResourceName string
+	Namespace    string
+	RelatedKind  string
+	RelatedName  string
+	// Threshold Deploymentに要求されるreplica数の下限
+	Threshold int32
+	// Current 現在のreplica数
+	Current int32
+	// Error 内部エラーの詳細
+	Error string
+	// User 検証の対象外とした呼び出し元のユーザー名
+	User string
+}
+
+// MessageTemplate 1つのエラーコードに対応するメッセージテンプレート
+type MessageTemplate struct {
+	Message     string
+	Details     string
+	Suggestions []string
+}
+
+// MessageBundle 1言語分のメッセージカタログ
+type MessageBundle struct {
+	// Codes エラーコードごとのメッセージ
+	Codes map[string]MessageTemplate
+	// Production 本番環境でエラータイプごとに表示する制限されたメッセージ
+	Production map[ErrorType]string
+	// Fragments フィールド原因、警告、見出しなどの断片
+	Fragments map[string]string
+}
+
+// フィールド原因・警告・見出しのキー
+const (
+	CauseDeploymentReplicas = "CAUSE_DEPLOYMENT_REPLICAS"
+	CauseHPAScaleTarget     = "CAUSE_HPA_SCALE_TARGET"
+	CauseHPAMinReplicas     = "CAUSE_HPA_MIN_REPLICAS"
+
+	WarnDeploymentReplicasRaised = "WARN_DEPLOYMENT_REPLICAS_RAISED"
+	WarnHPAMinReplicasRaised     = "WARN_HPA_MIN_REPLICAS_RAISED"
+	WarnDeadlineExceededAllowed  = "WARN_DEADLINE_EXCEEDED_ALLOWED"
+	WarnExemptionAllowed         = "WARN_EXEMPTION_ALLOWED"
+
+	LabelDetails       = "LABEL_DETAILS"
+	LabelSuggestions   = "LABEL_SUGGESTIONS"
+	LabelFixes         = "LABEL_FIXES"
+	LabelRelatedObject = "LABEL_RELATED_OBJECT"
+)
+
+// AllErrorCodes 定義済みの全エラーコード
+var AllErrorCodes = []string{
+	CodeDeploymentHPAConflict, CodeHPASingleReplica, CodeInvalidResource,
+	CodeInvalidConfig, CodeMissingConfig, CodeConfigValidation,
+	CodeNetworkTimeout, CodeNetworkConnection, CodeNetworkDNS,
+	CodeCertExpired, CodeCertInvalid, CodeCertNotFound, CodeCertChainInvalid,
+	CodeAPITimeout, CodeAPIConnection, CodeAPINotFound, CodeAPIConflict, CodeAPIForbidden,
+	CodeInternalPanic, CodeInternalTemporary, CodeInternalUnknown, CodeInternalDeadlineExceeded,
+	CodeAuthFailed, CodeAuthInsufficientPermissions,
+	CodeResourceExhausted, CodeResourceUnavailable,
+}
+
+// AllErrorTypes 定義済みの全エラータイプ
+var AllErrorTypes = []ErrorType{
+	ErrorTypeValidation, ErrorTypeConfiguration, ErrorTypeNetwork, ErrorTypeCertificate,
+	ErrorTypeInternal, ErrorTypeKubernetesAPI, ErrorTypeAuth, ErrorTypeResource,
+}
+
+// AllFragmentKeys 定義済みの全断片キー
+var AllFragmentKeys = []string{
+	CauseDeploymentReplicas, CauseHPAScaleTarget, CauseHPAMinReplicas,
+	WarnDeploymentReplicasRaised, WarnHPAMinReplicasRaised, WarnDeadlineExceededAllowed, WarnExemptionAllowed,
+	LabelDetails, LabelSuggestions, LabelFixes, LabelRelatedObject,
+}
+
+// MessageBundles 言語ごとのメッセージカタログ
+var MessageBundles = map[Language]MessageBundle{
+	LanguageJapanese: {
+		Codes: map[string]MessageTemplate{
+			CodeDeploymentHPAConflict: {
+				Message: "{{.Current}} replicaのDeploymentにHPAが設定されています。HPAを削除するか、replicasを{{.Threshold}}以上に設定してください。",
+				Details: "HPAが正常に動作するためには、対象のDeploymentのreplica数が{{.Threshold}}以上である必要があります。",
+				Suggestions: []string{
+					"Deploymentのspec.replicasを{{.Threshold}}以上に設定してください",
+					"または、HPAを削除してください",
+				},
+			},
+			CodeHPASingleReplica: {
+				Message: "{{.Current}} replicaのDeploymentを対象とするHPAは作成できません。Deploymentのreplicasを{{.Threshold}}以上に設定してください。",
+				Details: "HPAは最低{{.Threshold}}つのレプリカが必要です。{{.Current}}つのレプリカでは自動スケーリングが機能しません。",
+				Suggestions: []string{
+					"対象のDeploymentのspec.replicasを{{.Threshold}}以上に設定してください",
+					"HPAのspec.minReplicasを{{.Threshold}}以上に設定してください",
+				},
+			},
+			CodeInvalidResource:             {Message: "無効な{{.ResourceKind}}リソースです"},
+			CodeInvalidConfig:               {Message: "webhookの設定が無効です: {{.Error}}"},
+			CodeMissingConfig:               {Message: "webhookの設定が見つかりません: {{.Error}}"},
+			CodeConfigValidation:            {Message: "webhookの設定の検証に失敗しました: {{.Error}}"},
+			CodeNetworkTimeout:              {Message: "ネットワークがタイムアウトしました: {{.Error}}"},
+			CodeNetworkConnection:           {Message: "ネットワーク接続に失敗しました: {{.Error}}"},
+			CodeNetworkDNS:                  {Message: "名前解決に失敗しました: {{.Error}}"},
+			CodeCertExpired:                 {Message: "証明書の有効期限が切れています: {{.Error}}"},
+			CodeCertInvalid:                 {Message: "証明書が無効です: {{.Error}}"},
+			CodeCertNotFound:                {Message: "証明書が見つかりません: {{.Error}}"},
+			CodeCertChainInvalid:            {Message: "証明書チェーンが無効です: {{.Error}}"},
+			CodeAPITimeout:                  {Message: "Kubernetes APIの呼び出しがタイムアウトしました: {{.Error}}"},
+			CodeAPIConnection:               {Message: "Kubernetes APIとの通信に失敗しました: {{.Error}}"},
+			CodeAPINotFound:                 {Message: "Kubernetes APIでリソースが見つかりません: {{.Error}}"},
+			CodeAPIConflict:                 {Message: "Kubernetes APIで競合が発生しました: {{.Error}}"},
+			CodeAPIForbidden:                {Message: "Kubernetes APIへのアクセスが拒否されました: {{.Error}}"},
+			CodeInternalPanic:               {Message: "内部エラー（panic）が発生しました: {{.Error}}"},
+			CodeInternalTemporary:           {Message: "一時的な内部エラーが発生しました: {{.Error}}"},
+			CodeInternalUnknown:             {Message: "内部エラーが発生しました: {{.Error}}"},
+			CodeInternalDeadlineExceeded:    {Message: "処理が制限時間内に完了しませんでした: {{.Error}}"},
+			CodeAuthFailed:                  {Message: "認証に失敗しました: {{.Error}}"},
+			CodeAuthInsufficientPermissions: {Message: "権限が不足しています: {{.Error}}"},
+			CodeResourceExhausted:           {Message: "リソースが不足しています: {{.Error}}"},
+			CodeResourceUnavailable:         {Message: "リソースが利用できません: {{.Error}}"},
+		},
+		Production: map[ErrorType]string{
+			ErrorTypeConfiguration: "設定に問題があります。管理者に連絡してください。",
+			ErrorTypeNetwork:       "ネットワークエラーが発生しました。しばらく待ってから再試行してください。",
+			ErrorTypeCertificate:   "証明書に問題があります。管理者に連絡してください。",
+			ErrorTypeKubernetesAPI: "Kubernetes APIとの通信に問題があります。しばらく待ってから再試行してください。",
+			ErrorTypeAuth:          "認証に失敗しました。権限を確認してください。",
+			ErrorTypeResource:      "リソースが不足しています。しばらく待ってから再試行してください。",
+			ErrorTypeInternal:      "内部エラーが発生しました。管理者に連絡してください。",
+			ErrorTypeValidation:    "リソースがバリデーションに失敗しました。",
+		},
+		Fragments: map[string]string{
+			CauseDeploymentReplicas:      "HPA {{.RelatedName}} の対象となっているため、replicasは{{.Threshold}}以上である必要があります（現在: {{.Current}}）",
+			CauseHPAScaleTarget:          "対象のDeployment {{.RelatedName}} のreplicasが{{.Current}}です",
+			CauseHPAMinReplicas:          "対象のDeploymentを自動的にスケールアウトするには、minReplicasを{{.Threshold}}以上に設定してください",
+			WarnDeploymentReplicasRaised: "HPAの対象となっているDeploymentのspec.replicasを{{.Current}}から{{.Threshold}}に変更しました。",
+			WarnHPAMinReplicasRaised:     "replica数が{{.Threshold}}未満のDeploymentを対象とするため、HPAのspec.minReplicasを{{.Current}}から{{.Threshold}}に変更しました。",
+			WarnDeadlineExceededAllowed:  "処理が制限時間内に完了しなかったため、失敗ポリシー(Ignore)に従って検証せずに許可しました: {{.Error}}",
+			WarnExemptionAllowed:         "{{.User}} は検証の対象外のため、違反がありますが許可しました: {{.Error}}",
+			LabelDetails:                 "詳細",
+			LabelSuggestions:             "提案",
+			LabelFixes:                   "修正案",
+			LabelRelatedObject:           "関連リソース",
+		},
+	},
+	LanguageEnglish: {
+		Codes: map[string]MessageTemplate{
+			CodeDeploymentHPAConflict: {
+				Message: "An HPA targets this Deployment, which has only {{.Current}} replica(s). Delete the HPA or set replicas to {{.Threshold}} or more.",
+				Details: "For an HPA to work, the target Deployment must have at least {{.Threshold}} replicas.",
+				Suggestions: []string{
+					"Set the Deployment's spec.replicas to {{.Threshold}} or more",
+					"Alternatively, delete the HPA",
+				},
+			},
+			CodeHPASingleReplica: {
+				Message: "An HPA cannot target a Deployment with only {{.Current}} replica(s). Set the Deployment's replicas to {{.Threshold}} or more.",
+				Details: "An HPA needs at least {{.Threshold}} replicas. Autoscaling does not work with {{.Current}} replica(s).",
+				Suggestions: []string{
+					"Set the target Deployment's spec.replicas to {{.Threshold}} or more",
+					"Set the HPA's spec.minReplicas to {{.Threshold}} or more",
+				},
+			},
+			CodeInvalidResource:             {Message: "Invalid {{.ResourceKind}} resource"},
+			CodeInvalidConfig:               {Message: "The webhook configuration is invalid: {{.Error}}"},
+			CodeMissingConfig:               {Message: "The webhook configuration is missing: {{.Error}}"},
+			CodeConfigValidation:            {Message: "The webhook configuration failed validation: {{.Error}}"},
+			CodeNetworkTimeout:              {Message: "A network operation timed out: {{.Error}}"},
+			CodeNetworkConnection:           {Message: "A network connection failed: {{.Error}}"},
+			CodeNetworkDNS:                  {Message: "DNS resolution failed: {{.Error}}"},
+			CodeCertExpired:                 {Message: "The certificate has expired: {{.Error}}"},
+			CodeCertInvalid:                 {Message: "The certificate is invalid: {{.Error}}"},
+			CodeCertNotFound:                {Message: "The certificate was not found: {{.Error}}"},
+			CodeCertChainInvalid:            {Message: "The certificate chain is invalid: {{.Error}}"},
+			CodeAPITimeout:                  {Message: "A Kubernetes API call timed out: {{.Error}}"},
+			CodeAPIConnection:               {Message: "Communication with the Kubernetes API failed: {{.Error}}"},
+			CodeAPINotFound:                 {Message: "The Kubernetes API could not find the resource: {{.Error}}"},
+			CodeAPIConflict:                 {Message: "The Kubernetes API reported a conflict: {{.Error}}"},
+			CodeAPIForbidden:                {Message: "Access to the Kubernetes API was denied: {{.Error}}"},
+			CodeInternalPanic:               {Message: "An internal error (panic) occurred: {{.Error}}"},
+			CodeInternalTemporary:           {Message: "A temporary internal error occurred: {{.Error}}"},
+			CodeInternalUnknown:             {Message: "An internal error occurred: {{.Error}}"},
+			CodeInternalDeadlineExceeded:    {Message: "Processing did not finish within the deadline: {{.Error}}"},
+			CodeAuthFailed:                  {Message: "Authentication failed: {{.Error}}"},
+			CodeAuthInsufficientPermissions: {Message: "Insufficient permissions: {{.Error}}"},
+			CodeResourceExhausted:           {Message: "Resources are exhausted: {{.Error}}"},
+			CodeResourceUnavailable:         {Message: "A resource is unavailable: {{.Error}}"},
+		},
+		Production: map[ErrorType]string{
+			ErrorTypeConfiguration: "There is a configuration problem. Please contact your administrator.",
+			ErrorTypeNetwork:       "A network error occurred. Please wait a moment and try again.",
+			ErrorTypeCertificate:   "There is a certificate problem. Please contact your administrator.",
+			ErrorTypeKubernetesAPI: "There is a problem communicating with the Kubernetes API. Please wait a moment and try again.",
+			ErrorTypeAuth:          "Authentication failed. Please check your permissions.",
+			ErrorTypeResource:      "Resources are exhausted. Please wait a moment and try again.",
+			ErrorTypeInternal:      "An internal error occurred. Please contact your administrator.",
+			ErrorTypeValidation:    "The resource failed validation.",
+		},
+		Fragments: map[string]string{
+			CauseDeploymentReplicas:      "replicas must be {{.Threshold}} or more because HPA {{.RelatedName}} targets this Deployment (current: {{.Current}})",
+			CauseHPAScaleTarget:          "target Deployment {{.RelatedName}} has {{.Current}} replica(s)",
+			CauseHPAMinReplicas:          "set minReplicas to {{.Threshold}} or more so the HPA scales the target Deployment out",
+			WarnDeploymentReplicasRaised: "spec.replicas of this HPA-targeted Deployment was changed from {{.Current}} to {{.Threshold}}.",
+			WarnHPAMinReplicasRaised:     "spec.minReplicas of this HPA was changed from {{.Current}} to {{.Threshold}} because it targets a Deployment with fewer than {{.Threshold}} replicas.",
+			WarnDeadlineExceededAllowed:  "this request was allowed without validation because processing did not finish within the deadline (failure policy Ignore): {{.Error}}",
+			WarnExemptionAllowed:         "this request was allowed despite a violation because {{.User}} is exempt from validation: {{.Error}}",
+			LabelDetails:                 "Details",
+			LabelSuggestions:             "Suggestions",
+			LabelFixes:                   "Suggested fixes",
+			LabelRelatedObject:           "related object",
+		},
+	},
+}
+
+// compiledTemplates 起動時に解析したテンプレート（キーは言語とテンプレート名）
+var compiledTemplates = map[Language]map[string]*template.Template{}
+
+func init() {
+	for lang, bundle := range MessageBundles {
+		compiled := map[string]*template.Template{}
+		for code, tmpl := range bundle.Codes {
+			compiled[code] = template.Must(template.New(code).Parse(tmpl.Message))
+			compiled[code+".details"] = template.Must(template.New(code + ".details").Parse(tmpl.Details))
+			for i, suggestion := range tmpl.Suggestions {
+				name := fmt.Sprintf("%s.suggestions.%d", code, i)
+				compiled[name] = template.Must(template.New(name).Parse(suggestion))
+			}
+		}
+		for key, fragment := range bundle.Fragments {
+			compiled[key] = template.Must(template.New(key).Parse(fragment))
+		}
+		compiledTemplates[lang] = compiled
+	}
+}
+
+// render 指定された言語でテンプレートを描画（該当がない場合はDefaultLanguageにフォールバック）
+func render(lang Language, name string, data MessageData) (string, bool) {
+	tmpl, ok := compiledTemplates[lang][name]
+	if !ok {
+		if tmpl, ok = compiledTemplates[DefaultLanguage][name]; !ok {
+			return "", false
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// LocalizeFragment 断片（フィールド原因、警告、見出し）を指定された言語で描画
+func LocalizeFragment(lang Language, key string, data MessageData) string {
+	if message, ok := render(lang, key, data); ok {
+		return message
+	}
+	return key
+}
+
+// MessageData WebhookErrorからテンプレートパラメータを作成
+func (e *WebhookError) MessageData() MessageData {
+	data := MessageData{
+		ResourceKind: e.ResourceType,
+		ResourceName: e.ResourceName,
+		Namespace:    e.Namespace,
+		Threshold:    e.Threshold,
+		Current:      e.Current,
+		Error:        e.Message,
+	}
+	if data.Threshold == 0 {
+		data.Threshold = DefaultMinReplicas
+	}
+	// replica数を設定していないエラー（NewDeploymentHPAConflictErrorなど）は、従来のルールの1 replicaとして描画する
+	if data.Current == 0 {
+		data.Current = 1
+	}
+	if e.RelatedObject != nil {
+		data.RelatedKind = e.RelatedObject.Kind
+		data.RelatedName = e.RelatedObject.Name
+	}
+	if e.InternalError != nil {
+		data.Error = e.InternalError.Error()
+	}
+	return data
+}
+
+// Localize 指定された言語でメッセージを描画したコピーを返す
+// カタログに存在しないエラーコードの場合はメッセージを変更しない
+func (e *WebhookError) Localize(lang Language) *WebhookError {
+	localized := *e
+	localized.Language = lang
+	data := e.MessageData()
+
+	if hasCode(lang, e.Code) || hasCode(DefaultLanguage, e.Code) {
+		tmpl, ok := MessageBundles[lang].Codes[e.Code]
+		if !ok {
+			tmpl = MessageBundles[DefaultLanguage].Codes[e.Code]
+		}
+		if message, ok := render(lang, e.Code, data); ok {
+			localized.Message = message
+		}
+		if details, ok := render(lang, e.Code+".details", data); ok && details != "" {
+			localized.Details = details
+		}
+		if len(tmpl.Suggestions) > 0 {
+			localized.Suggestions = make([]string, 0, len(tmpl.Suggestions))
+			for i := range tmpl.Suggestions {
+				if suggestion, ok := render(lang, fmt.Sprintf("%s.suggestions.%d", e.Code, i), data); ok {
+					localized.Suggestions = append(localized.Suggestions, suggestion)
+				}
+			}
+		}
+	}
+
+	if len(e.Causes) > 0 {
+		localized.Causes = make([]FieldCause, len(e.Causes))
+		for i, cause := range e.Causes {
+			if cause.Key != "" {
+				cause.Message = LocalizeFragment(lang, cause.Key, data)
+			}
+			localized.Causes[i] = cause
+		}
+	}
+
+	return &localized
+}
+
+// GetLanguage メッセージの言語を取得（未設定の場合はDefaultLanguage）
+func (e *WebhookError) GetLanguage() Language {
+	if e.Language == "" {
+		return DefaultLanguage
+	}
+	return e.Language
+}
+
+// GetProductionMessageIn 指定された言語で本番環境用の制限されたメッセージを取得
+func (e *WebhookError) GetProductionMessageIn(lang Language) string {
+	// バリデーションエラーは詳細を表示
+	if e.Type == ErrorTypeValidation {
+		return e.Localize(lang).Message
+	}
+	if message, ok := MessageBundles[lang].Production[e.Type]; ok {
+		return message
+	}
+	if message, ok := MessageBundles[DefaultLanguage].Production[e.Type]; ok {
+		return message
+	}
+	return ErrSystemFailure
+}
+
+// hasCode 指定された言語のカタログにエラーコードが存在するかを判定
+func hasCode(lang Language, code string) bool {
+	_, ok := MessageBundles[lang].Codes[code]
+	return ok
+}
@@ -0,0 +1,162 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMessageCatalogCompleteness(t *testing.T) {
+	data := MessageData{
+		ResourceKind: "Deployment",
+		ResourceName: "test-deployment",
+		Namespace:    "default",
+		RelatedKind:  "HorizontalPodAutoscaler",
+		RelatedName:  "test-hpa",
+		Threshold:    3,
+		Current:      1,
+		Error:        "boom",
+	}
+
+	for _, lang := range SupportedLanguages {
+		bundle, ok := MessageBundles[lang]
+		if !ok {
+			t.Fatalf("Language %s has no message bundle", lang)
+		}
+
+		for _, code := range AllErrorCodes {
+			tmpl, ok := bundle.Codes[code]
+			if !ok || tmpl.Message == "" {
+				t.Errorf("[%s] Missing message for error code %s", lang, code)
+				continue
+			}
+			if message, ok := render(lang, code, data); !ok || message == "" || strings.Contains(message, "<no value>") {
+				t.Errorf("[%s] Failed to render %s: %q", lang, code, message)
+			}
+			// 全言語で提案の数が一致すること
+			if want := len(MessageBundles[DefaultLanguage].Codes[code].Suggestions); len(tmpl.Suggestions) != want {
+				t.Errorf("[%s] %s has %d suggestions, want %d", lang, code, len(tmpl.Suggestions), want)
+			}
+		}
+		if len(bundle.Codes) != len(AllErrorCodes) {
+			t.Errorf("[%s] Catalog has %d codes, AllErrorCodes has %d", lang, len(bundle.Codes), len(AllErrorCodes))
+		}
+
+		for _, errorType := range AllErrorTypes {
+			if bundle.Production[errorType] == "" {
+				t.Errorf("[%s] Missing production message for error type %s", lang, errorType)
+			}
+		}
+
+		for _, key := range AllFragmentKeys {
+			if _, ok := bundle.Fragments[key]; !ok {
+				t.Errorf("[%s] Missing fragment %s", lang, key)
+				continue
+			}
+			if message := LocalizeFragment(lang, key, data); message == key || strings.Contains(message, "<no value>") {
+				t.Errorf("[%s] Failed to render fragment %s: %q", lang, key, message)
+			}
+		}
+	}
+
+	// ルールの違反のメッセージには実際のreplica数を埋め込むこと（1 replicaに限定しない）
+	data.Current = 2
+	for _, lang := range SupportedLanguages {
+		for _, code := range []string{CodeDeploymentHPAConflict, CodeHPASingleReplica} {
+			message, _ := render(lang, code, data)
+			if !strings.Contains(message, "2 replica") || strings.Contains(message, "1 replica") {
+				t.Errorf("[%s] %s does not render the current replica count: %q", lang, code, message)
+			}
+		}
+	}
+}
+
+func TestDefaultMessagesMatchErrorConstants(t *testing.T) {
+	if got := NewDeploymentHPAConflictError().Message; got != ErrDeploymentWithHPA {
+		t.Errorf("Deployment conflict message = %q, want %q", got, ErrDeploymentWithHPA)
+	}
+	if got := NewHPASingleReplicaError().Message; got != ErrHPAWithSingleReplica {
+		t.Errorf("HPA single replica message = %q, want %q", got, ErrHPAWithSingleReplica)
+	}
+}
+
+func TestWebhookError_Localize(t *testing.T) {
+	err := NewDeploymentHPAConflictError().
+		WithContext("req-1", "Deployment", "test-deployment", "default").
+		WithRelatedObject("HorizontalPodAutoscaler", "test-hpa", "default").
+		WithReplicaCounts(3, 1).
+		WithCauseKey("spec.replicas", CauseDeploymentReplicas)
+
+	// 既定の言語では下限値が埋め込まれる
+	if !strings.Contains(err.Message, "3以上") {
+		t.Errorf("Default message should contain the threshold: %s", err.Message)
+	}
+
+	english := err.Localize(LanguageEnglish)
+	if english.Language != LanguageEnglish {
+		t.Errorf("Language = %s, want %s", english.Language, LanguageEnglish)
+	}
+	if !strings.Contains(english.Message, "3 or more") {
+		t.Errorf("English message should contain the threshold: %s", english.Message)
+	}
+	if len(english.Suggestions) != 2 || !strings.Contains(english.Suggestions[0], "spec.replicas") {
+		t.Errorf("Unexpected English suggestions: %v", english.Suggestions)
+	}
+	if len(english.Causes) != 1 || !strings.Contains(english.Causes[0].Message, "HPA test-hpa") {
+		t.Errorf("Unexpected English causes: %+v", english.Causes)
+	}
+
+	// 元のエラーは変更されない
+	if err.Language != DefaultLanguage || strings.Contains(err.Message, "or more") {
+		t.Errorf("Localize() must not modify the original error: %+v", err)
+	}
+
+	// カタログにないエラーコードはそのまま
+	custom := NewWebhookError(ErrorTypeValidation, "TEST_CODE", "テストメッセージ").Localize(LanguageEnglish)
+	if custom.Message != "テストメッセージ" {
+		t.Errorf("Unknown code message = %q, want unchanged", custom.Message)
+	}
+}
+
+func TestWebhookError_GetProductionMessageIn(t *testing.T) {
+	internal := NewInternalError("test", fmt.Errorf("boom"))
+	if got := internal.GetProductionMessageIn(LanguageEnglish); got != MessageBundles[LanguageEnglish].Production[ErrorTypeInternal] {
+		t.Errorf("English production message = %q", got)
+	}
+	if got := internal.GetProductionMessage(); got != MessageBundles[LanguageJapanese].Production[ErrorTypeInternal] {
+		t.Errorf("Default production message = %q", got)
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   Language
+		wantOK bool
+	}{
+		{value: "ja", want: LanguageJapanese, wantOK: true},
+		{value: "EN", want: LanguageEnglish, wantOK: true},
+		{value: "en-US", want: LanguageEnglish, wantOK: true},
+		{value: "ja_JP", want: LanguageJapanese, wantOK: true},
+		{value: "fr", wantOK: false},
+		{value: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := ParseLanguage(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseLanguage(%q) = %q, %v, want %q, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if got := LanguageFromContext(context.Background()); got != DefaultLanguage {
+		t.Errorf("LanguageFromContext() = %s, want %s", got, DefaultLanguage)
+	}
+	ctx := ContextWithLanguage(context.Background(), LanguageEnglish)
+	if got := LanguageFromContext(ctx); got != LanguageEnglish {
+		t.Errorf("LanguageFromContext() = %s, want %s", got, LanguageEnglish)
+	}
+}
@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+// Mutator defines the interface for fixing rule violations instead of rejecting them
+type Mutator interface {
+	MutateDeployment(ctx context.Context, deployment *appsv1.Deployment) (*MutationResult, error)
+	MutateHPA(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) (*MutationResult, error)
+	// HPAMeetsFloor MutateHPAの修正が適用済み（minReplicasが下限以上）かどうか
+	HPAMeetsFloor(hpa *autoscalingv2.HorizontalPodAutoscaler) bool
+}
+
+// PatchOperation JSON Patch (RFC 6902) の1操作
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MutationResult ミューテーションの結果
+type MutationResult struct {
+	// Patches 適用するJSON Patch（修正不要の場合は空）
+	Patches []PatchOperation
+	// Warnings ユーザーに返す警告メッセージ
+	Warnings []string
+	// Violation 修正したルール違反（修正不要の場合はnil）
+	Violation *WebhookError
+}
+
+// IsMutated ミューテーションが発生したかどうかを判定
+func (r *MutationResult) IsMutated() bool {
+	return r != nil && len(r.Patches) > 0
+}
+
+// MutateDeployment raises spec.replicas to the floor when ValidateDeployment reports a conflict
+func (v *DeploymentHPAValidator) MutateDeployment(ctx context.Context, deployment *appsv1.Deployment) (*MutationResult, error) {
+	violation, err := v.fixableViolation(v.ValidateDeployment(ctx, deployment), CodeDeploymentHPAConflict)
+	if err != nil || violation == nil {
+		return &MutationResult{}, err
+	}
+
+	return &MutationResult{
+		Patches: []PatchOperation{
+			{Op: "replace", Path: "/spec/replicas", Value: v.minReplicas},
+		},
+		// 警告はコンテキストの言語で描画
+		Warnings: []string{LocalizeFragment(LanguageFromContext(ctx), WarnDeploymentReplicasRaised, MessageData{
+			Threshold: v.minReplicas,
+			Current:   *deployment.Spec.Replicas,
+		})},
+		Violation: violation,
+	}, nil
+}
+
+// MutateHPA raises spec.minReplicas to the floor when ValidateHPA reports a single replica target
+func (v *DeploymentHPAValidator) MutateHPA(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) (*MutationResult, error) {
+	// minReplicasが下限以上であれば、修正は不要
+	if v.HPAMeetsFloor(hpa) {
+		return &MutationResult{}, nil
+	}
+	violation, err := v.fixableViolation(v.ValidateHPA(ctx, hpa), CodeHPASingleReplica)
+	if err != nil || violation == nil {
+		return &MutationResult{}, err
+	}
+
+	// minReplicas未指定時のAPIサーバーのデフォルト値は1
+	current := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		current = *hpa.Spec.MinReplicas
+	}
+
+	return &MutationResult{
+		Patches: []PatchOperation{
+			// "add"は既存フィールドの置換も兼ねる
+			{Op: "add", Path: "/spec/minReplicas", Value: v.minReplicas},
+		},
+		Warnings: []string{LocalizeFragment(LanguageFromContext(ctx), WarnHPAMinReplicasRaised, MessageData{
+			Threshold: v.minReplicas,
+			Current:   current,
+		})},
+		Violation: violation,
+	}, nil
+}
+
+// HPAMeetsFloor reports whether an HPA's minReplicas is already at or above the floor
+// HPAコントローラーが対象のDeploymentをminReplicasまでスケールアウトするため、修正済みのHPAと判断できる
+func (v *DeploymentHPAValidator) HPAMeetsFloor(hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	return hpa.Spec.MinReplicas != nil && *hpa.Spec.MinReplicas >= v.minReplicas
+}
+
+// fixableViolation splits a validation error into a fixable violation or a real error
+func (v *DeploymentHPAValidator) fixableViolation(err error, code string) (*WebhookError, error) {
+	if err == nil {
+		return nil, nil
+	}
+	if webhookErr, ok := err.(*WebhookError); ok && webhookErr.Code == code {
+		return webhookErr, nil
+	}
+	return nil, err
+}
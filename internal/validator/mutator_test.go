@@ -0,0 +1,155 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMutateDeployment(t *testing.T) {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-hpa", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		replicas    *int32
+		minReplicas int32
+		withHPA     bool
+		expectPatch bool
+		expectFloor int32
+	}{
+		{name: "1 replica with HPA is raised to default floor", replicas: int32Ptr(1), minReplicas: DefaultMinReplicas, withHPA: true, expectPatch: true, expectFloor: 2},
+		{name: "1 replica with HPA is raised to configured floor", replicas: int32Ptr(1), minReplicas: 3, withHPA: true, expectPatch: true, expectFloor: 3},
+		{name: "2 replicas below configured floor is raised", replicas: int32Ptr(2), minReplicas: 3, withHPA: true, expectPatch: true, expectFloor: 3},
+		{name: "1 replica without HPA is untouched", replicas: int32Ptr(1), minReplicas: DefaultMinReplicas, withHPA: false, expectPatch: false},
+		{name: "nil replicas is untouched", replicas: nil, minReplicas: DefaultMinReplicas, withHPA: true, expectPatch: false},
+		{name: "replicas at floor is untouched", replicas: int32Ptr(2), minReplicas: DefaultMinReplicas, withHPA: true, expectPatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset()
+			if tt.withHPA {
+				if _, err := fakeClient.AutoscalingV2().HorizontalPodAutoscalers("default").Create(
+					context.Background(), hpa.DeepCopy(), metav1.CreateOptions{}); err != nil {
+					t.Fatalf("Failed to create test HPA: %v", err)
+				}
+			}
+
+			v := NewDeploymentHPAValidatorWithMinReplicas(fakeClient, tt.minReplicas)
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "default"},
+				Spec:       appsv1.DeploymentSpec{Replicas: tt.replicas},
+			}
+
+			result, err := v.MutateDeployment(context.Background(), deployment)
+			if err != nil {
+				t.Fatalf("MutateDeployment() unexpected error: %v", err)
+			}
+			if result.IsMutated() != tt.expectPatch {
+				t.Fatalf("MutateDeployment() mutated = %v, expected %v", result.IsMutated(), tt.expectPatch)
+			}
+			if !tt.expectPatch {
+				return
+			}
+
+			if len(result.Warnings) == 0 {
+				t.Error("Expected a warning explaining the mutation")
+			}
+			if result.Violation == nil || result.Violation.Code != CodeDeploymentHPAConflict {
+				t.Errorf("Expected violation %s, got %v", CodeDeploymentHPAConflict, result.Violation)
+			}
+			patch := result.Patches[0]
+			if patch.Path != "/spec/replicas" || patch.Value != tt.expectFloor {
+				t.Errorf("Unexpected patch: %+v", patch)
+			}
+
+			// 修正後のDeploymentはバリデーションを通過すること
+			deployment.Spec.Replicas = int32Ptr(patch.Value.(int32))
+			if err := v.ValidateDeployment(context.Background(), deployment); err != nil {
+				t.Errorf("Mutated deployment still fails validation: %v", err)
+			}
+		})
+	}
+}
+
+func TestMutateHPA(t *testing.T) {
+	tests := []struct {
+		name           string
+		deploymentReps *int32
+		hpaMinReplicas *int32
+		expectPatch    bool
+	}{
+		{name: "HPA targeting 1 replica deployment is raised", deploymentReps: int32Ptr(1), expectPatch: true},
+		{name: "HPA with explicit minReplicas 1 is raised", deploymentReps: int32Ptr(1), hpaMinReplicas: int32Ptr(1), expectPatch: true},
+		{name: "HPA with minReplicas at floor is untouched", deploymentReps: int32Ptr(1), hpaMinReplicas: int32Ptr(2), expectPatch: false},
+		{name: "HPA targeting 3 replica deployment is untouched", deploymentReps: int32Ptr(3), expectPatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset()
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "default"},
+				Spec:       appsv1.DeploymentSpec{Replicas: tt.deploymentReps},
+			}
+			if _, err := fakeClient.AppsV1().Deployments("default").Create(
+				context.Background(), deployment, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("Failed to create test deployment: %v", err)
+			}
+
+			v := NewDeploymentHPAValidator(fakeClient)
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-hpa", Namespace: "default"},
+				Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+					MinReplicas:    tt.hpaMinReplicas,
+					ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment"},
+				},
+			}
+
+			result, err := v.MutateHPA(context.Background(), hpa)
+			if err != nil {
+				t.Fatalf("MutateHPA() unexpected error: %v", err)
+			}
+			if result.IsMutated() != tt.expectPatch {
+				t.Fatalf("MutateHPA() mutated = %v, expected %v", result.IsMutated(), tt.expectPatch)
+			}
+			if !tt.expectPatch {
+				return
+			}
+
+			patch := result.Patches[0]
+			if patch.Op != "add" || patch.Path != "/spec/minReplicas" || patch.Value != DefaultMinReplicas {
+				t.Errorf("Unexpected patch: %+v", patch)
+			}
+			if len(result.Warnings) == 0 {
+				t.Error("Expected a warning explaining the mutation")
+			}
+
+			// 修正後のHPAは修正済みと判断されること（バリデーションは対象のDeploymentのreplica数で判定する）
+			hpa.Spec.MinReplicas = int32Ptr(patch.Value.(int32))
+			if !v.HPAMeetsFloor(hpa) {
+				t.Errorf("Mutated HPA does not meet the floor: %+v", hpa.Spec)
+			}
+		})
+	}
+}
+
+func TestNewDeploymentHPAValidatorWithMinReplicas(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+
+	if got := NewDeploymentHPAValidatorWithMinReplicas(fakeClient, 0).MinReplicas(); got != DefaultMinReplicas {
+		t.Errorf("Expected floor below 2 to fall back to %d, got %d", DefaultMinReplicas, got)
+	}
+	if got := NewDeploymentHPAValidatorWithMinReplicas(fakeClient, 4).MinReplicas(); got != 4 {
+		t.Errorf("Expected floor 4, got %d", got)
+	}
+}
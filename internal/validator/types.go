@@ -2,12 +2,15 @@ package validator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Validator defines the interface for validating Kubernetes resources
@@ -70,14 +73,73 @@ const (
 	ErrorTypeResource ErrorType = "resource"
 )
 
+// FieldCause 違反の原因となったフィールド
+type FieldCause struct {
+	// Field 違反したフィールドのパス（例: spec.replicas）
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	// Key メッセージカタログのキー（ローカライズ用）
+	Key string `json:"-"`
+}
+
+// ObjectReference 違反に関係する別のリソース
+type ObjectReference struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// UID 関係するリソースが既に存在する場合のUID（Eventの対象の特定に使用）
+	UID string `json:"uid,omitempty"`
+}
+
+// String はKind/Name形式の文字列を返す
+func (r *ObjectReference) String() string {
+	return r.Kind + "/" + r.Name
+}
+
+// SuggestedFix 違反を解消するための具体的な修正案
+type SuggestedFix struct {
+	Target ObjectReference `json:"target"`
+	// MergePatch JSON merge patch (RFC 7386)
+	MergePatch string `json:"merge_patch"`
+	// Command そのまま実行できるkubectl patchコマンド
+	Command string `json:"command"`
+}
+
+// NewSuggestedFix 修正対象のリソースとmerge patchから修正案を作成
+func NewSuggestedFix(kind, name, namespace string, patch map[string]interface{}) SuggestedFix {
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		// map[string]interface{}の数値と文字列のみを扱うため発生しない
+		patchBytes = []byte("{}")
+	}
+
+	command := fmt.Sprintf("kubectl patch %s %s", strings.ToLower(kind), name)
+	if namespace != "" {
+		command += " -n " + namespace
+	}
+	command += fmt.Sprintf(" --type merge -p '%s'", patchBytes)
+
+	return SuggestedFix{
+		Target:     ObjectReference{Kind: kind, Name: name, Namespace: namespace},
+		MergePatch: string(patchBytes),
+		Command:    command,
+	}
+}
+
 // WebhookError webhook固有のエラー構造体
 type WebhookError struct {
-	Type        ErrorType `json:"type"`
-	Code        string    `json:"code"`
-	Message     string    `json:"message"`
-	Details     string    `json:"details,omitempty"`
-	Suggestions []string  `json:"suggestions,omitempty"`
-	Timestamp   time.Time `json:"timestamp"`
+	Type          ErrorType        `json:"type"`
+	Code          string           `json:"code"`
+	Message       string           `json:"message"`
+	Details       string           `json:"details,omitempty"`
+	Suggestions   []string         `json:"suggestions,omitempty"`
+	Causes        []FieldCause     `json:"causes,omitempty"`
+	RelatedObject *ObjectReference `json:"related_object,omitempty"`
+	Fixes         []SuggestedFix   `json:"fixes,omitempty"`
+	Threshold     int32            `json:"threshold,omitempty"`
+	Current       int32            `json:"current,omitempty"`
+	Language      Language         `json:"language,omitempty"`
+	Timestamp     time.Time        `json:"timestamp"`
 	// 内部情報（ログ用、レスポンスには含めない）
 	InternalError error  `json:"-"`
 	RequestID     string `json:"-"`
@@ -108,6 +170,10 @@ func (e *WebhookError) IsRetryable() bool {
 
 // GetHTTPStatusCode エラーに対応するHTTPステータスコードを取得
 func (e *WebhookError) GetHTTPStatusCode() int {
+	if e.Code == CodeInternalDeadlineExceeded {
+		return 504 // Gateway Timeout
+	}
+
 	switch e.Type {
 	case ErrorTypeValidation:
 		return 400 // Bad Request
@@ -128,37 +194,38 @@ func (e *WebhookError) GetHTTPStatusCode() int {
 	}
 }
 
-// GetProductionMessage 本番環境用の制限されたメッセージを取得
-func (e *WebhookError) GetProductionMessage() string {
+// GetStatusReason エラーに対応するmetav1.StatusReasonを取得
+func (e *WebhookError) GetStatusReason() metav1.StatusReason {
+	switch e.Code {
+	case CodeNetworkTimeout, CodeAPITimeout, CodeInternalDeadlineExceeded:
+		return metav1.StatusReasonTimeout
+	}
+
 	switch e.Type {
 	case ErrorTypeValidation:
-		return e.Message // バリデーションエラーは詳細を表示
-	case ErrorTypeConfiguration:
-		return "設定に問題があります。管理者に連絡してください。"
-	case ErrorTypeNetwork:
-		return "ネットワークエラーが発生しました。しばらく待ってから再試行してください。"
-	case ErrorTypeCertificate:
-		return "証明書に問題があります。管理者に連絡してください。"
-	case ErrorTypeKubernetesAPI:
-		return "Kubernetes APIとの通信に問題があります。しばらく待ってから再試行してください。"
+		return metav1.StatusReasonInvalid
 	case ErrorTypeAuth:
-		return "認証に失敗しました。権限を確認してください。"
-	case ErrorTypeResource:
-		return "リソースが不足しています。しばらく待ってから再試行してください。"
-	case ErrorTypeInternal:
-		return "内部エラーが発生しました。管理者に連絡してください。"
+		return metav1.StatusReasonForbidden
+	case ErrorTypeNetwork, ErrorTypeKubernetesAPI, ErrorTypeResource:
+		return metav1.StatusReasonServiceUnavailable
 	default:
-		return "システムエラーが発生しました。管理者に連絡してください。"
+		return metav1.StatusReasonInternalError
 	}
 }
 
-// Error messages in Japanese
+// GetProductionMessage 本番環境用の制限されたメッセージを既定の言語で取得
+func (e *WebhookError) GetProductionMessage() string {
+	return e.GetProductionMessageIn(DefaultLanguage)
+}
+
+// Error messages in Japanese（既定の言語・既定の下限で描画したカタログのメッセージ）
 const (
 	ErrDeploymentWithHPA    = "1 replicaのDeploymentにHPAが設定されています。HPAを削除するか、replicasを2以上に設定してください。"
 	ErrHPAWithSingleReplica = "1 replicaのDeploymentを対象とするHPAは作成できません。Deploymentのreplicasを2以上に設定してください。"
 	ErrSystemFailure        = "システムエラーが発生しました。管理者に連絡してください。"
 )
 
+
 // エラーコード定数
 const (
 	// バリデーションエラーコード
@@ -193,6 +260,7 @@ const (
 	CodeInternalPanic     = "INTERNAL_PANIC"
 	CodeInternalTemporary = "INTERNAL_TEMPORARY"
 	CodeInternalUnknown   = "INTERNAL_UNKNOWN"
+	CodeInternalDeadlineExceeded = "INTERNAL_DEADLINE_EXCEEDED"
 
 	// 認証エラーコード
 	CodeAuthFailed        = "AUTH_FAILED"
@@ -203,6 +271,11 @@ const (
 	CodeResourceUnavailable = "RESOURCE_UNAVAILABLE"
 )
 
+// IsRuleCode エラーコードがルールの違反（リソースの設定の誤り）を表すかどうか
+func IsRuleCode(code string) bool {
+	return code == CodeDeploymentHPAConflict || code == CodeHPASingleReplica
+}
+
 // NewWebhookError 新しいWebhookErrorを作成
 func NewWebhookError(errorType ErrorType, code, message string) *WebhookError {
 	return &WebhookError{
@@ -251,6 +324,51 @@ func (e *WebhookError) WithInternalError(err error) *WebhookError {
 	return e
 }
 
+// WithCause WebhookErrorに違反したフィールドを追加
+func (e *WebhookError) WithCause(field, message string) *WebhookError {
+	e.Causes = append(e.Causes, FieldCause{Field: field, Message: message})
+	return e
+}
+
+// WithCauseKey メッセージカタログのキーで違反したフィールドを追加
+func (e *WebhookError) WithCauseKey(field, key string) *WebhookError {
+	message := LocalizeFragment(DefaultLanguage, key, e.MessageData())
+	e.Causes = append(e.Causes, FieldCause{Field: field, Message: message, Key: key})
+	return e
+}
+
+// WithReplicaCounts WebhookErrorにreplica数の下限と現在値を設定し、既定の言語で再描画
+func (e *WebhookError) WithReplicaCounts(threshold, current int32) *WebhookError {
+	e.Threshold = threshold
+	e.Current = current
+	localized := e.Localize(DefaultLanguage)
+	e.Message = localized.Message
+	e.Details = localized.Details
+	e.Suggestions = localized.Suggestions
+	e.Causes = localized.Causes
+	return e
+}
+
+// WithRelatedObject WebhookErrorに関係するリソースを設定
+func (e *WebhookError) WithRelatedObject(kind, name, namespace string) *WebhookError {
+	e.RelatedObject = &ObjectReference{Kind: kind, Name: name, Namespace: namespace}
+	return e
+}
+
+// WithRelatedObjectUID WebhookErrorに関係するリソースのUIDを設定（WithRelatedObjectの後に呼び出す）
+func (e *WebhookError) WithRelatedObjectUID(uid string) *WebhookError {
+	if e.RelatedObject != nil {
+		e.RelatedObject.UID = uid
+	}
+	return e
+}
+
+// WithFix WebhookErrorに修正案を追加
+func (e *WebhookError) WithFix(fix SuggestedFix) *WebhookError {
+	e.Fixes = append(e.Fixes, fix)
+	return e
+}
+
 // WithSuggestions WebhookErrorに提案を追加
 func (e *WebhookError) WithSuggestions(suggestions []string) *WebhookError {
 	e.Suggestions = suggestions
@@ -261,30 +379,20 @@ func (e *WebhookError) WithSuggestions(suggestions []string) *WebhookError {
 
 // NewDeploymentHPAConflictError Deployment-HPA競合エラーを作成
 func NewDeploymentHPAConflictError() *WebhookError {
-	return NewWebhookErrorWithDetails(
+	return NewWebhookError(
 		ErrorTypeValidation,
 		CodeDeploymentHPAConflict,
 		ErrDeploymentWithHPA,
-		"HPAが正常に動作するためには、対象のDeploymentのreplica数が2以上である必要があります。",
-		[]string{
-			"Deploymentのspec.replicasを2以上に設定してください",
-			"または、HPAを削除してください",
-		},
-	)
+	).Localize(DefaultLanguage)
 }
 
 // NewHPASingleReplicaError HPA単一レプリカエラーを作成
 func NewHPASingleReplicaError() *WebhookError {
-	return NewWebhookErrorWithDetails(
+	return NewWebhookError(
 		ErrorTypeValidation,
 		CodeHPASingleReplica,
 		ErrHPAWithSingleReplica,
-		"HPAは最低2つのレプリカが必要です。1つのレプリカでは自動スケーリングが機能しません。",
-		[]string{
-			"対象のDeploymentのspec.replicasを2以上に設定してください",
-			"HPAのspec.minReplicasを2以上に設定してください",
-		},
-	)
+	).Localize(DefaultLanguage)
 }
 
 // NewKubernetesAPIError Kubernetes APIエラーを作成
@@ -339,6 +447,15 @@ func NewInternalError(component string, err error) *WebhookError {
 	})
 }
 
+// NewDeadlineExceededError リクエストの処理期限超過エラーを作成
+func NewDeadlineExceededError(deadline time.Duration) *WebhookError {
+	return NewWebhookErrorFromError(
+		ErrorTypeInternal,
+		CodeInternalDeadlineExceeded,
+		fmt.Errorf("deadline %s: %w", deadline, context.DeadlineExceeded),
+	).Localize(DefaultLanguage)
+}
+
 // GetType エラータイプを取得（メトリクス用）
 func (e *WebhookError) GetType() string {
 	return string(e.Type)
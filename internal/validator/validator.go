@@ -11,22 +11,83 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// DefaultMinReplicas HPAの対象となるDeploymentに要求されるreplica数の下限
+const DefaultMinReplicas int32 = 2
+
+// ResourceLookup 検証時に関係するDeploymentとHPAを取得する方法
+// デフォルトはKubernetes APIから取得し、監査スキャンではinformerのキャッシュから取得する
+type ResourceLookup interface {
+	// ListHPAs namespace内のHPAを取得
+	ListHPAs(ctx context.Context, namespace string) ([]autoscalingv2.HorizontalPodAutoscaler, error)
+	// GetDeployment Deploymentを取得（存在しない場合はエラー）
+	GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error)
+}
+
+// clientLookup Kubernetes APIからリソースを取得するResourceLookup
+type clientLookup struct {
+	client kubernetes.Interface
+}
+
+// ListHPAs namespace内のHPAをKubernetes APIから取得
+func (l clientLookup) ListHPAs(ctx context.Context, namespace string) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpaList, err := l.client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return hpaList.Items, nil
+}
+
+// GetDeployment DeploymentをKubernetes APIから取得
+func (l clientLookup) GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	return l.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
 // DeploymentHPAValidator implements the Validator interface
 type DeploymentHPAValidator struct {
-	client kubernetes.Interface
+	lookup      ResourceLookup
+	minReplicas int32
 }
 
 // NewDeploymentHPAValidator creates a new validator instance
 func NewDeploymentHPAValidator(client kubernetes.Interface) *DeploymentHPAValidator {
+	return NewDeploymentHPAValidatorWithMinReplicas(client, DefaultMinReplicas)
+}
+
+// NewDeploymentHPAValidatorWithMinReplicas creates a new validator instance with a replica floor
+func NewDeploymentHPAValidatorWithMinReplicas(client kubernetes.Interface, minReplicas int32) *DeploymentHPAValidator {
+	// 下限が2未満ではHPAが機能しないため、デフォルト値を使用
+	if minReplicas < DefaultMinReplicas {
+		minReplicas = DefaultMinReplicas
+	}
 	return &DeploymentHPAValidator{
-		client: client,
+		lookup:      clientLookup{client: client},
+		minReplicas: minReplicas,
 	}
 }
 
+// WithLookup 関係するリソースの取得方法を差し替えた同じ設定のvalidatorを返す
+func (v *DeploymentHPAValidator) WithLookup(lookup ResourceLookup) *DeploymentHPAValidator {
+	return &DeploymentHPAValidator{
+		lookup:      lookup,
+		minReplicas: v.minReplicas,
+	}
+}
+
+// MinReplicas returns the replica floor enforced by the validator
+func (v *DeploymentHPAValidator) MinReplicas() int32 {
+	return v.minReplicas
+}
+
+// isBelowFloor reports whether an explicit replica count is below the floor.
+// nil と 0 は対象外（nilはAPIサーバーのデフォルト、0はスケールダウン中）
+func (v *DeploymentHPAValidator) isBelowFloor(replicas *int32) bool {
+	return replicas != nil && *replicas > 0 && *replicas < v.minReplicas
+}
+
 // ValidateDeployment validates a Deployment resource
 func (v *DeploymentHPAValidator) ValidateDeployment(ctx context.Context, deployment *appsv1.Deployment) error {
-	// Check if deployment has 1 replica
-	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 1 {
+	// Check if deployment has fewer replicas than the floor
+	if v.isBelowFloor(deployment.Spec.Replicas) {
 		// Search for HPAs that target this deployment
 		hpa, err := v.findHPAForDeployment(ctx, deployment)
 		if err != nil {
@@ -37,6 +98,16 @@ func (v *DeploymentHPAValidator) ValidateDeployment(ctx context.Context, deploym
 		if hpa != nil {
 			return NewDeploymentHPAConflictError().WithContext(
 				"", "Deployment", deployment.Name, deployment.Namespace,
+			).WithRelatedObject(
+				"HorizontalPodAutoscaler", hpa.Name, hpa.Namespace,
+			).WithRelatedObjectUID(
+				string(hpa.UID),
+			).WithReplicaCounts(
+				v.minReplicas, *deployment.Spec.Replicas,
+			).WithCauseKey(
+				"spec.replicas", CauseDeploymentReplicas,
+			).WithFix(
+				v.replicasFix(deployment.Name, deployment.Namespace),
 			)
 		}
 	}
@@ -58,24 +129,52 @@ func (v *DeploymentHPAValidator) ValidateHPA(ctx context.Context, hpa *autoscali
 		)
 	}
 
-	// Check if target deployment has 1 replica
-	if deployment != nil && deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 1 {
+	// Check if target deployment has fewer replicas than the floor
+	if deployment != nil && v.isBelowFloor(deployment.Spec.Replicas) {
 		return NewHPASingleReplicaError().WithContext(
 			"", "HorizontalPodAutoscaler", hpa.Name, hpa.Namespace,
+		).WithRelatedObject(
+			"Deployment", deployment.Name, deployment.Namespace,
+		).WithRelatedObjectUID(
+			string(deployment.UID),
+		).WithReplicaCounts(
+			v.minReplicas, *deployment.Spec.Replicas,
+		).WithCauseKey(
+			"spec.scaleTargetRef.name", CauseHPAScaleTarget,
+		).WithCauseKey(
+			"spec.minReplicas", CauseHPAMinReplicas,
+		).WithFix(
+			v.minReplicasFix(hpa.Name, hpa.Namespace),
+		).WithFix(
+			v.replicasFix(deployment.Name, deployment.Namespace),
 		)
 	}
 
 	return nil
 }
 
+// replicasFix suggests raising a Deployment's spec.replicas to the floor
+func (v *DeploymentHPAValidator) replicasFix(name, namespace string) SuggestedFix {
+	return NewSuggestedFix("Deployment", name, namespace, map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": v.minReplicas},
+	})
+}
+
+// minReplicasFix suggests raising an HPA's spec.minReplicas to the floor
+func (v *DeploymentHPAValidator) minReplicasFix(name, namespace string) SuggestedFix {
+	return NewSuggestedFix("HorizontalPodAutoscaler", name, namespace, map[string]interface{}{
+		"spec": map[string]interface{}{"minReplicas": v.minReplicas},
+	})
+}
+
 // findHPAForDeployment searches for HPAs that target the given deployment
 func (v *DeploymentHPAValidator) findHPAForDeployment(ctx context.Context, deployment *appsv1.Deployment) (*autoscalingv2.HorizontalPodAutoscaler, error) {
-	hpaList, err := v.client.AutoscalingV2().HorizontalPodAutoscalers(deployment.Namespace).List(ctx, metav1.ListOptions{})
+	hpas, err := v.lookup.ListHPAs(ctx, deployment.Namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, hpa := range hpaList.Items {
+	for _, hpa := range hpas {
 		if hpa.Spec.ScaleTargetRef.Kind == "Deployment" &&
 			hpa.Spec.ScaleTargetRef.Name == deployment.Name {
 			return &hpa, nil
@@ -86,7 +185,7 @@ func (v *DeploymentHPAValidator) findHPAForDeployment(ctx context.Context, deplo
 
 // getTargetDeployment retrieves the deployment targeted by the given HPA
 func (v *DeploymentHPAValidator) getTargetDeployment(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) (*appsv1.Deployment, error) {
-	deployment, err := v.client.AppsV1().Deployments(hpa.Namespace).Get(ctx, hpa.Spec.ScaleTargetRef.Name, metav1.GetOptions{})
+	deployment, err := v.lookup.GetDeployment(ctx, hpa.Namespace, hpa.Spec.ScaleTargetRef.Name)
 	if err != nil {
 		// If deployment doesn't exist, return nil without error (it might be created later)
 		return nil, nil
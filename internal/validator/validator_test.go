@@ -233,6 +233,34 @@ func TestValidateHPA(t *testing.T) {
 			expectedError:  true,
 			expectedErrMsg: ErrHPAWithSingleReplica,
 		},
+		{
+			// minReplicasが下限以上でも、対象のDeploymentが1 replicaのままであれば拒否する
+			name: "Invalid HPA with minReplicas at the floor targeting deployment with 1 replica",
+			hpa: &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-hpa",
+					Namespace: "default",
+				},
+				Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+					MinReplicas: int32Ptr(2),
+					ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "test-deployment",
+					},
+				},
+			},
+			existingDeployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment",
+					Namespace: "default",
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: int32Ptr(1),
+				},
+			},
+			expectedError:  true,
+			expectedErrMsg: ErrHPAWithSingleReplica,
+		},
 		{
 			name: "HPA targeting non-existent deployment",
 			hpa: &autoscalingv2.HorizontalPodAutoscaler{
@@ -326,6 +354,67 @@ func TestValidateHPA(t *testing.T) {
 	}
 }
 
+func TestValidationErrorCauses(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "default", UID: "deployment-uid"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(1)},
+		},
+		&autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-hpa", Namespace: "default", UID: "hpa-uid"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment"},
+			},
+		},
+	)
+	v := NewDeploymentHPAValidator(fakeClient)
+
+	t.Run("Deployment", func(t *testing.T) {
+		err := v.ValidateDeployment(context.Background(), &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(1)},
+		})
+		webhookErr, ok := err.(*WebhookError)
+		if !ok {
+			t.Fatalf("Expected WebhookError, got %v", err)
+		}
+		if webhookErr.RelatedObject == nil || webhookErr.RelatedObject.Name != "test-hpa" || webhookErr.RelatedObject.UID != "hpa-uid" {
+			t.Errorf("RelatedObject = %+v, want test-hpa (hpa-uid)", webhookErr.RelatedObject)
+		}
+		if len(webhookErr.Causes) != 1 || webhookErr.Causes[0].Field != "spec.replicas" {
+			t.Errorf("Causes = %+v, want spec.replicas", webhookErr.Causes)
+		}
+	})
+
+	t.Run("HorizontalPodAutoscaler", func(t *testing.T) {
+		err := v.ValidateHPA(context.Background(), &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "new-hpa", Namespace: "default"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment"},
+			},
+		})
+		webhookErr, ok := err.(*WebhookError)
+		if !ok {
+			t.Fatalf("Expected WebhookError, got %v", err)
+		}
+		if webhookErr.RelatedObject == nil || webhookErr.RelatedObject.String() != "Deployment/test-deployment" || webhookErr.RelatedObject.UID != "deployment-uid" {
+			t.Errorf("RelatedObject = %+v, want Deployment/test-deployment (deployment-uid)", webhookErr.RelatedObject)
+		}
+		fields := []string{}
+		for _, cause := range webhookErr.Causes {
+			fields = append(fields, cause.Field)
+		}
+		if fmt.Sprint(fields) != "[spec.scaleTargetRef.name spec.minReplicas]" {
+			t.Errorf("Cause fields = %v", fields)
+		}
+		if len(webhookErr.Fixes) != 2 ||
+			webhookErr.Fixes[0].Target.String() != "HorizontalPodAutoscaler/new-hpa" ||
+			webhookErr.Fixes[1].Target.String() != "Deployment/test-deployment" {
+			t.Errorf("Fixes = %+v", webhookErr.Fixes)
+		}
+	})
+}
+
 func TestCreateValidationResult(t *testing.T) {
 	tests := []struct {
 		name           string
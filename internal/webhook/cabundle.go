@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"k8s-deployment-hpa-validator/internal/cert"
+	"k8s-deployment-hpa-validator/internal/config"
+)
+
+// caBundleCheckTimeout caBundleの確認1回あたりの期限
+const caBundleCheckTimeout = 10 * time.Second
+
+// newCABundleChecker 設定からcaBundleの確認を作成（webhook設定の名前が未設定の場合はnil）
+// ミューテーションが有効な場合は同名のMutatingWebhookConfigurationも確認する
+func newCABundleChecker(client kubernetes.Interface, certManager *cert.Manager, cfg *config.WebhookConfig) *cert.CABundleChecker {
+	if cfg.WebhookConfigurationName == "" {
+		return nil
+	}
+	mutatingName := ""
+	if cfg.MutationEnabled {
+		mutatingName = cfg.WebhookConfigurationName
+	}
+	return cert.NewCABundleChecker(client, certManager, cfg.WebhookConfigurationName, mutatingName)
+}
+
+// runCABundleCheck 起動時と一定間隔ごとにcaBundleを確認する（ctxが終了するまで）
+func (s *Server) runCABundleCheck(ctx context.Context) {
+	s.checkCABundle(ctx)
+
+	ticker := time.NewTicker(s.config.CABundleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkCABundle(ctx)
+		}
+	}
+}
+
+// checkCABundle 現在のサーバー証明書がwebhook設定のcaBundleで検証できるかを確認し、不一致をログに記録
+func (s *Server) checkCABundle(ctx context.Context) {
+	if s.caBundleChecker == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, caBundleCheckTimeout)
+	defer cancel()
+
+	status := s.caBundleChecker.Check(ctx)
+	if status.Err != nil {
+		s.logger.Warn("caBundleの確認に失敗しました", map[string]interface{}{
+			"error": status.Err.Error(),
+		})
+		return
+	}
+	for _, mismatch := range status.Mismatches() {
+		s.logger.Error("サーバー証明書がwebhook設定のcaBundleで検証できません。API serverからの呼び出しが失敗します", map[string]interface{}{
+			"configuration": mismatch.Configuration,
+			"webhook":       mismatch.Webhook,
+			"error":         mismatch.Error,
+		})
+	}
+}
+
+// verifyCABundleBeforeSwap 再読み込みした証明書に差し替える前に、webhook設定のcaBundleで検証できるかを確認
+// 差し替えは止めないが、API serverからの呼び出しが失敗する前にエラーとして記録する
+func (s *Server) verifyCABundleBeforeSwap(certificate tls.Certificate) {
+	ctx, cancel := context.WithTimeout(context.Background(), caBundleCheckTimeout)
+	defer cancel()
+
+	status := s.caBundleChecker.Verify(ctx, &certificate)
+	if status.Err != nil {
+		s.logger.Warn("差し替え前のcaBundleの確認に失敗しました", map[string]interface{}{
+			"error": status.Err.Error(),
+		})
+		return
+	}
+	for _, mismatch := range status.Mismatches() {
+		s.logger.Error("新しいサーバー証明書がwebhook設定のcaBundleで検証できません。差し替え後はAPI serverからの呼び出しが失敗します", map[string]interface{}{
+			"configuration": mismatch.Configuration,
+			"webhook":       mismatch.Webhook,
+			"error":         mismatch.Error,
+		})
+	}
+}
+
+// caBundleHealth ヘルスチェックのcaBundleの状態（確認していない場合はnil）
+// 不一致がある場合はfalseを返す
+func (s *Server) caBundleHealth() (map[string]interface{}, bool) {
+	if s.caBundleChecker == nil {
+		return nil, true
+	}
+
+	status := s.caBundleChecker.LastStatus()
+	if status == nil {
+		return map[string]interface{}{"status": "unknown"}, true
+	}
+	if status.Err != nil {
+		// 確認できない場合（権限不足など）は不一致とはみなさない
+		return map[string]interface{}{
+			"status":     "unknown",
+			"error":      status.Err.Error(),
+			"checked_at": status.CheckedAt.UTC().Format(time.RFC3339),
+		}, true
+	}
+
+	component := map[string]interface{}{
+		"status":     "healthy",
+		"webhooks":   len(status.Results),
+		"checked_at": status.CheckedAt.UTC().Format(time.RFC3339),
+	}
+	if mismatches := status.Mismatches(); len(mismatches) > 0 {
+		component["status"] = "unhealthy"
+		component["mismatches"] = mismatches
+		return component, false
+	}
+	return component, true
+}
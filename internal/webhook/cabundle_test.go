@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s-deployment-hpa-validator/internal/cert"
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+// newBootstrappedClient 自己署名CAで証明書を発行し、caBundleを設定したfake clientsetを作成
+func newBootstrappedClient(t *testing.T, cfg *config.WebhookConfig) *fake.Clientset {
+	t.Helper()
+
+	client := fake.NewSimpleClientset(&admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: cfg.WebhookConfigurationName},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name: "deployment.hpa-validator.io",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{Namespace: cfg.TLSSecretNamespace, Name: cfg.CertBootstrapService},
+			},
+		}},
+	})
+	if err := newCertBootstrapper(client, cfg).Reconcile(context.Background()); err != nil {
+		t.Fatalf("証明書の自動発行に失敗: %v", err)
+	}
+	return client
+}
+
+func TestServer_checkCABundle(t *testing.T) {
+	cfg := &config.WebhookConfig{
+		Environment:              "development",
+		HealthEnabled:            true,
+		TLSSource:                config.TLSSourceSecret,
+		TLSSecretName:            "webhook-tls",
+		TLSSecretNamespace:       "webhook-system",
+		CertBootstrapService:     "k8s-deployment-hpa-validator",
+		WebhookConfigurationName: "k8s-deployment-hpa-validator",
+	}
+	client := newBootstrappedClient(t, cfg)
+
+	certManager := cert.NewManagerFromSecret(client, cfg.TLSSecretNamespace, cfg.TLSSecretName)
+	if _, err := certManager.LoadCertificate(); err != nil {
+		t.Fatalf("証明書の読み込みに失敗: %v", err)
+	}
+
+	logger := logging.NewLogger("test-webhook")
+	server := &Server{
+		client:          client,
+		validator:       validator.NewDeploymentHPAValidator(client),
+		certManager:     certManager,
+		caBundleChecker: newCABundleChecker(client, certManager, cfg),
+		logger:          logger,
+		config:          cfg,
+		errorHandler:    NewErrorHandler(cfg, logger),
+	}
+
+	caBundleComponent := func() map[string]interface{} {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.handleHealth(w, httptest.NewRequest("GET", "/health", nil))
+		var health HealthStatus
+		if err := json.Unmarshal(w.Body.Bytes(), &health); err != nil {
+			t.Fatalf("Failed to unmarshal health response: %v", err)
+		}
+		component, ok := health.Components["ca_bundle"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected ca_bundle component, got %v", health.Components)
+		}
+		return component
+	}
+
+	if status := caBundleComponent()["status"]; status != "unknown" {
+		t.Errorf("Expected ca_bundle status unknown before the first check, got %v", status)
+	}
+
+	server.checkCABundle(context.Background())
+	if status := caBundleComponent()["status"]; status != "healthy" {
+		t.Errorf("Expected ca_bundle status healthy, got %v", status)
+	}
+
+	// 別のCAのcaBundleに差し替えられると不一致として報告する
+	other := newBootstrappedClient(t, cfg)
+	otherConfig, err := other.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), cfg.WebhookConfigurationName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get webhook configuration: %v", err)
+	}
+	if _, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(context.Background(), otherConfig, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update webhook configuration: %v", err)
+	}
+
+	server.checkCABundle(context.Background())
+	component := caBundleComponent()
+	if component["status"] != "unhealthy" {
+		t.Errorf("Expected ca_bundle status unhealthy, got %v", component["status"])
+	}
+	mismatches, ok := component["mismatches"].([]interface{})
+	if !ok || len(mismatches) != 1 {
+		t.Errorf("Expected one mismatch, got %v", component["mismatches"])
+	}
+}
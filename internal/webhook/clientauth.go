@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+)
+
+// クライアント証明書による認証の失敗理由（メトリクスのreasonラベル）
+const (
+	clientAuthMissing    = "missing"
+	clientAuthUntrusted  = "untrusted"
+	clientAuthNotAllowed = "not_allowed"
+)
+
+// clientAuthenticator クライアント証明書で呼び出し元（kube-apiserver）を認証する
+// 失敗理由ごとにメトリクスとログを記録するため、証明書の検証はcrypto/tlsではなくVerifyConnectionで行う
+type clientAuthenticator struct {
+	roots *x509.CertPool
+	// allowedNames 許可するCN・SANのglobパターン（空の場合はCAで検証できる全ての証明書を許可する）
+	allowedNames []string
+	logger       *logging.Logger
+}
+
+// newClientAuthenticator 設定のCA証明書ファイルと許可リストからクライアント認証を作成
+func newClientAuthenticator(cfg *config.WebhookConfig, logger *logging.Logger) (*clientAuthenticator, error) {
+	caPEM, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("failed to parse client CA file: %s", cfg.ClientCAFile)
+	}
+
+	return &clientAuthenticator{
+		roots:        roots,
+		allowedNames: cfg.ClientAllowedNames,
+		logger:       logger,
+	}, nil
+}
+
+// apply TLS設定でクライアント証明書を要求し、ハンドシェイクごとに検証する
+func (a *clientAuthenticator) apply(tlsConfig *tls.Config) {
+	base := tlsConfig.Clone()
+	// 証明書がない場合も理由を記録するため、要求のみ行いVerifyConnectionで拒否する
+	base.ClientAuth = tls.RequestClientCert
+
+	tlsConfig.ClientAuth = tls.RequestClientCert
+	tlsConfig.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		var remoteAddr net.Addr
+		if hello.Conn != nil {
+			remoteAddr = hello.Conn.RemoteAddr()
+		}
+		perConn := base.Clone()
+		perConn.VerifyConnection = func(state tls.ConnectionState) error {
+			return a.verifyConnection(state, remoteAddr)
+		}
+		return perConn, nil
+	}
+}
+
+// verifyConnection クライアント証明書をCAで検証し、CN・SANが許可リストに含まれるかを確認
+func (a *clientAuthenticator) verifyConnection(state tls.ConnectionState, remoteAddr net.Addr) error {
+	if len(state.PeerCertificates) == 0 {
+		return a.reject(clientAuthMissing, remoteAddr, nil, fmt.Errorf("クライアント証明書がありません"))
+	}
+
+	leaf := state.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         a.roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return a.reject(clientAuthUntrusted, remoteAddr, leaf, err)
+	}
+
+	if !a.isAllowed(leaf) {
+		return a.reject(clientAuthNotAllowed, remoteAddr, leaf, fmt.Errorf("CN・SANが許可リストに含まれていません"))
+	}
+	return nil
+}
+
+// isAllowed 証明書のCNまたはSAN（DNS名、URI、メールアドレス）が許可リストのいずれかに一致するかどうか
+func (a *clientAuthenticator) isAllowed(cert *x509.Certificate) bool {
+	if len(a.allowedNames) == 0 {
+		return true
+	}
+	for _, name := range certificateNames(cert) {
+		for _, pattern := range a.allowedNames {
+			if matched, _ := path.Match(pattern, name); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reject 認証の失敗をメトリクスとログに記録し、ハンドシェイクを中断するエラーを返す
+func (a *clientAuthenticator) reject(reason string, remoteAddr net.Addr, cert *x509.Certificate, err error) error {
+	metrics.RecordClientAuthFailure(reason)
+
+	fields := map[string]interface{}{
+		"reason": reason,
+		"error":  err.Error(),
+	}
+	if remoteAddr != nil {
+		fields["remote_addr"] = remoteAddr.String()
+	}
+	if cert != nil {
+		fields["subject"] = cert.Subject.String()
+		fields["issuer"] = cert.Issuer.String()
+		fields["names"] = certificateNames(cert)
+	}
+	a.logger.Warn("クライアント証明書による認証に失敗したため、接続を拒否しました", fields)
+
+	return fmt.Errorf("client certificate rejected (%s): %w", reason, err)
+}
+
+// certificateNames 証明書のCNとSAN
+func certificateNames(cert *x509.Certificate) []string {
+	var names []string
+	if cert.Subject.CommonName != "" {
+		names = append(names, cert.Subject.CommonName)
+	}
+	names = append(names, cert.DNSNames...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	names = append(names, cert.EmailAddresses...)
+	return names
+}
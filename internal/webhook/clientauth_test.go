@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+)
+
+// testIssuer テスト用の証明書を発行するCA
+type testIssuer struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate CA key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse CA certificate: %v", err)
+	}
+	return &testIssuer{cert: cert, key: key}
+}
+
+// issue CAで署名した証明書を発行
+func (i *testIssuer) issue(t *testing.T, commonName string, dnsNames []string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, i.cert, key.Public(), i.key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// writeCAFile CA証明書をPEMファイルに書き出す
+func (i *testIssuer) writeCAFile(t *testing.T) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "client-ca.crt")
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: i.cert.Raw}), 0600); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+	return file
+}
+
+func TestClientAuthenticator(t *testing.T) {
+	clientCA := newTestIssuer(t)
+	serverCA := newTestIssuer(t)
+
+	cfg := &config.WebhookConfig{
+		ClientAuthEnabled:  true,
+		ClientCAFile:       clientCA.writeCAFile(t),
+		ClientAllowedNames: []string{"kube-apiserver", "*.apiserver.example.com"},
+	}
+	authenticator, err := newClientAuthenticator(cfg, logging.NewLogger("test-webhook"))
+	if err != nil {
+		t.Fatalf("newClientAuthenticator() error = %v", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{serverCA.issue(t, "webhook", []string{"localhost"}, x509.ExtKeyUsageServerAuth)},
+		MinVersion:   tls.VersionTLS12,
+	}
+	authenticator.apply(tlsConfig)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	untrustedCA := newTestIssuer(t)
+	tests := []struct {
+		name         string
+		certificates []tls.Certificate
+		maxVersion   uint16
+		expectReason string
+	}{
+		{
+			name:         "allowed common name",
+			certificates: []tls.Certificate{clientCA.issue(t, "kube-apiserver", nil, x509.ExtKeyUsageClientAuth)},
+		},
+		{
+			name:         "allowed SAN pattern over TLS 1.2",
+			certificates: []tls.Certificate{clientCA.issue(t, "apiserver", []string{"node1.apiserver.example.com"}, x509.ExtKeyUsageClientAuth)},
+			maxVersion:   tls.VersionTLS12,
+		},
+		{
+			name:         "no client certificate",
+			expectReason: clientAuthMissing,
+		},
+		{
+			name:         "certificate from untrusted CA",
+			certificates: []tls.Certificate{untrustedCA.issue(t, "kube-apiserver", nil, x509.ExtKeyUsageClientAuth)},
+			expectReason: clientAuthUntrusted,
+		},
+		{
+			name:         "certificate without client auth usage",
+			certificates: []tls.Certificate{clientCA.issue(t, "kube-apiserver", nil, x509.ExtKeyUsageServerAuth)},
+			expectReason: clientAuthUntrusted,
+		},
+		{
+			name:         "name not in allowlist",
+			certificates: []tls.Certificate{clientCA.issue(t, "attacker", []string{"attacker.example.com"}, x509.ExtKeyUsageClientAuth)},
+			expectReason: clientAuthNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before float64
+			if tt.expectReason != "" {
+				before = testutil.ToFloat64(metrics.WebhookClientAuthFailures.WithLabelValues(tt.expectReason))
+			}
+
+			client := &http.Client{
+				Timeout: 5 * time.Second,
+				Transport: &http.Transport{TLSClientConfig: &tls.Config{
+					Certificates:       tt.certificates,
+					InsecureSkipVerify: true,
+					MaxVersion:         tt.maxVersion,
+				}},
+			}
+			resp, err := client.Get("https://" + listener.Addr().String() + "/validate")
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if tt.expectReason == "" {
+				if err != nil {
+					t.Fatalf("Expected the request to succeed, got %v", err)
+				}
+				if resp.StatusCode != http.StatusOK {
+					t.Errorf("Expected status 200, got %d", resp.StatusCode)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Expected the connection to be rejected (%s)", tt.expectReason)
+			}
+			if after := testutil.ToFloat64(metrics.WebhookClientAuthFailures.WithLabelValues(tt.expectReason)); after != before+1 {
+				t.Errorf("Expected %s failures to increase by 1, got %v -> %v", tt.expectReason, before, after)
+			}
+		})
+	}
+}
+
+func TestNewClientAuthenticator_InvalidCAFile(t *testing.T) {
+	logger := logging.NewLogger("test-webhook")
+
+	if _, err := newClientAuthenticator(&config.WebhookConfig{ClientCAFile: filepath.Join(t.TempDir(), "missing.crt")}, logger); err == nil {
+		t.Error("Expected an error for a missing CA file")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.crt")
+	if err := os.WriteFile(invalid, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := newClientAuthenticator(&config.WebhookConfig{ClientCAFile: invalid}, logger); err == nil {
+		t.Error("Expected an error for an invalid CA file")
+	}
+}
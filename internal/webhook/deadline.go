@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+const (
+	// defaultWebhookTimeout API serverがtimeoutを指定しない場合のwebhookのtimeoutSeconds（Kubernetesの既定値）
+	defaultWebhookTimeout = 10 * time.Second
+
+	// admissionDeadlineMargin レスポンスの書き込みのためにwebhookのタイムアウトから差し引く時間
+	admissionDeadlineMargin = 500 * time.Millisecond
+
+	// AuditAnnotationDeadlineExceeded 処理期限の超過により許可したことを示す監査アノテーションのキー
+	AuditAnnotationDeadlineExceeded = "deadline-exceeded"
+)
+
+// admissionHandler AdmissionRequestを処理してレスポンスを返す関数
+type admissionHandler func(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+// admissionDeadline リクエストごとの処理期限を決定
+// 設定のTimeoutを、API serverが指定したwebhookのタイムアウト（timeoutクエリパラメータ）より短く制限する
+func (s *Server) admissionDeadline(r *http.Request) time.Duration {
+	webhookTimeout := defaultWebhookTimeout
+	if r != nil {
+		if value := r.URL.Query().Get("timeout"); value != "" {
+			if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+				webhookTimeout = parsed
+			}
+		}
+	}
+
+	limit := webhookTimeout - admissionDeadlineMargin
+	if limit <= 0 {
+		limit = webhookTimeout / 2
+	}
+
+	if s.config.Timeout > 0 && s.config.Timeout < limit {
+		return s.config.Timeout
+	}
+	return limit
+}
+
+// withAdmissionDeadline 処理期限付きでAdmissionRequestを処理
+// 期限を超過した場合、allowOnTimeoutがtrueなら警告付きで許可し、falseならタイムアウトエラーで拒否する
+func (s *Server) withAdmissionDeadline(ctx context.Context, deadline time.Duration, req *admissionv1.AdmissionRequest, allowOnTimeout bool, handle admissionHandler) *admissionv1.AdmissionResponse {
+	if req == nil || deadline <= 0 {
+		return handle(ctx, req)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	// 期限を超過した処理の完了を待たずに応答するため、別のgoroutineで実行
+	// 処理中のEventの記録などは保留し、そのレスポンスを採用した場合のみ実行する
+	handlerCtx, effects := withAdmissionEffects(ctx)
+	done := make(chan *admissionv1.AdmissionResponse, 1)
+	go func() {
+		done <- handle(handlerCtx, req)
+	}()
+
+	select {
+	case response := <-done:
+		// 期限切れのコンテキストによるKubernetes APIエラーなども期限超過として扱う
+		if !response.Allowed && ctx.Err() != nil && response.Result != nil && response.Result.Code >= http.StatusInternalServerError {
+			effects.discard()
+			return s.deadlineResponse(ctx, deadline, req, allowOnTimeout)
+		}
+		effects.commit()
+		return response
+	case <-ctx.Done():
+		effects.discard()
+		return s.deadlineResponse(ctx, deadline, req, allowOnTimeout)
+	}
+}
+
+// deadlineResponse 処理期限を超過したリクエストのレスポンスを作成
+func (s *Server) deadlineResponse(ctx context.Context, deadline time.Duration, req *admissionv1.AdmissionRequest, allowOnTimeout bool) *admissionv1.AdmissionResponse {
+	requestID := logging.RequestIDFromContext(ctx)
+	webhookErr := validator.NewDeadlineExceededError(deadline).WithContext(requestID, req.Kind.Kind, req.Name, req.Namespace)
+
+	// 期限切れのコンテキストではnamespaceを取得できないため、短い期限の新しいコンテキストで応答を作成する
+	responseCtx, cancel := context.WithTimeout(logging.ContextWithRequestID(context.Background(), requestID), admissionDeadlineMargin/2)
+	defer cancel()
+
+	if !allowOnTimeout {
+		return s.handleValidationResult(responseCtx, webhookErr, req)
+	}
+
+	metrics.RecordWebhookError(webhookErr)
+	s.logger.WithRequestID(requestID).Warn("処理期限を超過したため、失敗ポリシー(Ignore)に従いリクエストを許可します", map[string]interface{}{
+		"resource_type": req.Kind.Kind,
+		"resource_name": req.Name,
+		"namespace":     req.Namespace,
+		"deadline":      deadline.String(),
+		"error_code":    webhookErr.Code,
+	})
+
+	lang := s.errorHandler.ResolveLanguage(responseCtx, req.Namespace)
+	warning := validator.LocalizeFragment(lang, validator.WarnDeadlineExceededAllowed, webhookErr.MessageData())
+	s.recordEvent(responseCtx, req, webhookErr, EventReasonDeadlineExceeded, warning)
+	return &admissionv1.AdmissionResponse{
+		UID:      req.UID,
+		Allowed:  true,
+		Warnings: []string{warning},
+		AuditAnnotations: map[string]string{
+			AuditAnnotationDeadlineExceeded: deadline.String(),
+		},
+	}
+}
+
+// allowOnDeadline 処理期限の超過時にリクエストを許可するかどうか（失敗ポリシーがIgnoreの場合）
+func (s *Server) allowOnDeadline() bool {
+	return s.config.FailurePolicy == "Ignore"
+}
@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-deployment-hpa-validator/internal/config"
+)
+
+// newSlowTestClient HPAの一覧取得がdelayだけ遅延するKubernetes APIのクライアントを作成
+func newSlowTestClient(delay time.Duration) *fake.Clientset {
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("list", "horizontalpodautoscalers", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		time.Sleep(delay)
+		return false, nil, nil
+	})
+	return fakeClient
+}
+
+func TestServer_admissionDeadline(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		url      string
+		expected time.Duration
+	}{
+		{name: "config timeout below webhook timeout", timeout: 3 * time.Second, url: "/validate?timeout=10s", expected: 3 * time.Second},
+		{name: "config timeout capped by webhook timeout", timeout: 30 * time.Second, url: "/validate?timeout=5s", expected: 5*time.Second - admissionDeadlineMargin},
+		{name: "default webhook timeout", timeout: 30 * time.Second, url: "/validate", expected: defaultWebhookTimeout - admissionDeadlineMargin},
+		{name: "invalid timeout parameter", timeout: 30 * time.Second, url: "/validate?timeout=abc", expected: defaultWebhookTimeout - admissionDeadlineMargin},
+		{name: "webhook timeout shorter than margin", timeout: 30 * time.Second, url: "/validate?timeout=400ms", expected: 200 * time.Millisecond},
+		{name: "no config timeout", timeout: 0, url: "/validate?timeout=2s", expected: 2*time.Second - admissionDeadlineMargin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{config: &config.WebhookConfig{Timeout: tt.timeout}}
+			req := httptest.NewRequest(http.MethodPost, tt.url, nil)
+			if got := server.admissionDeadline(req); got != tt.expected {
+				t.Errorf("admissionDeadline() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestServer_withAdmissionDeadline(t *testing.T) {
+	tests := []struct {
+		name          string
+		failurePolicy string
+		delay         time.Duration
+		expectAllowed bool
+		expectCode    int32
+		expectWarning bool
+	}{
+		{name: "completes within deadline", failurePolicy: "Fail", delay: 0, expectAllowed: true},
+		{name: "deadline exceeded with Fail policy", failurePolicy: "Fail", delay: 500 * time.Millisecond, expectAllowed: false, expectCode: http.StatusGatewayTimeout},
+		{name: "deadline exceeded with Ignore policy", failurePolicy: "Ignore", delay: 500 * time.Millisecond, expectAllowed: true, expectWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.WebhookConfig{Environment: "development", FailurePolicy: tt.failurePolicy}
+			server := newTestServer(newSlowTestClient(tt.delay), cfg)
+			request := createDeploymentAdmissionRequest("test-deployment", "default", 1)
+
+			start := time.Now()
+			response := server.withAdmissionDeadline(context.Background(), 50*time.Millisecond, request, server.allowOnDeadline(), server.validateAdmissionRequest)
+			if elapsed := time.Since(start); tt.delay > 0 && elapsed >= tt.delay {
+				t.Errorf("withAdmissionDeadline() waited %v for the slow handler", elapsed)
+			}
+
+			if response.UID != request.UID {
+				t.Errorf("Expected UID %s, got %s", request.UID, response.UID)
+			}
+			if response.Allowed != tt.expectAllowed {
+				t.Fatalf("withAdmissionDeadline() allowed = %v, expected %v (result: %+v)", response.Allowed, tt.expectAllowed, response.Result)
+			}
+			if !tt.expectAllowed {
+				if response.Result == nil || response.Result.Code != tt.expectCode {
+					t.Fatalf("Expected status code %d, got %+v", tt.expectCode, response.Result)
+				}
+				if response.Result.Reason != "Timeout" {
+					t.Errorf("Expected reason Timeout, got %s", response.Result.Reason)
+				}
+			}
+			if tt.expectWarning {
+				if len(response.Warnings) == 0 {
+					t.Error("Expected a warning explaining the request was allowed")
+				}
+				if _, ok := response.AuditAnnotations[AuditAnnotationDeadlineExceeded]; !ok {
+					t.Errorf("Expected audit annotation %s, got %v", AuditAnnotationDeadlineExceeded, response.AuditAnnotations)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_withAdmissionDeadline_Mutation(t *testing.T) {
+	cfg := &config.WebhookConfig{Environment: "development", FailurePolicy: "Ignore", MutationEnabled: true}
+	server := newTestServer(newSlowTestClient(500*time.Millisecond), cfg)
+	request := createDeploymentAdmissionRequest("test-deployment", "default", 1)
+
+	response := server.withAdmissionDeadline(context.Background(), 50*time.Millisecond, request, server.allowOnDeadline(), server.mutateAdmissionRequest)
+	if !response.Allowed || len(response.Patch) != 0 {
+		t.Fatalf("Expected the request to be allowed without a patch, got %+v", response)
+	}
+	if len(response.Warnings) == 0 {
+		t.Error("Expected a warning explaining the request was allowed")
+	}
+}
@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/decisionlog"
+	"k8s-deployment-hpa-validator/internal/metrics"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+// admissionOptions AdmissionRequest.Options（CreateOptions・UpdateOptions・PatchOptions）のうち決定ログに記録する項目
+type admissionOptions struct {
+	FieldManager string `json:"fieldManager,omitempty"`
+}
+
+// decisionLogFields 決定ログに記録するリソースと呼び出し元の情報
+func decisionLogFields(req *admissionv1.AdmissionRequest) map[string]interface{} {
+	return map[string]interface{}{
+		"resource_type": req.Kind.Kind,
+		"resource_name": req.Name,
+		"namespace":     req.Namespace,
+		"operation":     string(req.Operation),
+		"uid":           string(req.UID),
+		"user":          req.UserInfo.Username,
+		"groups":        req.UserInfo.Groups,
+		"dry_run":       req.DryRun != nil && *req.DryRun,
+		"field_manager": fieldManager(req),
+	}
+}
+
+// fieldManager AdmissionRequest.Optionsのフィールドマネージャー（DELETEや未指定の場合は空）
+func fieldManager(req *admissionv1.AdmissionRequest) string {
+	if len(req.Options.Raw) == 0 {
+		return ""
+	}
+	var options admissionOptions
+	if err := json.Unmarshal(req.Options.Raw, &options); err != nil {
+		return ""
+	}
+	return options.FieldManager
+}
+
+// newRequesterNamespaceLimiter 拒否のメトリクスのrequester_namespaceラベルの値を制限するLabelLimiterを作成（無効な場合はnil）
+func newRequesterNamespaceLimiter(cfg *config.WebhookConfig) *metrics.LabelLimiter {
+	if !cfg.MetricsRequesterNamespace {
+		return nil
+	}
+	return metrics.NewLabelLimiter(cfg.MetricsRequesterNamespaceLimit)
+}
+
+// recordDenial 拒否したリクエストのメトリクスを記録
+// 有効な場合は呼び出し元のサービスアカウントのnamespaceをラベルに付与する（サービスアカウント以外は空）
+func (s *Server) recordDenial(req *admissionv1.AdmissionRequest, errorType string) {
+	var requesterNamespace string
+	if s.requesterNamespaces != nil {
+		if namespace, _, ok := config.ParseServiceAccountUsername(req.UserInfo.Username); ok {
+			requesterNamespace = s.requesterNamespaces.Value(namespace)
+		}
+	}
+	metrics.RecordDenial(req.Kind.Kind, errorType, requesterNamespace)
+}
+
+// recordDecision 判定を決定ログに書き込み、CloudEventとして送信する（いずれも無効な場合は何もしない）
+// modeはエンドポイントの適用モードで、検証の対象外や処理期限の超過による許可はレスポンスの監査アノテーションから判定する
+func (s *Server) recordDecision(req *admissionv1.AdmissionRequest, response *admissionv1.AdmissionResponse, mode string, start time.Time) {
+	if s.decisions == nil && s.cloudEvents == nil {
+		return
+	}
+	entry := decisionEntry(req, response, mode, start, time.Now())
+	if s.decisions != nil {
+		s.decisions.Write(entry)
+	}
+	if s.cloudEvents != nil {
+		s.cloudEvents.Emit(entry)
+	}
+}
+
+// decisionEntry AdmissionRequestとレスポンスから決定ログの1件を作成
+func decisionEntry(req *admissionv1.AdmissionRequest, response *admissionv1.AdmissionResponse, mode string, start, now time.Time) decisionlog.Entry {
+	entry := decisionlog.Entry{
+		Timestamp:       now.UTC(),
+		UID:             string(req.UID),
+		User:            req.UserInfo.Username,
+		Groups:          req.UserInfo.Groups,
+		Kind:            req.Kind.Kind,
+		Name:            req.Name,
+		Namespace:       req.Namespace,
+		Operation:       string(req.Operation),
+		DryRun:          req.DryRun != nil && *req.DryRun,
+		Decision:        decisionlog.DecisionAllow,
+		EnforcementMode: mode,
+		LatencyMs:       float64(now.Sub(start).Microseconds()) / 1000,
+	}
+	if !response.Allowed {
+		entry.Decision = decisionlog.DecisionDeny
+	}
+
+	annotations := response.AuditAnnotations
+	var code string
+	switch {
+	case annotations[AuditAnnotationExemptedBy] != "":
+		entry.EnforcementMode = decisionlog.ModeExempt
+		code = annotations[AuditAnnotationExemptedRule]
+	case annotations[AuditAnnotationDeadlineExceeded] != "":
+		entry.EnforcementMode = decisionlog.ModeFailOpen
+		code = validator.CodeInternalDeadlineExceeded
+	case annotations[AuditAnnotationMutatedRule] != "":
+		code = annotations[AuditAnnotationMutatedRule]
+	default:
+		code = annotations[AuditAnnotationErrorCode]
+	}
+	if code != "" {
+		entry.Codes = []string{code}
+		if validator.IsRuleCode(code) {
+			entry.Rules = []string{code}
+		}
+	}
+	return entry
+}
@@ -0,0 +1,349 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s-deployment-hpa-validator/internal/cloudevents"
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/decisionlog"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+func TestDecisionLogFields(t *testing.T) {
+	dryRun := true
+	req := createDeploymentAdmissionRequest("web", "team-a", 1)
+	req.UserInfo = authenticationv1.UserInfo{
+		Username: "system:serviceaccount:ci:deployer",
+		Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:ci"},
+	}
+	req.DryRun = &dryRun
+	req.Options = runtime.RawExtension{Raw: []byte(`{"kind":"CreateOptions","apiVersion":"meta.k8s.io/v1","fieldManager":"argocd-controller"}`)}
+
+	expected := map[string]interface{}{
+		"resource_type": "Deployment",
+		"resource_name": "web",
+		"namespace":     "team-a",
+		"operation":     "CREATE",
+		"uid":           "test-uid",
+		"user":          "system:serviceaccount:ci:deployer",
+		"groups":        []string{"system:serviceaccounts", "system:serviceaccounts:ci"},
+		"dry_run":       true,
+		"field_manager": "argocd-controller",
+	}
+	if fields := decisionLogFields(req); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("decisionLogFields() = %v, expected %v", fields, expected)
+	}
+
+	// DELETEなどOptionsがない場合やdryRunが未指定の場合
+	req.DryRun = nil
+	req.Options = runtime.RawExtension{}
+	fields := decisionLogFields(req)
+	if fields["dry_run"] != false || fields["field_manager"] != "" {
+		t.Errorf("Unexpected dry_run/field_manager: %v, %v", fields["dry_run"], fields["field_manager"])
+	}
+}
+
+func TestServer_handleValidate_DenialMetrics(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-hpa", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment"},
+		},
+	})
+
+	newServer := func(cfg *config.WebhookConfig) *Server {
+		return newTestServer(fakeClient, cfg, func(s *Server) {
+			s.requesterNamespaces = newRequesterNamespaceLimiter(cfg)
+		})
+	}
+	deny := func(t *testing.T, server *Server, username string) {
+		t.Helper()
+		req := createDeploymentAdmissionRequest("test-deployment", "default", 1)
+		req.UserInfo = authenticationv1.UserInfo{Username: username}
+		body, err := json.Marshal(&admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request:  req,
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal admission review: %v", err)
+		}
+		r := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(string(body)))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.handleValidate(w, r)
+
+		var review admissionv1.AdmissionReview
+		if err := json.Unmarshal(w.Body.Bytes(), &review); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if review.Response.Allowed {
+			t.Fatal("Expected the request to be denied")
+		}
+	}
+	denials := func(requesterNamespace string) float64 {
+		return testutil.ToFloat64(metrics.WebhookDenialsTotal.WithLabelValues("Deployment", "validation_failed", requesterNamespace))
+	}
+
+	t.Run("label disabled", func(t *testing.T) {
+		server := newServer(&config.WebhookConfig{Environment: "development"})
+		before, beforeNS := denials(""), denials("ci")
+		deny(t, server, "system:serviceaccount:ci:deployer")
+		if denials("") != before+1 || denials("ci") != beforeNS {
+			t.Errorf("Expected the denial to be recorded without requester namespace")
+		}
+	})
+
+	t.Run("label enabled with limit", func(t *testing.T) {
+		server := newServer(&config.WebhookConfig{
+			Environment:                    "development",
+			MetricsRequesterNamespace:      true,
+			MetricsRequesterNamespaceLimit: 1,
+		})
+		before := map[string]float64{"": denials(""), "ci": denials("ci"), metrics.LabelValueOther: denials(metrics.LabelValueOther)}
+
+		deny(t, server, "system:serviceaccount:ci:deployer")
+		deny(t, server, "system:serviceaccount:ci:deployer")
+		deny(t, server, "system:serviceaccount:team-b:deployer")
+		deny(t, server, "alice@example.com")
+
+		expected := map[string]float64{"": 1, "ci": 2, metrics.LabelValueOther: 1}
+		for label, delta := range expected {
+			if got := denials(label) - before[label]; got != delta {
+				t.Errorf("requester_namespace=%q increased by %v, expected %v", label, got, delta)
+			}
+		}
+	})
+}
+
+func TestDecisionEntry(t *testing.T) {
+	start := time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC)
+	now := start.Add(1500 * time.Microsecond)
+	req := createDeploymentAdmissionRequest("web", "team-a", 1)
+	req.UserInfo = authenticationv1.UserInfo{Username: "alice@example.com", Groups: []string{"developers"}}
+
+	tests := []struct {
+		name             string
+		response         *admissionv1.AdmissionResponse
+		mode             string
+		expectedDecision string
+		expectedMode     string
+		expectedRules    []string
+		expectedCodes    []string
+	}{
+		{
+			name:             "allowed",
+			response:         &admissionv1.AdmissionResponse{Allowed: true},
+			mode:             decisionlog.ModeEnforce,
+			expectedDecision: decisionlog.DecisionAllow,
+			expectedMode:     decisionlog.ModeEnforce,
+		},
+		{
+			name: "denied by rule",
+			response: &admissionv1.AdmissionResponse{Allowed: false, AuditAnnotations: map[string]string{
+				AuditAnnotationErrorCode: validator.CodeDeploymentHPAConflict,
+			}},
+			mode:             decisionlog.ModeEnforce,
+			expectedDecision: decisionlog.DecisionDeny,
+			expectedMode:     decisionlog.ModeEnforce,
+			expectedRules:    []string{validator.CodeDeploymentHPAConflict},
+			expectedCodes:    []string{validator.CodeDeploymentHPAConflict},
+		},
+		{
+			name: "denied by internal error",
+			response: &admissionv1.AdmissionResponse{Allowed: false, AuditAnnotations: map[string]string{
+				AuditAnnotationErrorCode: validator.CodeAPITimeout,
+			}},
+			mode:             decisionlog.ModeEnforce,
+			expectedDecision: decisionlog.DecisionDeny,
+			expectedMode:     decisionlog.ModeEnforce,
+			expectedCodes:    []string{validator.CodeAPITimeout},
+		},
+		{
+			name: "exempted",
+			response: &admissionv1.AdmissionResponse{Allowed: true, AuditAnnotations: map[string]string{
+				AuditAnnotationExemptedBy:   "user:alice@example.com",
+				AuditAnnotationExemptedRule: validator.CodeHPASingleReplica,
+			}},
+			mode:             decisionlog.ModeEnforce,
+			expectedDecision: decisionlog.DecisionAllow,
+			expectedMode:     decisionlog.ModeExempt,
+			expectedRules:    []string{validator.CodeHPASingleReplica},
+			expectedCodes:    []string{validator.CodeHPASingleReplica},
+		},
+		{
+			name: "deadline exceeded",
+			response: &admissionv1.AdmissionResponse{Allowed: true, AuditAnnotations: map[string]string{
+				AuditAnnotationDeadlineExceeded: "2s",
+			}},
+			mode:             decisionlog.ModeMutate,
+			expectedDecision: decisionlog.DecisionAllow,
+			expectedMode:     decisionlog.ModeFailOpen,
+			expectedCodes:    []string{validator.CodeInternalDeadlineExceeded},
+		},
+		{
+			name: "mutated",
+			response: &admissionv1.AdmissionResponse{Allowed: true, AuditAnnotations: map[string]string{
+				AuditAnnotationMutatedRule: validator.CodeDeploymentHPAConflict,
+			}},
+			mode:             decisionlog.ModeMutate,
+			expectedDecision: decisionlog.DecisionAllow,
+			expectedMode:     decisionlog.ModeMutate,
+			expectedRules:    []string{validator.CodeDeploymentHPAConflict},
+			expectedCodes:    []string{validator.CodeDeploymentHPAConflict},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := decisionEntry(req, tt.response, tt.mode, start, now)
+			if entry.Decision != tt.expectedDecision || entry.EnforcementMode != tt.expectedMode {
+				t.Errorf("Decision/EnforcementMode = %s/%s, expected %s/%s", entry.Decision, entry.EnforcementMode, tt.expectedDecision, tt.expectedMode)
+			}
+			if !reflect.DeepEqual(entry.Rules, tt.expectedRules) || !reflect.DeepEqual(entry.Codes, tt.expectedCodes) {
+				t.Errorf("Rules/Codes = %v/%v, expected %v/%v", entry.Rules, entry.Codes, tt.expectedRules, tt.expectedCodes)
+			}
+			if entry.UID != "test-uid" || entry.User != "alice@example.com" || entry.Kind != "Deployment" || entry.Name != "web" ||
+				entry.Namespace != "team-a" || entry.Operation != "CREATE" || entry.LatencyMs != 1.5 || !entry.Timestamp.Equal(now) {
+				t.Errorf("Unexpected entry: %+v", entry)
+			}
+		})
+	}
+}
+
+func TestServer_handleValidate_DecisionLog(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-hpa", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment"},
+		},
+	})
+	path := filepath.Join(t.TempDir(), "decisions.jsonl")
+	cfg := &config.WebhookConfig{
+		Environment:           "development",
+		ExemptUsers:           []string{"admin@example.com"},
+		DecisionLogPath:       path,
+		DecisionLogMaxSizeMB:  1,
+		DecisionLogMaxAge:     time.Hour,
+		DecisionLogBufferSize: 16,
+	}
+	logger := logging.NewLogger("test-webhook")
+	decisions, err := decisionlog.NewWriter(cfg, logger)
+	if err != nil {
+		t.Fatalf("Failed to create decision log: %v", err)
+	}
+	server := newTestServer(fakeClient, cfg, func(s *Server) {
+		s.decisions = decisions
+	})
+
+	for _, username := range []string{"alice@example.com", "admin@example.com"} {
+		req := createDeploymentAdmissionRequest("test-deployment", "default", 1)
+		req.UserInfo = authenticationv1.UserInfo{Username: username}
+		body, err := json.Marshal(&admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request:  req,
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal admission review: %v", err)
+		}
+		r := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(string(body)))
+		server.handleValidate(httptest.NewRecorder(), r)
+	}
+	if err := decisions.Close(); err != nil {
+		t.Fatalf("Failed to close decision log: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read decision log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 decisions, got %q", content)
+	}
+	expected := []struct{ user, decision, mode string }{
+		{"alice@example.com", decisionlog.DecisionDeny, decisionlog.ModeEnforce},
+		{"admin@example.com", decisionlog.DecisionAllow, decisionlog.ModeExempt},
+	}
+	for i, line := range lines {
+		var entry decisionlog.Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to unmarshal %q: %v", line, err)
+		}
+		if entry.User != expected[i].user || entry.Decision != expected[i].decision || entry.EnforcementMode != expected[i].mode ||
+			!reflect.DeepEqual(entry.Rules, []string{validator.CodeDeploymentHPAConflict}) {
+			t.Errorf("decision[%d] = %+v, expected %+v", i, entry, expected[i])
+		}
+	}
+}
+
+func TestServer_handleValidate_CloudEvents(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-hpa", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment"},
+		},
+	})
+	var mu sync.Mutex
+	var types []string
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		types = append(types, r.Header.Get("ce-type"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	cfg := &config.WebhookConfig{
+		Environment:           "development",
+		ExemptUsers:           []string{"admin@example.com"},
+		CloudEventsSinkURL:    sink.URL,
+		CloudEventsMode:       config.CloudEventsModeBinary,
+		CloudEventsBufferSize: 16,
+	}
+	logger := logging.NewLogger("test-webhook")
+	emitter := cloudevents.NewEmitter(cfg, logger)
+	// 決定ログが無効でも判定をCloudEventとして送信する
+	server := newTestServer(fakeClient, cfg, func(s *Server) {
+		s.cloudEvents = emitter
+	})
+
+	for _, username := range []string{"alice@example.com", "admin@example.com"} {
+		req := createDeploymentAdmissionRequest("test-deployment", "default", 1)
+		req.UserInfo = authenticationv1.UserInfo{Username: username}
+		body, err := json.Marshal(&admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request:  req,
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal admission review: %v", err)
+		}
+		r := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(string(body)))
+		server.handleValidate(httptest.NewRecorder(), r)
+	}
+	emitter.Close()
+
+	// 検証の対象外として許可した判定は、CloudEventsAllDecisionsが無効なため送信しない
+	expected := []string{cloudevents.TypePrefix + ".denied." + validator.CodeDeploymentHPAConflict}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("Received event types %v, expected %v", types, expected)
+	}
+}
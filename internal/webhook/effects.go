@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+)
+
+// admissionEffectsKey コンテキストにadmissionEffectsを格納するキー
+type admissionEffectsKey struct{}
+
+// admissionEffects 処理中のAdmissionRequestについて、レスポンスが確定するまで保留するEventの記録など
+// 処理期限を超過して破棄したレスポンスのEventを記録しないよう、採用したレスポンスの分のみ実行する
+type admissionEffects struct {
+	mu      sync.Mutex
+	pending []func()
+	// settled レスポンスが確定した（以後に追加された処理は実行しない）
+	settled bool
+}
+
+// withAdmissionEffects レスポンスの確定まで処理を保留するコンテキストを作成
+func withAdmissionEffects(ctx context.Context) (context.Context, *admissionEffects) {
+	effects := &admissionEffects{}
+	return context.WithValue(ctx, admissionEffectsKey{}, effects), effects
+}
+
+// afterResponse レスポンスの確定後に処理を実行する
+// 保留するコンテキストでない場合（処理期限がない場合や期限超過時のレスポンスの作成）はすぐに実行する
+func afterResponse(ctx context.Context, effect func()) {
+	effects, ok := ctx.Value(admissionEffectsKey{}).(*admissionEffects)
+	if !ok {
+		effect()
+		return
+	}
+
+	effects.mu.Lock()
+	defer effects.mu.Unlock()
+	if !effects.settled {
+		effects.pending = append(effects.pending, effect)
+	}
+}
+
+// commit レスポンスを採用し、保留した処理を実行する
+func (e *admissionEffects) commit() {
+	e.mu.Lock()
+	pending := e.pending
+	e.pending, e.settled = nil, true
+	e.mu.Unlock()
+
+	for _, effect := range pending {
+		effect()
+	}
+}
+
+// discard レスポンスを破棄し、保留した処理と以後に追加される処理を実行しない
+func (e *admissionEffects) discard() {
+	e.mu.Lock()
+	e.pending, e.settled = nil, true
+	e.mu.Unlock()
+}
@@ -2,12 +2,14 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 
 	"k8s-deployment-hpa-validator/internal/config"
 	"k8s-deployment-hpa-validator/internal/logging"
@@ -15,18 +17,89 @@ import (
 	"k8s-deployment-hpa-validator/internal/validator"
 )
 
+// AuditAnnotationSuggestedFixes 修正案を記録するAuditAnnotationのキー
+const AuditAnnotationSuggestedFixes = "suggested-fixes"
+
+// AuditAnnotationErrorCode 拒否の理由となったエラーコードを記録するAuditAnnotationのキー
+const AuditAnnotationErrorCode = "error-code"
+
+// CauseTypeSuggestedFix 修正案を表すStatusCauseの種類
+const CauseTypeSuggestedFix metav1.CauseType = "SuggestedFix"
+
+// LanguageAnnotation namespaceごとにメッセージの言語を指定するアノテーション
+const LanguageAnnotation = "k8s-deployment-hpa-validator.io/language"
+
 // ErrorHandler 本番環境用エラーハンドラー
 type ErrorHandler struct {
-	config *config.WebhookConfig
-	logger *logging.Logger
+	config    *config.WebhookConfig
+	logger    *logging.Logger
+	client    kubernetes.Interface
+	templates config.MessageTemplateSet
+	// namespaces namespaceのアノテーションのキャッシュ（clientがない場合はnil）
+	namespaces *namespaceCache
 }
 
 // NewErrorHandler 新しいErrorHandlerを作成
 func NewErrorHandler(config *config.WebhookConfig, logger *logging.Logger) *ErrorHandler {
-	return &ErrorHandler{
-		config: config,
-		logger: logger,
+	return NewErrorHandlerWithClient(config, logger, nil)
+}
+
+// NewErrorHandlerWithClient namespaceのアノテーションを参照するErrorHandlerを作成
+func NewErrorHandlerWithClient(cfg *config.WebhookConfig, logger *logging.Logger, client kubernetes.Interface) *ErrorHandler {
+	// テンプレートは設定の読み込み時に検証済み
+	templates, err := config.ParseMessageTemplates(cfg.MessageTemplates)
+	if err != nil {
+		logger.Error("メッセージテンプレートの解析に失敗しました。既定のメッセージを使用します", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	eh := &ErrorHandler{
+		config:    cfg,
+		logger:    logger,
+		client:    client,
+		templates: templates,
+	}
+	if client != nil {
+		eh.namespaces = newNamespaceCache(client, namespaceCacheTTL)
+	}
+	return eh
+}
+
+// ResolveLanguage メッセージの言語を決定（namespaceのアノテーションが設定の既定値より優先）
+func (eh *ErrorHandler) ResolveLanguage(ctx context.Context, namespace string) validator.Language {
+	return eh.languageFor(eh.namespaceAnnotations(ctx, namespace))
+}
+
+// languageFor namespaceのアノテーションからメッセージの言語を決定
+func (eh *ErrorHandler) languageFor(annotations map[string]string) validator.Language {
+	if value, ok := annotations[LanguageAnnotation]; ok {
+		if parsed, ok := validator.ParseLanguage(value); ok {
+			return parsed
+		}
 	}
+	if parsed, ok := validator.ParseLanguage(eh.config.Language); ok {
+		return parsed
+	}
+	return validator.DefaultLanguage
+}
+
+// namespaceAnnotations namespaceのアノテーションを取得（取得できない場合はnil）
+// 結果はnamespaceCacheTTLの間キャッシュする
+func (eh *ErrorHandler) namespaceAnnotations(ctx context.Context, namespace string) map[string]string {
+	if eh.namespaces == nil || namespace == "" {
+		return nil
+	}
+
+	annotations, err := eh.namespaces.annotations(ctx, namespace)
+	if err != nil {
+		eh.logger.WithRequestID(logging.RequestIDFromContext(ctx)).Debug("namespaceの取得に失敗しました", map[string]interface{}{
+			"namespace": namespace,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+	return annotations
 }
 
 // HandleError エラーを処理してAdmissionResponseを作成
@@ -78,7 +151,8 @@ func (eh *ErrorHandler) handleWebhookError(ctx context.Context, webhookErr *vali
 	}
 
 	// レスポンス作成
-	return eh.createErrorResponse(webhookErr, req)
+	annotations := eh.namespaceAnnotations(ctx, webhookErr.Namespace)
+	return eh.createErrorResponse(eh.localize(webhookErr, annotations), req, annotations, logger)
 }
 
 // handleGenericError 通常のerrorを処理
@@ -119,6 +193,27 @@ func (eh *ErrorHandler) logWebhookError(webhookErr *validator.WebhookError, logg
 		fields["suggestions"] = webhookErr.Suggestions
 	}
 
+	// 違反したフィールドと関連リソースがある場合は追加
+	if len(webhookErr.Causes) > 0 {
+		causeFields := make([]string, 0, len(webhookErr.Causes))
+		for _, cause := range webhookErr.Causes {
+			causeFields = append(causeFields, cause.Field)
+		}
+		fields["fields"] = causeFields
+	}
+	if webhookErr.RelatedObject != nil {
+		fields["related_object"] = webhookErr.RelatedObject.String()
+	}
+
+	// 修正案は全環境で記録する
+	if len(webhookErr.Fixes) > 0 {
+		commands := make([]string, 0, len(webhookErr.Fixes))
+		for _, fix := range webhookErr.Fixes {
+			commands = append(commands, fix.Command)
+		}
+		fields["suggested_fixes"] = commands
+	}
+
 	// エラータイプに応じてログレベルを調整
 	switch webhookErr.Type {
 	case validator.ErrorTypeValidation:
@@ -166,29 +261,56 @@ func (eh *ErrorHandler) handleRetryableError(ctx context.Context, webhookErr *va
 		"error_code": webhookErr.Code,
 	})
 
-	return eh.createErrorResponse(webhookErr, req)
+	annotations := eh.namespaceAnnotations(ctx, webhookErr.Namespace)
+	return eh.createErrorResponse(eh.localize(webhookErr, annotations), req, annotations, logger)
+}
+
+// localize レスポンス用にWebhookErrorのメッセージを対象namespaceの言語で描画
+// メッセージは既定の言語で作成されているため、それ以外の言語の場合のみ再描画する
+func (eh *ErrorHandler) localize(webhookErr *validator.WebhookError, annotations map[string]string) *validator.WebhookError {
+	lang := eh.languageFor(annotations)
+	if lang == validator.DefaultLanguage {
+		return webhookErr
+	}
+	return webhookErr.Localize(lang)
 }
 
 // createErrorResponse エラーレスポンスを作成
-func (eh *ErrorHandler) createErrorResponse(webhookErr *validator.WebhookError, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+func (eh *ErrorHandler) createErrorResponse(webhookErr *validator.WebhookError, req *admissionv1.AdmissionRequest, annotations map[string]string, logger *logging.RequestLogger) *admissionv1.AdmissionResponse {
 	var uid types.UID
 	if req != nil {
 		uid = req.UID
 	}
 
 	// 本番環境では制限されたメッセージを使用
+	lang := webhookErr.GetLanguage()
 	message := webhookErr.Message
 	if eh.config.IsProductionEnvironment() {
-		message = webhookErr.GetProductionMessage()
+		message = webhookErr.GetProductionMessageIn(lang)
 	}
 
+	// 設定されたカスタムテンプレートで既定のメッセージを置き換える
+	message = eh.renderCustomMessage(webhookErr, message, annotations, logger)
+
 	response := &admissionv1.AdmissionResponse{
 		UID:     uid,
 		Allowed: false,
 		Result: &metav1.Status{
 			Code:    int32(webhookErr.GetHTTPStatusCode()),
 			Message: message,
+			Reason:  webhookErr.GetStatusReason(),
+			Details: eh.createStatusDetails(webhookErr, lang),
 		},
+		AuditAnnotations: map[string]string{
+			AuditAnnotationErrorCode: webhookErr.Code,
+		},
+	}
+
+	// 修正案はAPIサーバーの監査ログに記録されるよう全環境で付与
+	if len(webhookErr.Fixes) > 0 {
+		if fixesJSON, err := json.Marshal(webhookErr.Fixes); err == nil {
+			response.AuditAnnotations[AuditAnnotationSuggestedFixes] = string(fixesJSON)
+		}
 	}
 
 	// 開発環境では追加情報を含める
@@ -196,20 +318,103 @@ func (eh *ErrorHandler) createErrorResponse(webhookErr *validator.WebhookError,
 		// 提案を含めたメッセージを作成
 		detailedMessage := message
 		if webhookErr.Details != "" {
-			detailedMessage += fmt.Sprintf("\n詳細: %s", webhookErr.Details)
+			detailedMessage += fmt.Sprintf("\n%s: %s", eh.label(lang, validator.LabelDetails), webhookErr.Details)
 		}
 		if len(webhookErr.Suggestions) > 0 {
-			detailedMessage += "\n提案:"
+			detailedMessage += fmt.Sprintf("\n%s:", eh.label(lang, validator.LabelSuggestions))
 			for i, suggestion := range webhookErr.Suggestions {
 				detailedMessage += fmt.Sprintf("\n  %d. %s", i+1, suggestion)
 			}
 		}
+		if len(webhookErr.Fixes) > 0 {
+			detailedMessage += fmt.Sprintf("\n%s:", eh.label(lang, validator.LabelFixes))
+			for _, fix := range webhookErr.Fixes {
+				detailedMessage += fmt.Sprintf("\n  %s", fix.Command)
+			}
+		}
 		response.Result.Message = detailedMessage
 	}
 
 	return response
 }
 
+// createStatusDetails 違反したフィールドと修正案をStatusDetailsに変換
+func (eh *ErrorHandler) createStatusDetails(webhookErr *validator.WebhookError, lang validator.Language) *metav1.StatusDetails {
+	// 修正案は本番環境以外（開発・ステージング）でのみレスポンスに含める
+	includeFixes := !eh.config.IsProductionEnvironment() && len(webhookErr.Fixes) > 0
+	if len(webhookErr.Causes) == 0 && !includeFixes {
+		return nil
+	}
+
+	details := &metav1.StatusDetails{
+		Name: webhookErr.ResourceName,
+		Kind: webhookErr.ResourceType,
+	}
+	for _, cause := range webhookErr.Causes {
+		message := cause.Message
+		if webhookErr.RelatedObject != nil {
+			message = fmt.Sprintf("%s (%s: %s)", message, eh.label(lang, validator.LabelRelatedObject), webhookErr.RelatedObject)
+		}
+		details.Causes = append(details.Causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: message,
+			Field:   cause.Field,
+		})
+	}
+	if includeFixes {
+		for _, fix := range webhookErr.Fixes {
+			details.Causes = append(details.Causes, metav1.StatusCause{
+				Type:    CauseTypeSuggestedFix,
+				Message: fix.Command,
+				Field:   fix.Target.String(),
+			})
+		}
+	}
+
+	return details
+}
+
+// renderCustomMessage エラーコードに対応するカスタムテンプレートでメッセージを描画
+// テンプレートがない場合や描画に失敗した場合は既定のメッセージを返す
+func (eh *ErrorHandler) renderCustomMessage(webhookErr *validator.WebhookError, message string, annotations map[string]string, logger *logging.RequestLogger) string {
+	if len(eh.templates) == 0 {
+		return message
+	}
+
+	data := webhookErr.MessageData()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	rendered, ok, err := eh.templates.Render(config.MessageTemplateData{
+		Code:        webhookErr.Code,
+		Message:     message,
+		Kind:        data.ResourceKind,
+		Name:        data.ResourceName,
+		Namespace:   data.Namespace,
+		RelatedKind: data.RelatedKind,
+		RelatedName: data.RelatedName,
+		Threshold:   data.Threshold,
+		Current:     data.Current,
+		Annotations: annotations,
+	})
+	if err != nil {
+		logger.Warn("カスタムメッセージの描画に失敗しました。既定のメッセージを使用します", map[string]interface{}{
+			"error_code": webhookErr.Code,
+			"error":      err.Error(),
+		})
+		return message
+	}
+	if !ok {
+		return message
+	}
+	return rendered
+}
+
+// label レスポンスの見出しを指定された言語で取得
+func (eh *ErrorHandler) label(lang validator.Language, key string) string {
+	return validator.LocalizeFragment(lang, key, validator.MessageData{})
+}
+
 // RetryConfig 再試行設定
 type RetryConfig struct {
 	MaxRetries  int
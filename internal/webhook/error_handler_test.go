@@ -8,8 +8,10 @@ import (
 	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"k8s-deployment-hpa-validator/internal/config"
 	"k8s-deployment-hpa-validator/internal/logging"
@@ -190,6 +192,205 @@ func TestErrorHandler_ProductionMessageRestriction(t *testing.T) {
 	}
 }
 
+func TestErrorHandler_StatusCauses(t *testing.T) {
+	cfg := &config.WebhookConfig{
+		Environment: "production",
+		LogLevel:    "info",
+		LogFormat:   "json",
+	}
+	handler := NewErrorHandler(cfg, logging.NewLogger("test"))
+
+	req := &admissionv1.AdmissionRequest{
+		UID:       types.UID("test-uid"),
+		Kind:      metav1.GroupVersionKind{Kind: "HorizontalPodAutoscaler"},
+		Name:      "test-hpa",
+		Namespace: "default",
+	}
+	err := validator.NewHPASingleReplicaError().
+		WithRelatedObject("Deployment", "test-deployment", "default").
+		WithCause("spec.scaleTargetRef.name", "対象のDeploymentのreplicasが1です").
+		WithCause("spec.minReplicas", "minReplicasを2以上に設定してください")
+
+	response := handler.HandleError(context.Background(), err, req)
+
+	if response.Result.Reason != metav1.StatusReasonInvalid {
+		t.Errorf("Reason = %v, want %v", response.Result.Reason, metav1.StatusReasonInvalid)
+	}
+	details := response.Result.Details
+	if details == nil {
+		t.Fatal("Details should be set when the error has causes")
+	}
+	if details.Kind != "HorizontalPodAutoscaler" || details.Name != "test-hpa" {
+		t.Errorf("Details = %+v, want HorizontalPodAutoscaler/test-hpa", details)
+	}
+	if len(details.Causes) != 2 {
+		t.Fatalf("Causes = %+v, want 2 causes", details.Causes)
+	}
+	for i, field := range []string{"spec.scaleTargetRef.name", "spec.minReplicas"} {
+		cause := details.Causes[i]
+		if cause.Field != field || cause.Type != metav1.CauseTypeFieldValueInvalid {
+			t.Errorf("Causes[%d] = %+v, want field %s", i, cause, field)
+		}
+		if !strings.Contains(cause.Message, "Deployment/test-deployment") {
+			t.Errorf("Causes[%d] message should name the related object: %s", i, cause.Message)
+		}
+	}
+}
+
+func TestErrorHandler_SuggestedFixes(t *testing.T) {
+	req := &admissionv1.AdmissionRequest{
+		UID:       types.UID("test-uid"),
+		Kind:      metav1.GroupVersionKind{Kind: "Deployment"},
+		Name:      "test-deployment",
+		Namespace: "default",
+	}
+
+	tests := []struct {
+		environment    string
+		wantFixDetails bool
+	}{
+		{environment: "development", wantFixDetails: true},
+		{environment: "staging", wantFixDetails: true},
+		{environment: "production", wantFixDetails: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.environment, func(t *testing.T) {
+			cfg := &config.WebhookConfig{Environment: tt.environment}
+			handler := NewErrorHandler(cfg, logging.NewLogger("test"))
+
+			err := validator.NewDeploymentHPAConflictError().WithFix(
+				validator.NewSuggestedFix("Deployment", "test-deployment", "default", map[string]interface{}{
+					"spec": map[string]interface{}{"replicas": 2},
+				}),
+			)
+			response := handler.HandleError(context.Background(), err, req)
+
+			// 監査ログ用のAuditAnnotationsは全環境で付与される
+			annotation, ok := response.AuditAnnotations[AuditAnnotationSuggestedFixes]
+			if !ok || !strings.Contains(annotation, `"merge_patch":"{\"spec\":{\"replicas\":2}}"`) {
+				t.Errorf("Unexpected audit annotation: %q", annotation)
+			}
+
+			hasFixDetails := false
+			if response.Result.Details != nil {
+				for _, cause := range response.Result.Details.Causes {
+					if cause.Type == CauseTypeSuggestedFix {
+						hasFixDetails = true
+					}
+				}
+			}
+			if hasFixDetails != tt.wantFixDetails {
+				t.Errorf("Suggested fix in details = %v, want %v", hasFixDetails, tt.wantFixDetails)
+			}
+		})
+	}
+}
+
+func TestErrorHandler_Language(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-en",
+			Annotations: map[string]string{LanguageAnnotation: "en"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-ja",
+			Annotations: map[string]string{LanguageAnnotation: "ja"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "plain"}},
+	)
+
+	tests := []struct {
+		name        string
+		language    string
+		namespace   string
+		wantMessage string
+		wantLabel   string
+	}{
+		{name: "default language", namespace: "plain", wantMessage: "1 replicaのDeployment", wantLabel: "修正案:"},
+		{name: "configured english", language: "en", namespace: "plain", wantMessage: "An HPA targets this Deployment", wantLabel: "Suggested fixes:"},
+		{name: "namespace annotation overrides config", language: "en", namespace: "team-ja", wantMessage: "1 replicaのDeployment", wantLabel: "修正案:"},
+		{name: "namespace annotation english", namespace: "team-en", wantMessage: "An HPA targets this Deployment", wantLabel: "Suggested fixes:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.WebhookConfig{Environment: "development", Language: tt.language}
+			handler := NewErrorHandlerWithClient(cfg, logging.NewLogger("test"), fakeClient)
+
+			req := &admissionv1.AdmissionRequest{
+				UID:       types.UID("test-uid"),
+				Kind:      metav1.GroupVersionKind{Kind: "Deployment"},
+				Name:      "test-deployment",
+				Namespace: tt.namespace,
+			}
+			err := validator.NewDeploymentHPAConflictError().
+				WithRelatedObject("HorizontalPodAutoscaler", "test-hpa", tt.namespace).
+				WithCauseKey("spec.replicas", validator.CauseDeploymentReplicas).
+				WithFix(validator.NewSuggestedFix("Deployment", "test-deployment", tt.namespace, map[string]interface{}{
+					"spec": map[string]interface{}{"replicas": 2},
+				}))
+
+			response := handler.HandleError(context.Background(), err, req)
+
+			if !strings.HasPrefix(response.Result.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want prefix %q", response.Result.Message, tt.wantMessage)
+			}
+			if !strings.Contains(response.Result.Message, tt.wantLabel) {
+				t.Errorf("Message = %q, want label %q", response.Result.Message, tt.wantLabel)
+			}
+			if response.Result.Details == nil || len(response.Result.Details.Causes) == 0 {
+				t.Fatal("Expected field causes in details")
+			}
+			if tt.wantMessage == "An HPA targets this Deployment" &&
+				!strings.Contains(response.Result.Details.Causes[0].Message, "related object") {
+				t.Errorf("Cause should be localized: %q", response.Result.Details.Causes[0].Message)
+			}
+		})
+	}
+}
+
+func TestErrorHandler_CustomMessageTemplates(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "team-a",
+		Annotations: map[string]string{"example.com/owner": "team-a@example.com"},
+	}})
+
+	cfg := &config.WebhookConfig{
+		Environment: "production",
+		MessageTemplates: map[string]string{
+			validator.CodeDeploymentHPAConflict: `{{.Message}} {{.Kind}}/{{.Name}}は{{.RelatedKind}}/{{.RelatedName}}の対象です（下限: {{.Threshold}}）。担当: {{index .Annotations "example.com/owner"}}`,
+			config.MessageTemplateAllCodes:      "{{.Message}} 手順書: https://runbooks.example.com/{{.Code}}",
+		},
+	}
+	handler := NewErrorHandlerWithClient(cfg, logging.NewLogger("test"), fakeClient)
+
+	req := &admissionv1.AdmissionRequest{
+		UID:       types.UID("test-uid"),
+		Kind:      metav1.GroupVersionKind{Kind: "Deployment"},
+		Name:      "web",
+		Namespace: "team-a",
+	}
+
+	conflict := validator.NewDeploymentHPAConflictError().
+		WithRelatedObject("HorizontalPodAutoscaler", "web-hpa", "team-a").
+		WithReplicaCounts(3, 1)
+	response := handler.HandleError(context.Background(), conflict, req)
+
+	want := conflict.Message + " Deployment/webはHorizontalPodAutoscaler/web-hpaの対象です（下限: 3）。担当: team-a@example.com"
+	if response.Result.Message != want {
+		t.Errorf("Message = %q, want %q", response.Result.Message, want)
+	}
+
+	// 個別のテンプレートがないエラーコードは"*"のテンプレートを使用（本番環境では制限されたメッセージが埋め込まれる）
+	internal := validator.NewInternalError("test", fmt.Errorf("boom"))
+	response = handler.HandleError(context.Background(), internal, req)
+	if !strings.HasPrefix(response.Result.Message, internal.GetProductionMessage()) ||
+		!strings.HasSuffix(response.Result.Message, "https://runbooks.example.com/"+validator.CodeInternalUnknown) {
+		t.Errorf("Unexpected message: %q", response.Result.Message)
+	}
+}
+
 func TestErrorHandler_ShouldRetry(t *testing.T) {
 	tests := []struct {
 		name        string
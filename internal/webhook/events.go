@@ -0,0 +1,155 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+const (
+	// EventComponent Eventの送信元として記録するコンポーネント名
+	EventComponent = "k8s-deployment-hpa-validator"
+
+	// EventReasonDenied 違反によりリクエストを拒否したことを示すEventのReason
+	EventReasonDenied = "AdmissionDenied"
+	// EventReasonExempted 検証の対象外の呼び出し元のため違反を許可したことを示すEventのReason
+	EventReasonExempted = "ViolationExempted"
+	// EventReasonMutated 違反を自動修正して許可したことを示すEventのReason
+	EventReasonMutated = "ViolationMutated"
+	// EventReasonDeadlineExceeded 処理期限の超過により検証せずに許可したことを示すEventのReason
+	EventReasonDeadlineExceeded = "DeadlineExceeded"
+
+	// EventAnnotationErrorCode Eventにエラーコードを記録するアノテーションのキー
+	EventAnnotationErrorCode = "k8s-deployment-hpa-validator/error-code"
+	// EventAnnotationRequestID EventにリクエストID（AdmissionRequestのUID）を記録するアノテーションのキー
+	EventAnnotationRequestID = "k8s-deployment-hpa-validator/request-id"
+
+	// eventBurstSize 対象のリソースごとに連続して送信できるEventの数
+	eventBurstSize = 10
+	// eventQPS バーストを使い切った後に対象のリソースごとに送信できるEventの頻度（5分に1件）
+	eventQPS = 1.0 / 300
+)
+
+// eventAPIVersions 関係するリソースの種類ごとのAPIバージョン
+var eventAPIVersions = map[string]string{
+	"Deployment":              "apps/v1",
+	"HorizontalPodAutoscaler": "autoscaling/v2",
+}
+
+// eventRecorder 拒否や警告付きの許可をKubernetesのEventとして記録する
+// 同じリソースへのEventはclient-goのEventCorrelatorにより集約され、リソースごとに送信頻度を制限する
+type eventRecorder struct {
+	broadcaster record.EventBroadcaster
+	recorder    record.EventRecorder
+}
+
+// newEventRecorder Kubernetes APIにEventを送信するeventRecorderを作成
+func newEventRecorder(client kubernetes.Interface) *eventRecorder {
+	return newEventRecorderWithOptions(client, record.CorrelatorOptions{
+		BurstSize: eventBurstSize,
+		QPS:       eventQPS,
+	})
+}
+
+// newEventRecorderWithOptions 集約と送信頻度の設定を指定してeventRecorderを作成
+func newEventRecorderWithOptions(client kubernetes.Interface, options record.CorrelatorOptions) *eventRecorder {
+	broadcaster := record.NewBroadcasterWithCorrelatorOptions(options)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	return &eventRecorder{
+		broadcaster: broadcaster,
+		recorder:    broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: EventComponent}),
+	}
+}
+
+// Shutdown 送信待ちのEventの処理を停止
+func (e *eventRecorder) Shutdown() {
+	e.broadcaster.Shutdown()
+}
+
+// record リクエストに関するWarning Eventを記録
+// 関係する既存のリソース（例: Deploymentの作成を拒否した原因のHPA）があればそのリソース、なければリクエストのリソースを対象にする
+func (e *eventRecorder) record(req *admissionv1.AdmissionRequest, webhookErr *validator.WebhookError, reason, message string) {
+	annotations := map[string]string{
+		EventAnnotationErrorCode: webhookErr.Code,
+	}
+	if req.UID != "" {
+		annotations[EventAnnotationRequestID] = string(req.UID)
+	}
+
+	e.recorder.AnnotatedEventf(eventTarget(req, webhookErr), annotations, corev1.EventTypeWarning, reason,
+		"%s %s %s/%s: [%s] %s", req.Operation, req.Kind.Kind, req.Namespace, requestName(req), webhookErr.Code, message)
+}
+
+// eventTarget Eventの対象のリソースを決定
+func eventTarget(req *admissionv1.AdmissionRequest, webhookErr *validator.WebhookError) *corev1.ObjectReference {
+	if related := webhookErr.RelatedObject; related != nil && related.UID != "" {
+		return &corev1.ObjectReference{
+			Kind:       related.Kind,
+			APIVersion: eventAPIVersions[related.Kind],
+			Name:       related.Name,
+			Namespace:  related.Namespace,
+			UID:        types.UID(related.UID),
+		}
+	}
+
+	target := &corev1.ObjectReference{
+		Kind:       req.Kind.Kind,
+		APIVersion: (&metav1.GroupVersion{Group: req.Kind.Group, Version: req.Kind.Version}).String(),
+		Name:       requestName(req),
+		Namespace:  req.Namespace,
+	}
+	// 作成時はまだUIDがないため、既存のリソース（更新・削除）の場合のみUIDを設定する
+	if req.Operation != admissionv1.Create {
+		target.UID = requestObjectMeta(req).UID
+	}
+	return target
+}
+
+// requestName リクエストのリソースの名前
+// generateNameで作成する場合は名前が決まっていないため、generateNameを使用する
+func requestName(req *admissionv1.AdmissionRequest) string {
+	if req.Name != "" {
+		return req.Name
+	}
+	return requestObjectMeta(req).GenerateName
+}
+
+// requestObjectMeta リクエストのリソース（削除の場合は削除前のリソース）のメタデータ
+func requestObjectMeta(req *admissionv1.AdmissionRequest) metav1.ObjectMeta {
+	raw := req.Object.Raw
+	if len(raw) == 0 {
+		raw = req.OldObject.Raw
+	}
+	var object metav1.PartialObjectMetadata
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return metav1.ObjectMeta{}
+		}
+	}
+	return object.ObjectMeta
+}
+
+// recordEvent Eventの記録が有効な場合にリクエストに関するWarning Eventを記録
+// dry-runのリクエストは実際には変更されないため記録しない
+// 処理期限付きの処理中に呼び出された場合は、そのレスポンスを採用した時点で記録する
+func (s *Server) recordEvent(ctx context.Context, req *admissionv1.AdmissionRequest, webhookErr *validator.WebhookError, reason, message string) {
+	if s.events == nil || req == nil || webhookErr == nil {
+		return
+	}
+	if req.DryRun != nil && *req.DryRun {
+		return
+	}
+	afterResponse(ctx, func() {
+		s.events.record(req, webhookErr, reason, message)
+	})
+}
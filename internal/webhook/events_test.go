@@ -0,0 +1,257 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+// withEventRecorder Eventを記録するテスト用のサーバーにする（テストの終了時に停止する）
+func withEventRecorder(t *testing.T, client *fake.Clientset, options record.CorrelatorOptions) testServerOption {
+	return func(s *Server) {
+		s.events = newEventRecorderWithOptions(client, options)
+		t.Cleanup(s.events.Shutdown)
+	}
+}
+
+// flushEvents 別のリソースへのEventが記録されるまで待ち、それまでに記録したEventの処理の完了を確認する
+// Eventは記録した順に1つのgoroutineで送信される
+func flushEvents(t *testing.T, server *Server, client *fake.Clientset) {
+	t.Helper()
+	sentinel := createDeploymentAdmissionRequest("sentinel", "default", 1)
+	server.recordEvent(context.Background(), sentinel, validator.NewDeploymentHPAConflictError(), EventReasonDenied, "sentinel")
+	waitForEvents(t, client, func(events []corev1.Event) bool {
+		for _, event := range events {
+			if event.InvolvedObject.Name == "sentinel" {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// waitForEvents default namespaceのEventが条件を満たすまで待つ
+func waitForEvents(t *testing.T, client *fake.Clientset, condition func([]corev1.Event) bool) []corev1.Event {
+	t.Helper()
+	var events []corev1.Event
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+		list, err := client.CoreV1().Events("default").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		events = list.Items
+		return condition(events), nil
+	})
+	if err != nil {
+		t.Fatalf("Timed out waiting for events: %v (events: %+v)", err, events)
+	}
+	return events
+}
+
+// eventsFor 指定したリソースを対象とするEvent
+func eventsFor(events []corev1.Event, kind, name string) []corev1.Event {
+	var matched []corev1.Event
+	for _, event := range events {
+		if event.InvolvedObject.Kind == kind && event.InvolvedObject.Name == name {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+func TestServer_Events_Denied(t *testing.T) {
+	// 同じリソースへのEventは2件まで送信し、それ以降は破棄する
+	client := newHPATestClient()
+	server := newTestServer(client, &config.WebhookConfig{Environment: "development"}, withEventRecorder(t, client, record.CorrelatorOptions{
+		BurstSize: 2,
+		QPS:       1.0 / 3600,
+	}))
+
+	for i := 0; i < 4; i++ {
+		response := server.validateAdmissionRequest(context.Background(), createDeploymentAdmissionRequest("test-deployment", "default", 1))
+		if response.Allowed {
+			t.Fatal("Expected the request to be denied")
+		}
+	}
+	flushEvents(t, server, client)
+
+	events, err := client.CoreV1().Events("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	hpaEvents := eventsFor(events.Items, "HorizontalPodAutoscaler", "test-hpa")
+	if len(hpaEvents) != 1 {
+		t.Fatalf("Expected 1 aggregated event on the HPA, got %d: %+v", len(hpaEvents), events.Items)
+	}
+	if denied := eventsFor(events.Items, "Deployment", "test-deployment"); len(denied) != 0 {
+		t.Errorf("Expected no event on the denied Deployment, got %+v", denied)
+	}
+
+	event := hpaEvents[0]
+	if event.Type != corev1.EventTypeWarning || event.Reason != EventReasonDenied {
+		t.Errorf("Type/Reason = %s/%s, expected %s/%s", event.Type, event.Reason, corev1.EventTypeWarning, EventReasonDenied)
+	}
+	if event.InvolvedObject.UID != "hpa-uid" || event.InvolvedObject.APIVersion != "autoscaling/v2" {
+		t.Errorf("InvolvedObject = %+v, expected the existing HPA", event.InvolvedObject)
+	}
+	if event.Source.Component != EventComponent {
+		t.Errorf("Source.Component = %s, expected %s", event.Source.Component, EventComponent)
+	}
+	if event.Count != 2 {
+		t.Errorf("Count = %d, expected 2 (rate limited after the burst)", event.Count)
+	}
+	expectedPrefix := "CREATE Deployment default/test-deployment: [" + validator.CodeDeploymentHPAConflict + "] "
+	if !strings.HasPrefix(event.Message, expectedPrefix) {
+		t.Errorf("Message = %q, expected prefix %q", event.Message, expectedPrefix)
+	}
+	if event.Annotations[EventAnnotationErrorCode] != validator.CodeDeploymentHPAConflict || event.Annotations[EventAnnotationRequestID] != "test-uid" {
+		t.Errorf("Annotations = %v", event.Annotations)
+	}
+}
+
+func TestServer_Events_ExemptedAndDryRun(t *testing.T) {
+	client := newHPATestClient()
+	server := newTestServer(client, &config.WebhookConfig{
+		Environment: "development",
+		ExemptUsers: []string{"admin@example.com"},
+	}, withEventRecorder(t, client, record.CorrelatorOptions{}))
+
+	// dry-runのリクエストはEventを記録しない
+	dryRun := true
+	dryRunReq := createDeploymentAdmissionRequest("test-deployment", "default", 1)
+	dryRunReq.DryRun = &dryRun
+	if response := server.validateAdmissionRequest(context.Background(), dryRunReq); response.Allowed {
+		t.Fatal("Expected the dry-run request to be denied")
+	}
+
+	exemptReq := createDeploymentAdmissionRequest("test-deployment", "default", 1)
+	exemptReq.UserInfo = authenticationv1.UserInfo{Username: "admin@example.com"}
+	if response := server.validateAdmissionRequest(context.Background(), exemptReq); !response.Allowed {
+		t.Fatal("Expected the exempted request to be allowed")
+	}
+	flushEvents(t, server, client)
+
+	events, err := client.CoreV1().Events("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	hpaEvents := eventsFor(events.Items, "HorizontalPodAutoscaler", "test-hpa")
+	if len(hpaEvents) != 1 {
+		t.Fatalf("Expected 1 event on the HPA, got %d: %+v", len(hpaEvents), events.Items)
+	}
+	if hpaEvents[0].Reason != EventReasonExempted || hpaEvents[0].Count != 1 {
+		t.Errorf("Reason/Count = %s/%d, expected %s/1", hpaEvents[0].Reason, hpaEvents[0].Count, EventReasonExempted)
+	}
+	if !strings.Contains(hpaEvents[0].Message, "admin@example.com") {
+		t.Errorf("Message = %q, expected the exempted user", hpaEvents[0].Message)
+	}
+}
+
+func TestEventTarget(t *testing.T) {
+	conflict := func() *validator.WebhookError {
+		return validator.NewDeploymentHPAConflictError().WithRelatedObject("HorizontalPodAutoscaler", "test-hpa", "default")
+	}
+
+	update := createDeploymentAdmissionRequest("web", "default", 1)
+	update.Operation = admissionv1.Update
+	update.Object = runtime.RawExtension{Raw: []byte(`{"metadata":{"name":"web","namespace":"default","uid":"deployment-uid"}}`)}
+
+	generated := createDeploymentAdmissionRequest("", "default", 1)
+	generated.Object = runtime.RawExtension{Raw: []byte(`{"metadata":{"generateName":"web-","namespace":"default"}}`)}
+
+	tests := []struct {
+		name       string
+		req        *admissionv1.AdmissionRequest
+		webhookErr *validator.WebhookError
+		expected   corev1.ObjectReference
+	}{
+		{
+			name:       "existing related object",
+			req:        createDeploymentAdmissionRequest("web", "default", 1),
+			webhookErr: conflict().WithRelatedObjectUID("hpa-uid"),
+			expected:   corev1.ObjectReference{Kind: "HorizontalPodAutoscaler", APIVersion: "autoscaling/v2", Name: "test-hpa", Namespace: "default", UID: "hpa-uid"},
+		},
+		{
+			name:       "related object without UID",
+			req:        createDeploymentAdmissionRequest("web", "default", 1),
+			webhookErr: conflict(),
+			expected:   corev1.ObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Name: "web", Namespace: "default"},
+		},
+		{
+			name:       "update of existing object",
+			req:        update,
+			webhookErr: validator.NewDeploymentHPAConflictError(),
+			expected:   corev1.ObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Name: "web", Namespace: "default", UID: "deployment-uid"},
+		},
+		{
+			name:       "generateName",
+			req:        generated,
+			webhookErr: validator.NewDeploymentHPAConflictError(),
+			expected:   corev1.ObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Name: "web-", Namespace: "default"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventTarget(tt.req, tt.webhookErr); *got != tt.expected {
+				t.Errorf("eventTarget() = %+v, expected %+v", *got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestServer_Events_DeadlineExceeded(t *testing.T) {
+	client := newHPATestClient()
+	server := newTestServer(client, &config.WebhookConfig{Environment: "development", FailurePolicy: "Ignore"}, withEventRecorder(t, client, record.CorrelatorOptions{}))
+
+	// 期限を超過した処理が後から記録しようとしたEventは破棄し、期限超過のEventのみ記録する
+	handled := make(chan struct{})
+	slowHandler := func(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		defer close(handled)
+		<-ctx.Done()
+		server.recordEvent(ctx, req, validator.NewDeploymentHPAConflictError(), EventReasonDenied, "late")
+		return &admissionv1.AdmissionResponse{UID: req.UID, Allowed: false}
+	}
+	slowReq := createDeploymentAdmissionRequest("slow", "default", 1)
+	if response := server.withAdmissionDeadline(context.Background(), 20*time.Millisecond, slowReq, server.allowOnDeadline(), slowHandler); !response.Allowed {
+		t.Fatal("Expected the request to be allowed after the deadline")
+	}
+	<-handled
+
+	// 期限内に完了した処理のEventはレスポンスを採用した時点で記録する
+	fastHandler := func(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		server.recordEvent(ctx, req, validator.NewDeploymentHPAConflictError(), EventReasonDenied, "fast")
+		return &admissionv1.AdmissionResponse{UID: req.UID, Allowed: false}
+	}
+	fastReq := createDeploymentAdmissionRequest("fast", "default", 1)
+	if response := server.withAdmissionDeadline(context.Background(), time.Second, fastReq, server.allowOnDeadline(), fastHandler); response.Allowed {
+		t.Fatal("Expected the request to be denied")
+	}
+	flushEvents(t, server, client)
+
+	events, err := client.CoreV1().Events("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	slowEvents := eventsFor(events.Items, "Deployment", "slow")
+	if len(slowEvents) != 1 || slowEvents[0].Reason != EventReasonDeadlineExceeded {
+		t.Errorf("Expected only a %s event for the timed out request, got %+v", EventReasonDeadlineExceeded, slowEvents)
+	}
+	fastEvents := eventsFor(events.Items, "Deployment", "fast")
+	if len(fastEvents) != 1 || fastEvents[0].Reason != EventReasonDenied {
+		t.Errorf("Expected a %s event for the completed request, got %+v", EventReasonDenied, fastEvents)
+	}
+}
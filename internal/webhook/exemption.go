@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+
+	admissionv1 "k8s.io/api/admission/v1"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+const (
+	// AuditAnnotationExemptedUser 検証の対象外として許可した呼び出し元のユーザー名を記録する監査アノテーションのキー
+	AuditAnnotationExemptedUser = "exempted-user"
+	// AuditAnnotationExemptedBy 呼び出し元が一致した設定（"<種類>:<パターン>"）を記録する監査アノテーションのキー
+	AuditAnnotationExemptedBy = "exempted-by"
+	// AuditAnnotationExemptedRule 許可した違反のエラーコードを記録する監査アノテーションのキー
+	AuditAnnotationExemptedRule = "exempted-rule"
+)
+
+// handleValidationResult 検証結果からレスポンスを作成
+// ルールの違反があっても呼び出し元が検証の対象外の場合は、拒否せずに警告付きで許可する
+// APIサーバーへの接続エラーなどルールの違反以外のエラーは、対象外の呼び出し元でも失敗ポリシーに従う
+func (s *Server) handleValidationResult(ctx context.Context, err error, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if isRuleViolation(err) && req != nil && s.config != nil {
+		if exemption, ok := s.config.MatchExemption(req.UserInfo.Username, req.UserInfo.Groups); ok {
+			return s.exemptResponse(ctx, err, req, exemption)
+		}
+	}
+	response := s.errorHandler.HandleError(ctx, err, req)
+	if err != nil && !response.Allowed {
+		webhookErr := asWebhookError(err)
+		s.recordEvent(ctx, req, webhookErr, EventReasonDenied, webhookErr.Message)
+		s.notifyDenial(ctx, req, webhookErr)
+	}
+	return response
+}
+
+// isRuleViolation エラーがルールの違反（検証の対象外として許可できるエラー）かどうか
+func isRuleViolation(err error) bool {
+	webhookErr, ok := err.(*validator.WebhookError)
+	return ok && webhookErr.Type == validator.ErrorTypeValidation && validator.IsRuleCode(webhookErr.Code)
+}
+
+// asWebhookError エラーをWebhookErrorに変換（WebhookError以外は内部エラーとして扱う）
+func asWebhookError(err error) *validator.WebhookError {
+	if webhookErr, ok := err.(*validator.WebhookError); ok {
+		return webhookErr
+	}
+	return validator.NewWebhookError(
+		validator.ErrorTypeInternal,
+		validator.CodeInternalUnknown,
+		err.Error(),
+	).WithInternalError(err)
+}
+
+// exemptResponse 検証の対象外の呼び出し元の違反を記録し、警告付きで許可するレスポンスを作成
+func (s *Server) exemptResponse(ctx context.Context, err error, req *admissionv1.AdmissionRequest, exemption config.Exemption) *admissionv1.AdmissionResponse {
+	requestID := logging.RequestIDFromContext(ctx)
+
+	webhookErr := asWebhookError(err)
+	if webhookErr.RequestID == "" {
+		webhookErr.RequestID = requestID
+	}
+	if webhookErr.ResourceType == "" {
+		webhookErr.ResourceType = req.Kind.Kind
+	}
+	if webhookErr.ResourceName == "" {
+		webhookErr.ResourceName = req.Name
+	}
+	if webhookErr.Namespace == "" {
+		webhookErr.Namespace = req.Namespace
+	}
+
+	// 処理期限を超過して破棄したレスポンスの許可は記録しないよう、レスポンスの確定後に記録する
+	afterResponse(ctx, func() {
+		metrics.RecordExemptedRequest(exemption.String(), webhookErr.Code, req.Kind.Kind)
+		s.logger.WithRequestID(requestID).Warn("検証の対象外の呼び出し元のため、違反がありますがリクエストを許可します", map[string]interface{}{
+			"resource_type": req.Kind.Kind,
+			"resource_name": req.Name,
+			"namespace":     req.Namespace,
+			"operation":     string(req.Operation),
+			"user":          req.UserInfo.Username,
+			"groups":        req.UserInfo.Groups,
+			"exempted_by":   exemption.String(),
+			"error_code":    webhookErr.Code,
+			"message":       webhookErr.Message,
+		})
+	})
+
+	lang := s.errorHandler.ResolveLanguage(ctx, req.Namespace)
+	data := webhookErr.Localize(lang).MessageData()
+	data.User = req.UserInfo.Username
+	warning := validator.LocalizeFragment(lang, validator.WarnExemptionAllowed, data)
+	s.recordEvent(ctx, req, webhookErr, EventReasonExempted, warning)
+
+	return &admissionv1.AdmissionResponse{
+		UID:      req.UID,
+		Allowed:  true,
+		Warnings: []string{warning},
+		AuditAnnotations: map[string]string{
+			AuditAnnotationExemptedUser: req.UserInfo.Username,
+			AuditAnnotationExemptedBy:   exemption.String(),
+			AuditAnnotationExemptedRule: webhookErr.Code,
+		},
+	}
+}
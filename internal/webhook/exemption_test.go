@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/metrics"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+func TestServer_validateAdmissionRequest_Exemption(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-hpa", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment"},
+		},
+	})
+
+	cfg := &config.WebhookConfig{
+		Environment:           "production",
+		ExemptUsers:           []string{"admin@example.com"},
+		ExemptGroups:          []string{"system:masters"},
+		ExemptServiceAccounts: []string{"ops:break-glass-*"},
+	}
+	server := newTestServer(fakeClient, cfg)
+
+	tests := []struct {
+		name            string
+		userInfo        authenticationv1.UserInfo
+		replicas        int32
+		expectAllowed   bool
+		expectExemption string
+	}{
+		{
+			name:     "violation by a regular user is denied",
+			userInfo: authenticationv1.UserInfo{Username: "developer@example.com", Groups: []string{"system:authenticated"}},
+			replicas: 1,
+		},
+		{
+			name:            "violation by an exempt user is allowed",
+			userInfo:        authenticationv1.UserInfo{Username: "admin@example.com"},
+			replicas:        1,
+			expectAllowed:   true,
+			expectExemption: "user:admin@example.com",
+		},
+		{
+			name:            "violation by an exempt group member is allowed",
+			userInfo:        authenticationv1.UserInfo{Username: "alice", Groups: []string{"system:authenticated", "system:masters"}},
+			replicas:        1,
+			expectAllowed:   true,
+			expectExemption: "group:system:masters",
+		},
+		{
+			name:            "violation by an exempt service account is allowed",
+			userInfo:        authenticationv1.UserInfo{Username: "system:serviceaccount:ops:break-glass-deployer"},
+			replicas:        1,
+			expectAllowed:   true,
+			expectExemption: "serviceaccount:ops:break-glass-*",
+		},
+		{
+			name:     "service account in another namespace is denied",
+			userInfo: authenticationv1.UserInfo{Username: "system:serviceaccount:default:break-glass-deployer"},
+			replicas: 1,
+		},
+		{
+			name:          "exempt user without violation is allowed without warning",
+			userInfo:      authenticationv1.UserInfo{Username: "admin@example.com"},
+			replicas:      2,
+			expectAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before float64
+			if tt.expectExemption != "" {
+				before = testutil.ToFloat64(metrics.WebhookExemptedRequests.WithLabelValues(tt.expectExemption, validator.CodeDeploymentHPAConflict, "Deployment"))
+			}
+
+			req := createDeploymentAdmissionRequest("test-deployment", "default", tt.replicas)
+			req.UserInfo = tt.userInfo
+			response := server.validateAdmissionRequest(context.Background(), req)
+
+			if response.Allowed != tt.expectAllowed {
+				t.Fatalf("Allowed = %v, expected %v (result: %+v)", response.Allowed, tt.expectAllowed, response.Result)
+			}
+			if response.UID != req.UID {
+				t.Errorf("UID = %s, expected %s", response.UID, req.UID)
+			}
+
+			if tt.expectExemption == "" {
+				if _, ok := response.AuditAnnotations[AuditAnnotationExemptedUser]; ok {
+					t.Errorf("Unexpected exemption audit annotations: %v", response.AuditAnnotations)
+				}
+				if tt.expectAllowed && len(response.Warnings) > 0 {
+					t.Errorf("Unexpected warnings: %v", response.Warnings)
+				}
+				return
+			}
+
+			expectedAnnotations := map[string]string{
+				AuditAnnotationExemptedUser: tt.userInfo.Username,
+				AuditAnnotationExemptedBy:   tt.expectExemption,
+				AuditAnnotationExemptedRule: validator.CodeDeploymentHPAConflict,
+			}
+			for key, value := range expectedAnnotations {
+				if response.AuditAnnotations[key] != value {
+					t.Errorf("AuditAnnotations[%s] = %q, expected %q", key, response.AuditAnnotations[key], value)
+				}
+			}
+			if len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], tt.userInfo.Username) || !strings.Contains(response.Warnings[0], "HPA") {
+				t.Errorf("Unexpected warnings: %v", response.Warnings)
+			}
+			if after := testutil.ToFloat64(metrics.WebhookExemptedRequests.WithLabelValues(tt.expectExemption, validator.CodeDeploymentHPAConflict, "Deployment")); after != before+1 {
+				t.Errorf("Expected exempted requests to increase by 1, got %v -> %v", before, after)
+			}
+		})
+	}
+}
+
+func TestServer_validateAdmissionRequest_ExemptionAPIError(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("list", "horizontalpodautoscalers", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "autoscaling", Resource: "horizontalpodautoscalers"}, "", errors.New("denied"))
+	})
+
+	cfg := &config.WebhookConfig{Environment: "production", FailurePolicy: "Fail", ExemptUsers: []string{"admin@example.com"}}
+	server := newTestServer(fakeClient, cfg)
+
+	// HPAを取得できない場合はルールの違反ではないため、検証の対象外の呼び出し元でも許可しない
+	req := createDeploymentAdmissionRequest("test-deployment", "default", 1)
+	req.UserInfo = authenticationv1.UserInfo{Username: "admin@example.com"}
+	response := server.validateAdmissionRequest(context.Background(), req)
+	if response.Allowed {
+		t.Fatalf("Expected the request to be denied, got %+v", response)
+	}
+	if _, ok := response.AuditAnnotations[AuditAnnotationExemptedRule]; ok {
+		t.Errorf("Unexpected exemption audit annotations: %v", response.AuditAnnotations)
+	}
+}
+
+func TestServer_Exemption_DeadlineExceeded(t *testing.T) {
+	cfg := &config.WebhookConfig{Environment: "production", FailurePolicy: "Ignore", ExemptUsers: []string{"admin@example.com"}}
+	server := newTestServer(fake.NewSimpleClientset(), cfg)
+	exempted := metrics.WebhookExemptedRequests.WithLabelValues("user:admin@example.com", validator.CodeDeploymentHPAConflict, "Deployment")
+	before := testutil.ToFloat64(exempted)
+
+	// 期限を超過した処理が後から作成した許可は記録しない
+	handled := make(chan struct{})
+	slowHandler := func(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		defer close(handled)
+		<-ctx.Done()
+		return server.handleValidationResult(ctx, validator.NewDeploymentHPAConflictError(), req)
+	}
+	req := createDeploymentAdmissionRequest("slow", "default", 1)
+	req.UserInfo = authenticationv1.UserInfo{Username: "admin@example.com"}
+	if response := server.withAdmissionDeadline(context.Background(), 20*time.Millisecond, req, server.allowOnDeadline(), slowHandler); response.AuditAnnotations[AuditAnnotationExemptedUser] != "" {
+		t.Fatalf("Expected the deadline response, got %+v", response)
+	}
+	<-handled
+
+	if got := testutil.ToFloat64(exempted) - before; got != 0 {
+		t.Errorf("Expected the discarded exemption not to be recorded, got %v", got)
+	}
+}
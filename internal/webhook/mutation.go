@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+
+	"k8s-deployment-hpa-validator/internal/decisionlog"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+// AuditAnnotationMutatedRule 自動修正した違反のエラーコードを記録する監査アノテーションのキー
+const AuditAnnotationMutatedRule = "mutated-rule"
+
+// handleMutate handles mutation requests
+func (s *Server) handleMutate(w http.ResponseWriter, r *http.Request) {
+	// AdmissionReviewを解析するまでは、生成したリクエストIDを使用する
+	requestID := logging.GenerateRequestID()
+	requestLogger := s.logger.WithRequestID(requestID)
+
+	requestLogger.Info("ミューテーションリクエストを受信しました", map[string]interface{}{
+		"remote_addr": r.RemoteAddr,
+		"method":      r.Method,
+		"path":        r.URL.Path,
+	})
+
+	// メトリクス記録開始
+	requestMetrics := metrics.NewRequestMetrics(r.Method, "unknown")
+
+	// Set response headers
+	w.Header().Set("Content-Type", "application/json")
+
+	admissionReview, ok := s.decodeAdmissionReview(w, r, requestLogger, requestMetrics)
+	if !ok {
+		return
+	}
+	req := admissionReview.Request
+	requestMetrics.ResourceType = req.Kind.Kind
+
+	// AdmissionRequestのUIDをリクエストIDとしてコンテキストに設定
+	requestID = admissionRequestID(req, requestID)
+	ctx := logging.ContextWithRequestID(r.Context(), requestID)
+	requestLogger = s.logger.WithRequestID(requestID)
+
+	admissionResponse := s.withAdmissionDeadline(ctx, s.admissionDeadline(r), req, s.allowOnDeadline(), s.mutateAdmissionRequest)
+	withRequestIDAnnotation(admissionResponse, requestID)
+
+	if !s.writeAdmissionReview(w, admissionResponse, requestLogger, requestMetrics) {
+		return
+	}
+
+	fields := decisionLogFields(req)
+	fields["allowed"] = admissionResponse.Allowed
+	fields["patched"] = len(admissionResponse.Patch) > 0
+	if admissionResponse.Allowed {
+		requestLogger.Info("ミューテーションが完了しました", fields)
+		requestMetrics.RecordSuccess()
+	} else {
+		if admissionResponse.Result != nil {
+			fields["message"] = admissionResponse.Result.Message
+		}
+		requestLogger.Warn("ミューテーションが失敗しました", fields)
+		requestMetrics.RecordError("mutation_failed")
+		s.recordDenial(req, "mutation_failed")
+	}
+	s.recordDecision(req, admissionResponse, decisionlog.ModeMutate, requestMetrics.StartTime)
+}
+
+// acceptsMutatedHPA ミューテーションが有効なnamespaceで、/mutateの修正が適用済みのHPAかどうか
+// HPAコントローラーが対象のDeploymentをminReplicasまでスケールアウトするため、Deploymentのreplica数に関わらず許可する
+func (s *Server) acceptsMutatedHPA(req *admissionv1.AdmissionRequest, hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	return s.mutator != nil && s.config.ShouldMutateNamespace(req.Namespace) && s.mutator.HPAMeetsFloor(hpa)
+}
+
+// mutateAdmissionRequest fixes rule violations and returns a patching admission response
+func (s *Server) mutateAdmissionRequest(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	requestID := logging.RequestIDFromContext(ctx)
+	requestLogger := s.logger.WithRequestID(requestID)
+
+	if req == nil {
+		err := validator.NewWebhookError(
+			validator.ErrorTypeInternal,
+			validator.CodeInternalUnknown,
+			"AdmissionRequestがnilです",
+		)
+		return s.errorHandler.HandleError(ctx, err, req)
+	}
+
+	// ミューテーションが無効、または対象外のnamespaceの場合はそのまま許可
+	if s.mutator == nil || !s.config.ShouldMutateNamespace(req.Namespace) {
+		return &admissionv1.AdmissionResponse{
+			UID:     req.UID,
+			Allowed: true,
+		}
+	}
+
+	// 警告はnamespaceの言語で返す
+	ctx = validator.ContextWithLanguage(ctx, s.errorHandler.ResolveLanguage(ctx, req.Namespace))
+
+	var (
+		result *validator.MutationResult
+		err    error
+	)
+
+	switch req.Kind.Kind {
+	case "Deployment":
+		deployment := &appsv1.Deployment{}
+		if parseErr := json.Unmarshal(req.Object.Raw, deployment); parseErr != nil {
+			err = validator.NewWebhookError(
+				validator.ErrorTypeInternal,
+				validator.CodeInvalidResource,
+				"Deploymentの解析に失敗しました",
+			).WithInternalError(parseErr).WithContext(requestID, "Deployment", req.Name, req.Namespace)
+		} else {
+			result, err = s.mutator.MutateDeployment(ctx, deployment)
+		}
+
+	case "HorizontalPodAutoscaler":
+		hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+		if parseErr := json.Unmarshal(req.Object.Raw, hpa); parseErr != nil {
+			err = validator.NewWebhookError(
+				validator.ErrorTypeInternal,
+				validator.CodeInvalidResource,
+				"HPAの解析に失敗しました",
+			).WithInternalError(parseErr).WithContext(requestID, "HorizontalPodAutoscaler", req.Name, req.Namespace)
+		} else {
+			result, err = s.mutator.MutateHPA(ctx, hpa)
+		}
+
+	default:
+		return &admissionv1.AdmissionResponse{
+			UID:     req.UID,
+			Allowed: true,
+		}
+	}
+
+	if err != nil {
+		return s.errorHandler.HandleError(ctx, err, req)
+	}
+
+	if !result.IsMutated() {
+		return &admissionv1.AdmissionResponse{
+			UID:     req.UID,
+			Allowed: true,
+		}
+	}
+
+	patch, err := json.Marshal(result.Patches)
+	if err != nil {
+		return s.errorHandler.HandleError(ctx, validator.NewInternalError("mutation", err), req)
+	}
+
+	requestLogger.Info("ルール違反を自動修正しました", map[string]interface{}{
+		"resource_type": req.Kind.Kind,
+		"resource_name": req.Name,
+		"namespace":     req.Namespace,
+		"error_code":    result.Violation.Code,
+		"patch":         string(patch),
+	})
+	metrics.RecordMutation(req.Kind.Kind, result.Violation.Code)
+	s.recordEvent(ctx, req, result.Violation, EventReasonMutated, strings.Join(result.Warnings, " "))
+
+	patchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{
+		UID:       req.UID,
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &patchType,
+		Warnings:  result.Warnings,
+		AuditAnnotations: map[string]string{
+			AuditAnnotationMutatedRule: result.Violation.Code,
+		},
+	}
+}
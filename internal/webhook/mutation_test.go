@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+func TestServer_mutateAdmissionRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         *config.WebhookConfig
+		request     *admissionv1.AdmissionRequest
+		expectPatch bool
+	}{
+		{
+			name:        "mutation disabled",
+			cfg:         &config.WebhookConfig{Environment: "development"},
+			request:     createDeploymentAdmissionRequest("test-deployment", "default", 1),
+			expectPatch: false,
+		},
+		{
+			name:        "mutation enabled for all namespaces",
+			cfg:         &config.WebhookConfig{Environment: "development", MutationEnabled: true},
+			request:     createDeploymentAdmissionRequest("test-deployment", "default", 1),
+			expectPatch: true,
+		},
+		{
+			name:        "namespace not opted in",
+			cfg:         &config.WebhookConfig{Environment: "development", MutationEnabled: true, MutationNamespaces: []string{"team-a"}},
+			request:     createDeploymentAdmissionRequest("test-deployment", "default", 1),
+			expectPatch: false,
+		},
+		{
+			name:        "no violation",
+			cfg:         &config.WebhookConfig{Environment: "development", MutationEnabled: true},
+			request:     createDeploymentAdmissionRequest("test-deployment", "default", 3),
+			expectPatch: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(newHPATestClient(), tt.cfg)
+
+			response := server.mutateAdmissionRequest(context.Background(), tt.request)
+			if !response.Allowed {
+				t.Fatalf("mutateAdmissionRequest() should always allow, got %+v", response.Result)
+			}
+			if (len(response.Patch) > 0) != tt.expectPatch {
+				t.Fatalf("mutateAdmissionRequest() patch = %s, expectPatch %v", response.Patch, tt.expectPatch)
+			}
+			if !tt.expectPatch {
+				return
+			}
+
+			if response.PatchType == nil || *response.PatchType != admissionv1.PatchTypeJSONPatch {
+				t.Errorf("Expected JSONPatch patch type, got %v", response.PatchType)
+			}
+			if len(response.Warnings) == 0 {
+				t.Error("Expected a warning explaining the mutation")
+			}
+
+			var patches []validator.PatchOperation
+			if err := json.Unmarshal(response.Patch, &patches); err != nil {
+				t.Fatalf("Failed to unmarshal patch: %v", err)
+			}
+			if len(patches) != 1 || patches[0].Path != "/spec/replicas" || patches[0].Value != float64(2) {
+				t.Errorf("Unexpected patch: %+v", patches)
+			}
+		})
+	}
+}
+
+func TestServer_handleMutate(t *testing.T) {
+	server := newTestServer(newHPATestClient(), &config.WebhookConfig{Environment: "development", MutationEnabled: true})
+
+	admissionReview := &admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "admission.k8s.io/v1",
+			Kind:       "AdmissionReview",
+		},
+		Request: createHPAAdmissionRequest("other-hpa", "default", "test-deployment"),
+	}
+	body, err := json.Marshal(admissionReview)
+	if err != nil {
+		t.Fatalf("Failed to marshal admission review: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/mutate", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	server.handleMutate(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("handleMutate() status = %v, expected %v", w.Code, http.StatusOK)
+	}
+
+	var responseReview admissionv1.AdmissionReview
+	if err := json.Unmarshal(w.Body.Bytes(), &responseReview); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	// 対象のDeploymentが存在しない場合は修正不要
+	if !responseReview.Response.Allowed || len(responseReview.Response.Patch) != 0 {
+		t.Errorf("handleMutate() unexpected response: %+v", responseReview.Response)
+	}
+}
+
+func TestServer_validateAdmissionRequest_MutatedHPA(t *testing.T) {
+	replicas, minReplicas := int32(1), int32(2)
+	tests := []struct {
+		name          string
+		cfg           *config.WebhookConfig
+		minReplicas   *int32
+		expectAllowed bool
+	}{
+		{
+			// ミューテーションが無効な場合は、minReplicasに関わらず1 replicaのDeploymentを対象とするHPAを拒否する
+			name:          "mutation disabled",
+			cfg:           &config.WebhookConfig{Environment: "development"},
+			minReplicas:   &minReplicas,
+			expectAllowed: false,
+		},
+		{
+			name:          "mutated HPA in mutation namespace",
+			cfg:           &config.WebhookConfig{Environment: "development", MutationEnabled: true},
+			minReplicas:   &minReplicas,
+			expectAllowed: true,
+		},
+		{
+			name:          "namespace not opted in",
+			cfg:           &config.WebhookConfig{Environment: "development", MutationEnabled: true, MutationNamespaces: []string{"team-a"}},
+			minReplicas:   &minReplicas,
+			expectAllowed: false,
+		},
+		{
+			name:          "minReplicas below the floor",
+			cfg:           &config.WebhookConfig{Environment: "development", MutationEnabled: true},
+			minReplicas:   nil,
+			expectAllowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset(&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "default"},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			})
+			server := newTestServer(fakeClient, tt.cfg)
+
+			req := createHPAAdmissionRequest("test-hpa", "default", "test-deployment")
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+			if err := json.Unmarshal(req.Object.Raw, hpa); err != nil {
+				t.Fatalf("Failed to unmarshal HPA: %v", err)
+			}
+			hpa.Spec.MinReplicas = tt.minReplicas
+			req.Object.Raw, _ = json.Marshal(hpa)
+
+			response := server.validateAdmissionRequest(context.Background(), req)
+			if response.Allowed != tt.expectAllowed {
+				t.Errorf("validateAdmissionRequest() allowed = %v, expected %v", response.Allowed, tt.expectAllowed)
+			}
+		})
+	}
+}
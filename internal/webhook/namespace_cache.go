@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// namespaceCacheTTL namespaceのアノテーションを再取得するまでの間隔
+	// アノテーションの変更はこの間隔で反映される
+	namespaceCacheTTL = 30 * time.Second
+	// namespaceLookupTimeout namespaceの取得の期限（AdmissionRequestの処理を長く待たせないため）
+	namespaceLookupTimeout = 2 * time.Second
+)
+
+// namespaceCacheEntry キャッシュしたnamespaceのアノテーション
+type namespaceCacheEntry struct {
+	annotations map[string]string
+	expires     time.Time
+}
+
+// namespaceCache namespaceのアノテーションを一定時間キャッシュする
+// 拒否や/mutateのたびにAPIサーバーへnamespaceを問い合わせないようにする
+type namespaceCache struct {
+	client kubernetes.Interface
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]namespaceCacheEntry
+}
+
+// newNamespaceCache 新しいnamespaceCacheを作成
+func newNamespaceCache(client kubernetes.Interface, ttl time.Duration) *namespaceCache {
+	return &namespaceCache{
+		client:  client,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]namespaceCacheEntry),
+	}
+}
+
+// annotations namespaceのアノテーションを取得（期限内はキャッシュを使用）
+// 取得に失敗した場合もnilをキャッシュし、APIサーバーの障害時に問い合わせを繰り返さない
+func (c *namespaceCache) annotations(ctx context.Context, namespace string) (map[string]string, error) {
+	now := c.now()
+	c.mu.Lock()
+	entry, ok := c.entries[namespace]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.annotations, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, namespaceLookupTimeout)
+	defer cancel()
+	var annotations map[string]string
+	ns, err := c.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err == nil {
+		annotations = ns.Annotations
+	}
+
+	c.mu.Lock()
+	c.entries[namespace] = namespaceCacheEntry{annotations: annotations, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return annotations, err
+}
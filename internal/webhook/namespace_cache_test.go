@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+func TestNamespaceCache(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "team-en",
+		Annotations: map[string]string{LanguageAnnotation: "en"},
+	}})
+	gets := 0
+	fakeClient.PrependReactor("get", "namespaces", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+
+	handler := NewErrorHandlerWithClient(&config.WebhookConfig{}, logging.NewLogger("test"), fakeClient)
+	now := time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC)
+	handler.namespaces.now = func() time.Time { return now }
+
+	// 期限内は同じnamespaceを再取得しない（存在しないnamespaceも同様）
+	for i := 0; i < 3; i++ {
+		if got := handler.ResolveLanguage(context.Background(), "team-en"); got != validator.LanguageEnglish {
+			t.Errorf("ResolveLanguage() = %q, expected %q", got, validator.LanguageEnglish)
+		}
+		handler.ResolveLanguage(context.Background(), "missing")
+	}
+	if gets != 2 {
+		t.Errorf("Expected 2 namespace lookups within the TTL, got %d", gets)
+	}
+
+	// 期限を過ぎたらアノテーションの変更を反映する
+	ns, _ := fakeClient.CoreV1().Namespaces().Get(context.Background(), "team-en", metav1.GetOptions{})
+	ns.Annotations[LanguageAnnotation] = "ja"
+	if _, err := fakeClient.CoreV1().Namespaces().Update(context.Background(), ns, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update namespace: %v", err)
+	}
+	gets = 0
+	now = now.Add(namespaceCacheTTL)
+	if got := handler.ResolveLanguage(context.Background(), "team-en"); got != validator.LanguageJapanese {
+		t.Errorf("ResolveLanguage() after TTL = %q, expected %q", got, validator.LanguageJapanese)
+	}
+	if gets != 1 {
+		t.Errorf("Expected 1 namespace lookup after the TTL, got %d", gets)
+	}
+}
@@ -0,0 +1,388 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s-deployment-hpa-validator/internal/asyncqueue"
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/metrics"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+const (
+	// NotificationURLAnnotation namespaceごとに拒否の通知先のURLを指定するアノテーション
+	NotificationURLAnnotation = "k8s-deployment-hpa-validator.io/notification-url"
+	// NotificationFormatAnnotation namespaceごとに拒否の通知の形式（json、slack）を指定するアノテーション
+	NotificationFormatAnnotation = "k8s-deployment-hpa-validator.io/notification-format"
+
+	// notificationTimeout 1回の通知の送信とnamespaceの取得の期限
+	notificationTimeout = 10 * time.Second
+	// notificationFlushTimeout 停止時に残りの拒否を通知する期限
+	notificationFlushTimeout = 30 * time.Second
+	// slackMaxDenials Slack形式で1回の通知に列挙する拒否の上限（超えた分は件数のみ）
+	slackMaxDenials = 20
+)
+
+// denialNotice 通知を待つ1件の拒否
+type denialNotice struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Operation string
+	User      string
+	Code      string
+	Message   string
+	RequestID string
+	Time      time.Time
+}
+
+// denialKey 同じリソース・エラーコードの拒否をまとめるキー
+type denialKey struct {
+	namespace string
+	kind      string
+	name      string
+	code      string
+}
+
+// notificationRoute 通知先（URLと形式）
+type notificationRoute struct {
+	url    string
+	format string
+}
+
+// NotificationDenial 通知する拒否（同じリソース・エラーコードの拒否は件数にまとめる）
+type NotificationDenial struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Operation string `json:"operation"`
+	User      string `json:"user"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Count     int    `json:"count"`
+	// RequestID 最初に拒否したリクエストのID
+	RequestID string    `json:"request_id"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// NotificationPayload json形式の通知の内容
+type NotificationPayload struct {
+	Cluster     string               `json:"cluster,omitempty"`
+	Environment string               `json:"environment,omitempty"`
+	WindowStart time.Time            `json:"window_start"`
+	WindowEnd   time.Time            `json:"window_end"`
+	Denials     []NotificationDenial `json:"denials"`
+}
+
+// slackPayload slack形式の通知の内容（Incoming Webhook）
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// notifier 拒否を一定間隔でまとめてHTTPエンドポイントに通知する
+// キューが一杯のため破棄した拒否は webhook_notifications_dropped_total に記録する
+type notifier struct {
+	queue      *asyncqueue.Queue[denialNotice]
+	client     kubernetes.Interface
+	httpClient *http.Client
+	retry      RetryConfig
+	window     time.Duration
+
+	defaultRoute notificationRoute
+	// allowedURLPrefixes namespaceのアノテーションで指定できる通知先のURLの接頭辞
+	allowedURLPrefixes []string
+	cluster            string
+	environment        string
+	logger             *logging.Logger
+}
+
+// newNotifier 設定に従い拒否を通知するnotifierを作成し、送信を開始する
+func newNotifier(cfg *config.WebhookConfig, client kubernetes.Interface, logger *logging.Logger) *notifier {
+	n := &notifier{
+		queue:              newNoticeQueue(cfg.NotificationQueueSize),
+		client:             client,
+		httpClient:         &http.Client{Timeout: notificationTimeout},
+		retry:              DefaultRetryConfig(),
+		window:             cfg.NotificationBatchWindow,
+		defaultRoute:       notificationRoute{url: cfg.NotificationURL, format: cfg.NotificationFormat},
+		allowedURLPrefixes: cfg.NotificationAllowedURLPrefixes,
+		cluster:            cfg.ClusterName,
+		environment:        cfg.Environment,
+		logger:             logger,
+	}
+	n.queue.Start(n.run)
+	return n
+}
+
+// newNoticeQueue 通知を待つ拒否のキューを作成
+func newNoticeQueue(size int) *asyncqueue.Queue[denialNotice] {
+	return asyncqueue.New[denialNotice](size, metrics.RecordNotificationDropped)
+}
+
+// notify 拒否を通知のキューに追加（ブロックしない）
+func (n *notifier) notify(notice denialNotice) {
+	n.queue.Enqueue(notice)
+}
+
+// run 間隔ごとにキューの拒否をまとめて通知する
+func (n *notifier) run(queue <-chan denialNotice) {
+	ticker := time.NewTicker(n.window)
+	defer ticker.Stop()
+
+	var pending []denialNotice
+	windowStart := time.Now()
+	for {
+		select {
+		case notice, ok := <-queue:
+			if !ok {
+				// 停止時は残りの拒否を通知する
+				ctx, cancel := context.WithTimeout(context.Background(), notificationFlushTimeout)
+				n.flush(ctx, pending, windowStart, time.Now())
+				cancel()
+				return
+			}
+			pending = append(pending, notice)
+		case <-ticker.C:
+			windowEnd := time.Now()
+			n.flush(context.Background(), pending, windowStart, windowEnd)
+			pending = nil
+			windowStart = windowEnd
+		}
+	}
+}
+
+// flush 拒否を通知先ごとにまとめて送信
+func (n *notifier) flush(ctx context.Context, pending []denialNotice, windowStart, windowEnd time.Time) {
+	if len(pending) == 0 {
+		return
+	}
+
+	routes := make(map[string]notificationRoute)
+	batches := make(map[notificationRoute][]NotificationDenial)
+	indexes := make(map[notificationRoute]map[denialKey]int)
+	for _, notice := range pending {
+		route, ok := routes[notice.Namespace]
+		if !ok {
+			route = n.resolveRoute(ctx, notice.Namespace)
+			routes[notice.Namespace] = route
+		}
+		if route.url == "" {
+			continue
+		}
+
+		if indexes[route] == nil {
+			indexes[route] = make(map[denialKey]int)
+		}
+		key := denialKey{namespace: notice.Namespace, kind: notice.Kind, name: notice.Name, code: notice.Code}
+		if i, exists := indexes[route][key]; exists {
+			batches[route][i].Count++
+			batches[route][i].LastSeen = notice.Time
+			continue
+		}
+		indexes[route][key] = len(batches[route])
+		batches[route] = append(batches[route], NotificationDenial{
+			Namespace: notice.Namespace,
+			Kind:      notice.Kind,
+			Name:      notice.Name,
+			Operation: notice.Operation,
+			User:      notice.User,
+			Code:      notice.Code,
+			Message:   notice.Message,
+			Count:     1,
+			RequestID: notice.RequestID,
+			FirstSeen: notice.Time,
+			LastSeen:  notice.Time,
+		})
+	}
+
+	for route, denials := range batches {
+		payload := NotificationPayload{
+			Cluster:     n.cluster,
+			Environment: n.environment,
+			WindowStart: windowStart,
+			WindowEnd:   windowEnd,
+			Denials:     denials,
+		}
+		if err := n.send(ctx, route, payload); err != nil {
+			// SlackのURLには認証情報が含まれるため、URLは記録しない
+			n.logger.Warn("拒否の通知に失敗しました", map[string]interface{}{
+				"format":  route.format,
+				"denials": len(denials),
+				"error":   err.Error(),
+			})
+		}
+	}
+}
+
+// resolveRoute namespaceのアノテーションから通知先を決定（指定がない項目は既定の設定を使用）
+// 通知先のURLは許可した接頭辞に一致する場合のみ使用する
+func (n *notifier) resolveRoute(ctx context.Context, namespace string) notificationRoute {
+	route := n.defaultRoute
+	if n.client == nil || namespace == "" {
+		return route
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+	ns, err := n.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		n.logger.Debug("namespaceの取得に失敗しました", map[string]interface{}{
+			"namespace": namespace,
+			"error":     err.Error(),
+		})
+		return route
+	}
+
+	if rawURL, ok := ns.Annotations[NotificationURLAnnotation]; ok {
+		// SlackのURLには認証情報が含まれるため、URLはスキームとホストのみ記録する
+		if err := config.ValidateNotificationURL(rawURL); err != nil {
+			n.logger.Warn("namespaceの通知先のURLが無効なため、既定の通知先を使用します", urlLogFields(namespace, rawURL))
+		} else if !config.IsAllowedNotificationURL(rawURL, n.allowedURLPrefixes) {
+			n.logger.Warn("namespaceの通知先のURLが許可されていないため、既定の通知先を使用します", urlLogFields(namespace, rawURL))
+		} else {
+			route.url = rawURL
+		}
+	}
+	if format, ok := ns.Annotations[NotificationFormatAnnotation]; ok {
+		if config.IsValidNotificationFormat(format) {
+			route.format = format
+		} else {
+			n.logger.Warn("namespaceの通知の形式が無効なため、既定の形式を使用します", map[string]interface{}{
+				"namespace": namespace,
+				"format":    format,
+			})
+		}
+	}
+	return route
+}
+
+// urlLogFields 通知先のURLのうち、ログに記録するnamespaceとスキーム・ホスト（パスやクエリは認証情報を含むため記録しない）
+func urlLogFields(namespace, rawURL string) map[string]interface{} {
+	fields := map[string]interface{}{"namespace": namespace}
+	if u, err := url.Parse(rawURL); err == nil {
+		fields["scheme"] = u.Scheme
+		fields["host"] = u.Host
+	}
+	return fields
+}
+
+// send 通知先の形式で拒否を送信（失敗した場合は再試行する）
+func (n *notifier) send(ctx context.Context, route notificationRoute, payload NotificationPayload) error {
+	var body []byte
+	var err error
+	if route.format == config.NotificationFormatSlack {
+		body, err = json.Marshal(slackPayload{Text: slackText(payload)})
+	} else {
+		body, err = json.Marshal(payload)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	err = WithRetry(ctx, n.retry, func() error {
+		return n.post(ctx, route.url, body)
+	})
+	metrics.RecordNotification(err == nil)
+	return err
+}
+
+// post 1回の通知を送信
+// 通知先の一時的な障害（5xx、429）と通信エラーは再試行し、それ以外の4xxは再試行しない
+func (n *notifier) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return validator.NewWebhookError(validator.ErrorTypeConfiguration, validator.CodeInvalidConfig,
+			fmt.Sprintf("failed to create notification request: %v", err)).WithInternalError(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	message := fmt.Sprintf("notification endpoint returned %s", resp.Status)
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return validator.NewWebhookError(validator.ErrorTypeNetwork, validator.CodeNetworkConnection, message)
+	}
+	return validator.NewWebhookError(validator.ErrorTypeConfiguration, validator.CodeInvalidConfig, message)
+}
+
+// slackText 拒否の一覧をSlackのmrkdwn形式の文字列にする
+func slackText(payload NotificationPayload) string {
+	total := 0
+	for _, denial := range payload.Denials {
+		total += denial.Count
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, ":no_entry: *k8s-deployment-hpa-validator* が %d 件のリクエストを拒否しました", total)
+	if payload.Cluster != "" || payload.Environment != "" {
+		fmt.Fprintf(&b, " (cluster: %s, environment: %s)", payload.Cluster, payload.Environment)
+	}
+	fmt.Fprintf(&b, "\n%s 〜 %s\n", payload.WindowStart.UTC().Format(time.RFC3339), payload.WindowEnd.UTC().Format(time.RFC3339))
+
+	denials := append([]NotificationDenial(nil), payload.Denials...)
+	// 件数の多い順に列挙する
+	sort.SliceStable(denials, func(i, j int) bool { return denials[i].Count > denials[j].Count })
+	for i, denial := range denials {
+		if i == slackMaxDenials {
+			fmt.Fprintf(&b, "…ほか %d 件のリソース\n", len(denials)-slackMaxDenials)
+			break
+		}
+		fmt.Fprintf(&b, "• `%s/%s` %s `%s` × %d (%s): %s\n",
+			denial.Namespace, denial.Name, denial.Kind, denial.Code, denial.Count, denial.User, denial.Message)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Close キューの拒否をすべて通知してから停止する
+func (n *notifier) Close() {
+	n.queue.Close()
+}
+
+// notifyDenial 通知が有効な場合に拒否を通知のキューに追加
+// dry-runのリクエストは実際には変更されないため通知しない
+// 処理期限を超過して破棄したレスポンスの拒否は通知しないよう、レスポンスの確定後に追加する
+func (s *Server) notifyDenial(ctx context.Context, req *admissionv1.AdmissionRequest, webhookErr *validator.WebhookError) {
+	if s.notifier == nil || req == nil || webhookErr == nil {
+		return
+	}
+	if req.DryRun != nil && *req.DryRun {
+		return
+	}
+	notice := denialNotice{
+		Namespace: req.Namespace,
+		Kind:      req.Kind.Kind,
+		Name:      requestName(req),
+		Operation: string(req.Operation),
+		User:      req.UserInfo.Username,
+		Code:      webhookErr.Code,
+		Message:   webhookErr.Message,
+		RequestID: string(req.UID),
+		Time:      time.Now(),
+	}
+	afterResponse(ctx, func() {
+		s.notifier.notify(notice)
+	})
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+
+	"k8s-deployment-hpa-validator/internal/logging"
+)
+
+const (
+	// AuditAnnotationRequestID リクエストID（AdmissionRequestのUID）を記録する監査アノテーションのキー
+	AuditAnnotationRequestID = "request-id"
+
+	// RequestIDHeader 処理中のリクエストIDをKubernetes APIへのリクエストに付与するヘッダー
+	// API serverはAudit-IDヘッダーの値を監査イベントのauditIDとして使用するため、
+	// webhookが処理中に呼び出したAPIを監査ログでAdmissionRequestのUIDから検索できる
+	RequestIDHeader = "Audit-ID"
+)
+
+// admissionRequestID AdmissionRequestのUIDをリクエストIDとし、API serverの監査ログと対応付ける
+// UIDがない場合は受信時に生成したIDを使用する
+func admissionRequestID(req *admissionv1.AdmissionRequest, fallback string) string {
+	if req == nil || req.UID == "" {
+		return fallback
+	}
+	return string(req.UID)
+}
+
+// withRequestIDAnnotation レスポンスの監査アノテーションにリクエストIDを記録
+func withRequestIDAnnotation(response *admissionv1.AdmissionResponse, requestID string) *admissionv1.AdmissionResponse {
+	if response == nil || requestID == "" {
+		return response
+	}
+	if response.AuditAnnotations == nil {
+		response.AuditAnnotations = map[string]string{}
+	}
+	response.AuditAnnotations[AuditAnnotationRequestID] = requestID
+	return response
+}
+
+// requestIDRoundTripper コンテキストのリクエストIDをKubernetes APIへのリクエストのヘッダーに付与する
+type requestIDRoundTripper struct {
+	next http.RoundTripper
+}
+
+// wrapRequestIDTransport rest.Config.Wrapに渡すトランスポートのラッパー
+func wrapRequestIDTransport(next http.RoundTripper) http.RoundTripper {
+	return &requestIDRoundTripper{next: next}
+}
+
+// RoundTrip リクエストIDのヘッダーを付与して次のトランスポートに渡す
+func (rt *requestIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if requestID := logging.RequestIDFromContext(req.Context()); requestID != "" && req.Header.Get(RequestIDHeader) == "" {
+		// RoundTripperは元のリクエストを変更してはならないため複製する
+		req = req.Clone(req.Context())
+		req.Header.Set(RequestIDHeader, requestID)
+	}
+	return rt.next.RoundTrip(req)
+}
@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/logging"
+	"k8s-deployment-hpa-validator/internal/validator"
+)
+
+// requestIDValidator 受け取ったコンテキストのリクエストIDを記録し、指定したエラーを返すテスト用のValidator
+type requestIDValidator struct {
+	err       *validator.WebhookError
+	requestID string
+}
+
+func (v *requestIDValidator) ValidateDeployment(ctx context.Context, deployment *appsv1.Deployment) error {
+	v.requestID = logging.RequestIDFromContext(ctx)
+	if v.err == nil {
+		return nil
+	}
+	return v.err
+}
+
+func (v *requestIDValidator) ValidateHPA(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) error {
+	return v.ValidateDeployment(ctx, nil)
+}
+
+func (v *requestIDValidator) ValidateResource(ctx context.Context, resourceType string, resource interface{}) validator.ValidationResult {
+	return validator.ValidationResult{Allowed: true}
+}
+
+func TestServer_handleValidate_RequestID(t *testing.T) {
+	tests := []struct {
+		name          string
+		uid           types.UID
+		err           *validator.WebhookError
+		expectAllowed bool
+	}{
+		{name: "allowed", uid: "5f0c9d3e-7c1b-4a51-9d8e-2f6a1b3c4d5e", expectAllowed: true},
+		{
+			name: "denied",
+			uid:  "0b8e2c4a-1d3f-4e5a-8b7c-9d0e1f2a3b4c",
+			err:  validator.NewWebhookError(validator.ErrorTypeValidation, validator.CodeDeploymentHPAConflict, "conflict"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &requestIDValidator{err: tt.err}
+			cfg := &config.WebhookConfig{Environment: "development"}
+			logger := logging.NewLogger("test-webhook")
+			server := &Server{
+				client:       fake.NewSimpleClientset(),
+				validator:    stub,
+				logger:       logger,
+				config:       cfg,
+				errorHandler: NewErrorHandler(cfg, logger),
+			}
+
+			admissionRequest := createDeploymentAdmissionRequest("web", "default", 2)
+			admissionRequest.UID = tt.uid
+			body, err := json.Marshal(&admissionv1.AdmissionReview{
+				TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+				Request:  admissionRequest,
+			})
+			if err != nil {
+				t.Fatalf("Failed to marshal admission review: %v", err)
+			}
+			r := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(string(body)))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			server.handleValidate(w, r)
+
+			var review admissionv1.AdmissionReview
+			if err := json.Unmarshal(w.Body.Bytes(), &review); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if review.Response.Allowed != tt.expectAllowed {
+				t.Fatalf("Allowed = %v, expected %v", review.Response.Allowed, tt.expectAllowed)
+			}
+			if got := review.Response.AuditAnnotations[AuditAnnotationRequestID]; got != string(tt.uid) {
+				t.Errorf("AuditAnnotations[%s] = %q, expected %q", AuditAnnotationRequestID, got, tt.uid)
+			}
+			if stub.requestID != string(tt.uid) {
+				t.Errorf("Validator context request ID = %q, expected %q", stub.requestID, tt.uid)
+			}
+			if tt.err != nil && tt.err.RequestID != string(tt.uid) {
+				t.Errorf("WebhookError.RequestID = %q, expected %q", tt.err.RequestID, tt.uid)
+			}
+		})
+	}
+}
+
+func TestAdmissionRequestID(t *testing.T) {
+	if got := admissionRequestID(&admissionv1.AdmissionRequest{UID: "abc"}, "generated"); got != "abc" {
+		t.Errorf("admissionRequestID() = %q, expected abc", got)
+	}
+	if got := admissionRequestID(&admissionv1.AdmissionRequest{}, "generated"); got != "generated" {
+		t.Errorf("admissionRequestID() = %q, expected generated", got)
+	}
+}
+
+func TestRequestIDRoundTripper(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		headers []string
+	)
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Get("Audit-ID"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		})
+	}))
+	defer apiServer.Close()
+
+	restConfig := &rest.Config{Host: apiServer.URL}
+	restConfig.Wrap(wrapRequestIDTransport)
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := logging.ContextWithRequestID(context.Background(), "5f0c9d3e-7c1b-4a51-9d8e-2f6a1b3c4d5e")
+	if _, err := client.CoreV1().Namespaces().Get(ctx, "default", metav1.GetOptions{}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	// リクエストIDのないコンテキスト（起動時の処理など）ではヘッダーを付与しない
+	if _, err := client.CoreV1().Namespaces().Get(context.Background(), "default", metav1.GetOptions{}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{"5f0c9d3e-7c1b-4a51-9d8e-2f6a1b3c4d5e", ""}
+	if len(headers) != len(expected) || headers[0] != expected[0] || headers[1] != expected[1] {
+		t.Errorf("Audit-ID headers = %q, expected %q", headers, expected)
+	}
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -20,13 +24,17 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 
+	"k8s-deployment-hpa-validator/internal/audit"
 	"k8s-deployment-hpa-validator/internal/cert"
+	"k8s-deployment-hpa-validator/internal/cloudevents"
 	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/decisionlog"
 	"k8s-deployment-hpa-validator/internal/logging"
 	"k8s-deployment-hpa-validator/internal/metrics"
 	"k8s-deployment-hpa-validator/internal/validator"
@@ -34,22 +42,56 @@ import (
 
 // Server represents the webhook server
 type Server struct {
-	server       *http.Server
+	server *http.Server
+	// metricsServer メトリクスとヘルスチェック用の平文HTTPサーバー（無効な場合はnil）
+	metricsServer *http.Server
+	// metricsURL 起動したメトリクスサーバーの/metricsのURL（ヘルスチェック用）
+	metricsURL   atomic.Value
 	client       kubernetes.Interface
 	validator    validator.Validator
+	mutator      validator.Mutator
 	scheme       *runtime.Scheme
 	codecs       serializer.CodecFactory
 	certManager  *cert.Manager
+	// bootstrapper 自己署名CAによる証明書の自動発行（無効な場合はnil）
+	bootstrapper *cert.Bootstrapper
+	// caBundleChecker webhook設定のcaBundleとサーバー証明書の一致の確認（無効な場合はnil）
+	caBundleChecker *cert.CABundleChecker
+	// requesterNamespaces 拒否のメトリクスのrequester_namespaceラベルの値の制限（無効な場合はnil）
+	requesterNamespaces *metrics.LabelLimiter
+	// events 拒否や警告付きの許可のEventの記録（無効な場合はnil）
+	events       *eventRecorder
+	// auditScanner 既存のリソースの違反の定期的な検出（無効な場合はnil）
+	auditScanner *audit.Scanner
+	// decisions リクエストごとの判定の決定ログ（無効な場合はnil）
+	decisions *decisionlog.Writer
+	// notifier 拒否の概要のHTTPエンドポイントへの通知（無効な場合はnil）
+	notifier *notifier
+	// cloudEvents 判定のCloudEventの送信（無効な場合はnil）
+	cloudEvents *cloudevents.Emitter
 	logger       *logging.Logger
 	config       *config.WebhookConfig
 	errorHandler *ErrorHandler
 }
 
-// createKubernetesClient creates a Kubernetes client with fallback configuration
-func createKubernetesClient(logger *logging.Logger) (kubernetes.Interface, error) {
-	// Try in-cluster config first
-	config, err := rest.InClusterConfig()
-	if err != nil {
+// createRESTConfig creates a Kubernetes client configuration with fallback
+func createRESTConfig(logger *logging.Logger, kubeconfigPath string) (*rest.Config, error) {
+	var (
+		config *rest.Config
+		err    error
+	)
+
+	// kubeconfigが明示的に指定された場合はそれのみを使用し、
+	// それ以外はクラスター内設定を優先してkubeconfigにフォールバック
+	if kubeconfigPath != "" {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig %s: %w", kubeconfigPath, err)
+		}
+		logger.Info("kubeconfigを使用します", map[string]interface{}{
+			"kubeconfig_path": kubeconfigPath,
+		})
+	} else if config, err = rest.InClusterConfig(); err != nil {
 		logger.Info("クラスター内設定の作成に失敗しました。kubeconfigを試行します", map[string]interface{}{
 			"error": err.Error(),
 		})
@@ -79,7 +121,13 @@ func createKubernetesClient(logger *logging.Logger) (kubernetes.Interface, error
 	// Set client configuration
 	config.QPS = 50
 	config.Burst = 100
+	// 処理中のadmissionのリクエストIDをAPIリクエストのヘッダーに付与し、API serverのログと対応付ける
+	config.Wrap(wrapRequestIDTransport)
+	return config, nil
+}
 
+// createKubernetesClient creates a Kubernetes client and tests the connection
+func createKubernetesClient(logger *logging.Logger, config *rest.Config) (kubernetes.Interface, error) {
 	client, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
@@ -106,126 +154,346 @@ func NewServerWithCA(port int, certFile, keyFile, caFile string) (*Server, error
 }
 
 // NewServerWithConfig creates a new webhook server instance with configuration
+// 引数で指定されたポート・証明書ファイルは設定より優先される
 func NewServerWithConfig(port int, certFile, keyFile, caFile string, cfg *config.WebhookConfig) (*Server, error) {
-	// デフォルト設定を作成（設定が提供されていない場合）
+	// 設定が提供されていない場合は読み込む（失敗した場合はデフォルト値で起動せずエラーを返す）
 	if cfg == nil {
-		loader := config.NewConfigLoader()
-		var err error
-		cfg, err = loader.LoadConfig()
+		loaded, err := config.NewConfigLoader().LoadConfig()
 		if err != nil {
-			// 設定読み込みに失敗した場合はデフォルト値を使用
-			cfg = &config.WebhookConfig{
-				Port:        port,
-				TLSCertFile: certFile,
-				TLSKeyFile:  keyFile,
-				Environment: "development",
-				LogLevel:    "info",
-				LogFormat:   "json",
-			}
-		}
-		// コマンドライン引数で指定された値を優先
-		if port != 0 {
-			cfg.Port = port
-		}
-		if certFile != "" {
-			cfg.TLSCertFile = certFile
-		}
-		if keyFile != "" {
-			cfg.TLSKeyFile = keyFile
+			return nil, fmt.Errorf("failed to load webhook configuration: %w", err)
 		}
+		cfg = loaded
+	} else {
+		copied := *cfg
+		cfg = &copied
+	}
+
+	if port != 0 {
+		cfg.Port = port
+	}
+	if certFile != "" {
+		cfg.TLSCertFile = certFile
+	}
+	if keyFile != "" {
+		cfg.TLSKeyFile = keyFile
+	}
+	if caFile != "" {
+		cfg.TLSCAFile = caFile
+	}
+
+	return NewServerFromConfig(cfg)
+}
+
+// certBootstrapTimeout 起動時の証明書の自動発行の期限
+const certBootstrapTimeout = 30 * time.Second
+
+// newCertBootstrapper 設定から証明書の自動発行を作成
+// ミューテーションが有効な場合は同名のMutatingWebhookConfigurationのcaBundleも設定する
+func newCertBootstrapper(client kubernetes.Interface, cfg *config.WebhookConfig) *cert.Bootstrapper {
+	bootstrapConfig := cert.BootstrapConfig{
+		Namespace:             cfg.TLSSecretNamespace,
+		ServiceName:           cfg.CertBootstrapService,
+		SecretName:            cfg.TLSSecretName,
+		ValidatingWebhookName: cfg.WebhookConfigurationName,
+	}
+	if cfg.MutationEnabled {
+		bootstrapConfig.MutatingWebhookName = cfg.WebhookConfigurationName
+	}
+	return cert.NewBootstrapper(client, bootstrapConfig)
+}
+
+// serviceAccountNamespaceFile Podのnamespaceを記録したサービスアカウントのファイル
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// leaderElectionNamespace PolicyReportを書き込むレプリカを選出するLeaseのnamespace
+// 設定されていない場合はPodのnamespaceを使用する
+func leaderElectionNamespace(cfg *config.WebhookConfig) (string, error) {
+	if cfg.LeaderElectionNamespace != "" {
+		return cfg.LeaderElectionNamespace, nil
+	}
+	data, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil || strings.TrimSpace(string(data)) == "" {
+		return "", fmt.Errorf("leader_election_namespace is required when running outside a pod: %v", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// NewServerFromConfig creates a new webhook server instance from a loaded configuration
+func NewServerFromConfig(cfg *config.WebhookConfig) (*Server, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("webhook configuration is required")
 	}
+	port, certFile, keyFile, caFile := cfg.Port, cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSCAFile
 
 	// Create structured logger
-	logger := logging.NewLogger("webhook-server")
+	logger := logging.NewLoggerWithSettings("webhook-server", cfg.LogLevel, cfg.LogFormat, cfg.Environment)
 
 	// Create Kubernetes client with fallback configuration
-	client, err := createKubernetesClient(logger)
+	restConfig, err := createRESTConfig(logger, cfg.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	client, err := createKubernetesClient(logger, restConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
 	}
 
 	// Create validator
-	v := validator.NewDeploymentHPAValidator(client)
+	v := validator.NewDeploymentHPAValidatorWithMinReplicas(client, int32(cfg.MinReplicas))
+
+	// 証明書の自動発行が有効な場合は、読み込む前にSecretの証明書とcaBundleを準備する
+	var bootstrapper *cert.Bootstrapper
+	if cfg.CertBootstrap {
+		bootstrapper = newCertBootstrapper(client, cfg)
+		ctx, cancel := context.WithTimeout(context.Background(), certBootstrapTimeout)
+		err := bootstrapper.Reconcile(ctx)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to bootstrap TLS certificate: %w", err)
+		}
+	}
 
-	// Create certificate manager
+	// Create certificate manager（Secretから読み込む場合はAPI経由で取得し、watchで再読み込みする）
 	certManager := cert.NewManager(certFile, keyFile, caFile)
+	if cfg.TLSSource == config.TLSSourceSecret {
+		certManager = cert.NewManagerFromSecret(client, cfg.TLSSecretNamespace, cfg.TLSSecretName)
+	}
 
 	// Load and validate TLS certificate
-	tlsCert, err := certManager.LoadCertificate()
-	if err != nil {
+	if _, err := certManager.LoadCertificate(); err != nil {
 		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
 	}
 
 	// Validate certificate chain if CA is provided
-	if caFile != "" {
+	if caFile != "" || cfg.TLSSource == config.TLSSourceSecret {
 		if err := certManager.ValidateCertificateChain(); err != nil {
 			log.Printf("警告: 証明書チェーンの検証に失敗しました: %v", err)
 		}
 	}
 
 	// Log certificate information and update metrics
-	if info, err := certManager.GetCertificateInfo(); err == nil {
-		logger.Info("証明書情報を取得しました", map[string]interface{}{
-			"subject":             info.Subject,
-			"not_after":           info.NotAfter.Format("2006-01-02 15:04:05"),
-			"days_until_expiry":   info.DaysUntilExpiry,
-			"dns_names":           info.DNSNames,
-		})
-		
-		if info.DaysUntilExpiry <= 30 {
-			logger.Warn("証明書の有効期限が近づいています", map[string]interface{}{
-				"days_until_expiry": info.DaysUntilExpiry,
-			})
-		}
-		
-		// 証明書の有効期限メトリクスを更新
-		metrics.UpdateCertificateExpiry(info.DaysUntilExpiry)
-	}
+	logCertificateInfo(logger, certManager, "証明書情報を取得しました")
 
 	// Create runtime scheme and codecs for admission requests
 	scheme := runtime.NewScheme()
 	codecs := serializer.NewCodecFactory(scheme)
 
-	// Create HTTP server with TLS
+	// Create HTTP server with TLS（TLSプロファイルで最小バージョン・暗号スイート・楕円曲線を決定）
+	tlsConfig, err := NewTLSConfig(cfg, certManager)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCipherSuiteCompatibility(tlsConfig, certManager.GetCurrentCertificate()); err != nil {
+		logger.Warn("証明書の鍵の種類に対応するTLS 1.2の暗号スイートがありません。TLS 1.3に対応していないクライアントは接続できません", map[string]interface{}{
+			"tls_profile": cfg.TLSProfile,
+			"error":       err.Error(),
+		})
+	}
+
+	// クライアント証明書による呼び出し元の認証（mTLS）
+	if cfg.ClientAuthEnabled {
+		authenticator, err := newClientAuthenticator(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		authenticator.apply(tlsConfig)
+		logger.Info("クライアント証明書による認証を有効にしました", map[string]interface{}{
+			"client_ca_file": cfg.ClientCAFile,
+			"allowed_names":  cfg.ClientAllowedNames,
+		})
+	}
 	mux := http.NewServeMux()
 	server := &http.Server{
-		Addr:      fmt.Sprintf(":%d", port),
-		Handler:   mux,
-		TLSConfig: &tls.Config{
-			Certificates: []tls.Certificate{tlsCert},
-			MinVersion:   tls.VersionTLS12,
-			CipherSuites: []uint16{
-				tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-				tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
-				tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-			},
-		},
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		TLSConfig:         tlsConfig,
 	}
 
 	// Create error handler
-	errorHandler := NewErrorHandler(cfg, logger)
+	errorHandler := NewErrorHandlerWithClient(cfg, logger, client)
 
 	s := &Server{
 		server:       server,
 		client:       client,
 		validator:    v,
+		mutator:      v,
 		scheme:       scheme,
 		codecs:       codecs,
 		certManager:  certManager,
+		bootstrapper: bootstrapper,
+		caBundleChecker: newCABundleChecker(client, certManager, cfg),
+		requesterNamespaces: newRequesterNamespaceLimiter(cfg),
 		logger:       logger,
 		config:       cfg,
 		errorHandler: errorHandler,
 	}
+	if cfg.EventsEnabled {
+		s.events = newEventRecorder(client)
+		logger.Info("拒否や警告付きの許可をEventとして記録します")
+	}
+	if cfg.DecisionLogEnabled {
+		decisions, err := decisionlog.NewWriter(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open decision log: %w", err)
+		}
+		s.decisions = decisions
+		logger.Info("リクエストごとの判定を決定ログに記録します", map[string]interface{}{
+			"path": cfg.DecisionLogPath,
+		})
+	}
+	if cfg.NotificationsEnabled {
+		s.notifier = newNotifier(cfg, client, logger)
+		logger.Info("拒否の概要を通知します", map[string]interface{}{
+			"format":       cfg.NotificationFormat,
+			"batch_window": cfg.NotificationBatchWindow.String(),
+		})
+	}
+	if cfg.CloudEventsEnabled {
+		s.cloudEvents = cloudevents.NewEmitter(cfg, logger)
+		logger.Info("判定をCloudEventとして送信します", map[string]interface{}{
+			"mode":          cfg.CloudEventsMode,
+			"all_decisions": cfg.CloudEventsAllDecisions,
+		})
+	}
+	if cfg.AuditEnabled {
+		s.auditScanner = audit.NewScanner(client, v, cfg, logger)
+		if cfg.PolicyReportEnabled {
+			dynamicClient, err := dynamic.NewForConfig(restConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+			}
+			s.auditScanner.SetReporter(audit.NewPolicyReportWriter(dynamicClient, client.Discovery(), logger))
+
+			// 複数のレプリカが同じPolicyReportを書き込まないよう、Leaseで書き込むレプリカを選出する
+			namespace, err := leaderElectionNamespace(cfg)
+			if err != nil {
+				return nil, err
+			}
+			lock, err := audit.NewLeaseLock(client, namespace)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create leader election lock: %w", err)
+			}
+			s.auditScanner.SetLeaderElection(lock)
+		}
+	}
 
 	// Register handlers with middleware
 	mux.HandleFunc("/validate", s.withMiddleware(s.handleValidate))
+	mux.HandleFunc("/mutate", s.withMiddleware(s.handleMutate))
+	// クライアント証明書を要求する場合、ヘルスチェックはMetricsPortでのみ提供する（プローブは証明書を持たないため）
+	if !cfg.ClientAuthEnabled {
+		s.registerHealthHandlers(mux)
+	}
+
+	// メトリクスとヘルスチェックはMetricsPortの平文HTTPでも提供
+	s.metricsServer = s.newMetricsServer()
+
+	return s, nil
+}
+
+// registerHealthHandlers ヘルスチェックのエンドポイントを登録（HealthEnabledが無効な場合は登録しない）
+func (s *Server) registerHealthHandlers(mux *http.ServeMux) {
+	if !s.config.HealthEnabled {
+		return
+	}
 	mux.HandleFunc("/health", s.withMiddleware(s.handleHealth))
 	mux.HandleFunc("/healthz", s.withMiddleware(s.handleHealthz))
 	mux.HandleFunc("/readyz", s.withMiddleware(s.handleReadiness))
 	mux.HandleFunc("/livez", s.withMiddleware(s.handleLiveness))
-	mux.Handle("/metrics", promhttp.Handler())
+}
 
-	return s, nil
+// newMetricsServer MetricsPortで/metrics、ヘルスチェック、監査スキャンの/violationsを提供するHTTPサーバーを作成
+// いずれも無効な場合はnilを返す
+func (s *Server) newMetricsServer() *http.Server {
+	if !s.config.MetricsEnabled && !s.config.HealthEnabled && s.auditScanner == nil {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	if s.config.MetricsEnabled {
+		mux.Handle("/metrics", promhttp.Handler())
+	}
+	s.registerHealthHandlers(mux)
+	if s.auditScanner != nil {
+		mux.Handle("/violations", s.auditScanner)
+	}
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", s.config.MetricsPort),
+		Handler:           mux,
+		ReadHeaderTimeout: s.config.ReadHeaderTimeout,
+		ReadTimeout:       s.config.ReadTimeout,
+		WriteTimeout:      s.config.WriteTimeout,
+		IdleTimeout:       s.config.IdleTimeout,
+	}
+}
+
+// startMetricsServer メトリクスサーバーのリッスンを開始（エラーはerrChに送信）
+func (s *Server) startMetricsServer(errCh chan<- error) error {
+	if s.metricsServer == nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", s.metricsServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on metrics port: %w", err)
+	}
+
+	// ヘルスチェックからはループバック経由で確認する
+	if _, port, err := net.SplitHostPort(listener.Addr().String()); err == nil {
+		s.metricsURL.Store(fmt.Sprintf("http://%s/metrics", net.JoinHostPort("127.0.0.1", port)))
+	}
+
+	s.logger.Info("メトリクスサーバーを開始しました", map[string]interface{}{
+		"address":         listener.Addr().String(),
+		"metrics_enabled": s.config.MetricsEnabled,
+		"health_enabled":  s.config.HealthEnabled,
+	})
+
+	go func() {
+		if err := s.metricsServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			errCh <- fmt.Errorf("failed to start metrics server: %w", err)
+		}
+	}()
+	return nil
+}
+
+// shutdown webhookサーバーとメトリクスサーバーを停止
+func (s *Server) shutdown(ctx context.Context) error {
+	var errs []error
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down metrics server: %w", err))
+		}
+	}
+	if s.server != nil {
+		if err := s.server.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down HTTPS server: %w", err))
+		}
+	}
+	// 処理中のリクエストの完了後に、送信待ちのEventの処理を停止する
+	if s.events != nil {
+		s.events.Shutdown()
+	}
+	// 処理中のリクエストの拒否を通知してから通知を停止する
+	if s.notifier != nil {
+		s.notifier.Close()
+	}
+	// 処理中のリクエストの判定を送信してからCloudEventsの送信を停止する
+	if s.cloudEvents != nil {
+		s.cloudEvents.Close()
+	}
+	// 処理中のリクエストの判定を書き込んでから決定ログを閉じる
+	if s.decisions != nil {
+		if err := s.decisions.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close decision log: %w", err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // Start starts the webhook server
@@ -234,15 +502,40 @@ func (s *Server) Start(ctx context.Context) error {
 	metrics.SetWebhookUp(true)
 	
 	// 証明書監視を開始
-	s.certManager.SetReloadCallback(s.reloadCertificate)
-	s.certManager.StartMonitoring(5 * time.Minute) // 5分間隔で監視
+	s.certManager.SetReloadCallback(s.onCertificateReloaded)
+	if s.caBundleChecker != nil {
+		s.certManager.SetBeforeSwapCallback(s.verifyCABundleBeforeSwap)
+	}
+	s.certManager.SetFileWatch(s.config.CertWatchEnabled)
+	s.certManager.StartMonitoring(s.certCheckInterval())
 	defer s.certManager.StopMonitoring()
 	
 	s.logger.Info("証明書監視を開始しました", map[string]interface{}{
-		"check_interval": "5m",
+		"check_interval": s.certCheckInterval().String(),
+		"file_watch":     s.config.CertWatchEnabled,
 	})
+
+	// 自動発行した証明書を有効期限の前に更新（更新したSecretはwatchで再読み込みされる）
+	if s.bootstrapper != nil {
+		go s.bootstrapper.Run(ctx, cert.DefaultBootstrapCheckInterval)
+	}
+
+	// 自身のwebhook設定のcaBundleでサーバー証明書を検証できるかを定期的に確認
+	if s.caBundleChecker != nil {
+		go s.runCABundleCheck(ctx)
+	}
+
+	// webhookの導入前から存在する違反を検出
+	if s.auditScanner != nil {
+		go s.auditScanner.Run(ctx)
+	}
 	
-	errCh := make(chan error, 1)
+	errCh := make(chan error, 2)
+
+	if err := s.startMetricsServer(errCh); err != nil {
+		metrics.SetWebhookUp(false)
+		return err
+	}
 
 	go func() {
 		if err := s.server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
@@ -250,21 +543,26 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 	}()
 
+	// どちらかのサーバーが停止した場合は両方を停止する
 	select {
 	case err := <-errCh:
 		metrics.SetWebhookUp(false)
+		if shutdownErr := s.shutdown(context.Background()); shutdownErr != nil {
+			s.logger.Warn("サーバーの停止に失敗しました", map[string]interface{}{
+				"error": shutdownErr.Error(),
+			})
+		}
 		return err
 	case <-ctx.Done():
 		metrics.SetWebhookUp(false)
-		return s.server.Shutdown(context.Background())
+		return s.shutdown(context.Background())
 	}
 }
 
 // handleValidate handles validation requests
 func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
-	// リクエストIDを生成してコンテキストに設定
+	// AdmissionReviewを解析するまでは、生成したリクエストIDを使用する
 	requestID := logging.GenerateRequestID()
-	ctx := logging.ContextWithRequestID(r.Context(), requestID)
 	requestLogger := s.logger.WithRequestID(requestID)
 
 	requestLogger.Info("バリデーションリクエストを受信しました", map[string]interface{}{
@@ -279,15 +577,72 @@ func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
 	// Set response headers
 	w.Header().Set("Content-Type", "application/json")
 
+	admissionReview, ok := s.decodeAdmissionReview(w, r, requestLogger, requestMetrics)
+	if !ok {
+		return
+	}
+
+	// リソースと呼び出し元の情報を取得
+	req := admissionReview.Request
+	requestMetrics.ResourceType = req.Kind.Kind
+
+	// AdmissionRequestのUIDをリクエストIDとしてコンテキストに設定
+	requestID = admissionRequestID(req, requestID)
+	ctx := logging.ContextWithRequestID(r.Context(), requestID)
+	requestLogger = s.logger.WithRequestID(requestID)
+
+	requestLogger.Info("リソースのバリデーションを開始します", decisionLogFields(req))
+
+	// Validate the request（処理期限を超過した場合は失敗ポリシーに従う）
+	admissionResponse := s.withAdmissionDeadline(ctx, s.admissionDeadline(r), req, s.allowOnDeadline(), s.validateAdmissionRequest)
+	withRequestIDAnnotation(admissionResponse, requestID)
+
+	if !s.writeAdmissionReview(w, admissionResponse, requestLogger, requestMetrics) {
+		return
+	}
+	
+	// ログとメトリクス記録
+	fields := decisionLogFields(req)
+	fields["allowed"] = admissionResponse.Allowed
+	if admissionResponse.Allowed {
+		requestLogger.Info("バリデーションが成功しました", fields)
+		requestMetrics.RecordSuccess()
+	} else {
+		// バリデーションエラーの詳細を記録
+		errorType := "validation_failed"
+		if admissionResponse.Result != nil && admissionResponse.Result.Code >= 500 {
+			errorType = "system_error"
+		}
+		
+		fields["error_type"] = errorType
+		fields["message"] = admissionResponse.Result.Message
+		requestLogger.Warn("バリデーションが失敗しました", fields)
+		requestMetrics.RecordError(errorType)
+		s.recordDenial(req, errorType)
+	}
+	s.recordDecision(req, admissionResponse, decisionlog.ModeEnforce, requestMetrics.StartTime)
+}
+
+// decodeAdmissionReview reads and parses the AdmissionReview from the request body
+func (s *Server) decodeAdmissionReview(w http.ResponseWriter, r *http.Request, requestLogger *logging.RequestLogger, requestMetrics *metrics.RequestMetrics) (*admissionv1.AdmissionReview, bool) {
 	// Read request body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			requestLogger.Warn("リクエストボディが最大サイズを超えています", map[string]interface{}{
+				"limit": maxBytesErr.Limit,
+			})
+			requestMetrics.RecordError("request_body_too_large")
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return nil, false
+		}
 		requestLogger.Error("リクエストボディの読み込みに失敗しました", map[string]interface{}{
 			"error": err.Error(),
 		})
 		requestMetrics.RecordError("request_body_read_error")
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 	defer r.Body.Close()
 
@@ -299,28 +654,22 @@ func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
 		})
 		requestMetrics.RecordError("admission_review_parse_error")
 		http.Error(w, "Failed to parse admission review", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
-	// リソース情報を取得
-	var resourceType, resourceName, resourceNamespace string
-	if admissionReview.Request != nil {
-		resourceType = admissionReview.Request.Kind.Kind
-		resourceName = admissionReview.Request.Name
-		resourceNamespace = admissionReview.Request.Namespace
-		requestMetrics.ResourceType = resourceType
+	// requestを含まないAdmissionReviewは処理しない
+	if admissionReview.Request == nil {
+		requestLogger.Warn("AdmissionReviewにrequestが含まれていません")
+		requestMetrics.RecordError("admission_review_missing_request")
+		http.Error(w, "Admission review has no request", http.StatusBadRequest)
+		return nil, false
 	}
 
-	requestLogger.Info("リソースのバリデーションを開始します", map[string]interface{}{
-		"resource_type": resourceType,
-		"resource_name": resourceName,
-		"namespace":     resourceNamespace,
-		"operation":     string(admissionReview.Request.Operation),
-	})
-
-	// Validate the request
-	admissionResponse := s.validateAdmissionRequest(ctx, admissionReview.Request)
+	return admissionReview, true
+}
 
+// writeAdmissionReview wraps the response in an AdmissionReview and writes it
+func (s *Server) writeAdmissionReview(w http.ResponseWriter, admissionResponse *admissionv1.AdmissionResponse, requestLogger *logging.RequestLogger, requestMetrics *metrics.RequestMetrics) bool {
 	// Create response
 	responseReview := &admissionv1.AdmissionReview{
 		TypeMeta: metav1.TypeMeta{
@@ -338,38 +687,12 @@ func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
 		})
 		requestMetrics.RecordError("response_marshal_error")
 		http.Error(w, "Failed to create response", http.StatusInternalServerError)
-		return
+		return false
 	}
 
 	w.WriteHeader(http.StatusOK)
 	w.Write(responseBytes)
-	
-	// ログとメトリクス記録
-	if admissionResponse.Allowed {
-		requestLogger.Info("バリデーションが成功しました", map[string]interface{}{
-			"resource_type": resourceType,
-			"resource_name": resourceName,
-			"namespace":     resourceNamespace,
-			"allowed":       true,
-		})
-		requestMetrics.RecordSuccess()
-	} else {
-		// バリデーションエラーの詳細を記録
-		errorType := "validation_failed"
-		if admissionResponse.Result != nil && admissionResponse.Result.Code >= 500 {
-			errorType = "system_error"
-		}
-		
-		requestLogger.Warn("バリデーションが失敗しました", map[string]interface{}{
-			"resource_type": resourceType,
-			"resource_name": resourceName,
-			"namespace":     resourceNamespace,
-			"allowed":       false,
-			"error_type":    errorType,
-			"message":       admissionResponse.Result.Message,
-		})
-		requestMetrics.RecordError(errorType)
-	}
+	return true
 }
 
 // validateAdmissionRequest validates an admission request and returns an admission response
@@ -408,15 +731,8 @@ func (s *Server) validateAdmissionRequest(ctx context.Context, req *admissionv1.
 				"Deploymentの解析に失敗しました",
 			).WithInternalError(parseErr).WithContext(requestID, "Deployment", req.Name, req.Namespace)
 		} else {
-			result := s.validator.ValidateResource(ctx, "Deployment", deployment)
-			if !result.Allowed {
-				// ValidationResultからエラーを作成
-				err = validator.NewWebhookError(
-					validator.ErrorTypeValidation,
-					validator.CodeDeploymentHPAConflict,
-					result.Message,
-				).WithContext(requestID, "Deployment", req.Name, req.Namespace)
-			}
+			// フィールド情報を保持するため、WebhookErrorをそのまま使用
+			err = withRequestContext(s.validator.ValidateDeployment(ctx, deployment), requestID)
 		}
 
 	case "HorizontalPodAutoscaler":
@@ -430,16 +746,8 @@ func (s *Server) validateAdmissionRequest(ctx context.Context, req *admissionv1.
 				validator.CodeInvalidResource,
 				"HPAの解析に失敗しました",
 			).WithInternalError(parseErr).WithContext(requestID, "HorizontalPodAutoscaler", req.Name, req.Namespace)
-		} else {
-			result := s.validator.ValidateResource(ctx, "HorizontalPodAutoscaler", hpa)
-			if !result.Allowed {
-				// ValidationResultからエラーを作成
-				err = validator.NewWebhookError(
-					validator.ErrorTypeValidation,
-					validator.CodeHPASingleReplica,
-					result.Message,
-				).WithContext(requestID, "HorizontalPodAutoscaler", req.Name, req.Namespace)
-			}
+		} else if !s.acceptsMutatedHPA(req, hpa) {
+			err = withRequestContext(s.validator.ValidateHPA(ctx, hpa), requestID)
 		}
 
 	default:
@@ -453,7 +761,16 @@ func (s *Server) validateAdmissionRequest(ctx context.Context, req *admissionv1.
 		}
 	}
 
-	return s.errorHandler.HandleError(ctx, err, req)
+	return s.handleValidationResult(ctx, err, req)
+}
+
+// withRequestContext stamps the request ID onto a WebhookError returned by the validator
+func withRequestContext(err error, requestID string) error {
+	if webhookErr, ok := err.(*validator.WebhookError); ok {
+		webhookErr.RequestID = requestID
+		return webhookErr
+	}
+	return err
 }
 
 // withMiddleware wraps handlers with common middleware
@@ -472,8 +789,8 @@ func (s *Server) withMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 		w.Header().Set("X-Frame-Options", "DENY")
 		w.Header().Set("X-XSS-Protection", "1; mode=block")
 		
-		// Validate request method for validation endpoint
-		if r.URL.Path == "/validate" && r.Method != http.MethodPost {
+		// Validate request method for admission endpoints
+		if isAdmissionPath(r.URL.Path) && r.Method != http.MethodPost {
 			s.logger.Warn("許可されていないHTTPメソッドです", map[string]interface{}{
 				"method": r.Method,
 				"path":   r.URL.Path,
@@ -481,6 +798,21 @@ func (s *Server) withMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
+
+		if isAdmissionPath(r.URL.Path) {
+			// AdmissionReviewはJSONのみ受け付ける
+			if !isJSONContentType(r.Header.Get("Content-Type")) {
+				s.logger.Warn("サポートされていないContent-Typeです", map[string]interface{}{
+					"content_type": r.Header.Get("Content-Type"),
+					"path":         r.URL.Path,
+				})
+				http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+				return
+			}
+
+			// リクエストボディのサイズを制限
+			r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestBodyBytes())
+		}
 		
 		// Call the actual handler
 		handler(w, r)
@@ -503,6 +835,25 @@ type HealthStatus struct {
 	Environment string                 `json:"environment,omitempty"`
 }
 
+// isAdmissionPath AdmissionReviewを受け付けるエンドポイントかどうか
+func isAdmissionPath(path string) bool {
+	return path == "/validate" || path == "/mutate"
+}
+
+// isJSONContentType Content-Typeがapplication/jsonかどうか（charsetなどのパラメータは無視）
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// maxRequestBodyBytes リクエストボディの最大サイズ（未設定の場合は既定値）
+func (s *Server) maxRequestBodyBytes() int64 {
+	if s.config.MaxRequestBodyBytes > 0 {
+		return s.config.MaxRequestBodyBytes
+	}
+	return config.DefaultMaxRequestBodyBytes
+}
+
 // handleHealth handles health check requests with detailed status
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	health := s.getDetailedHealthStatus(r.Context())
@@ -573,22 +924,18 @@ func (s *Server) handleLiveness(w http.ResponseWriter, r *http.Request) {
 }
 
 // ReloadCertificate reloads the TLS certificate
+// 新しい証明書は証明書マネージャーが差し替え、以降のハンドシェイクで使用される
 func (s *Server) ReloadCertificate() error {
 	s.logger.Info("証明書を再読み込み中...")
-	
-	// Load new certificate
-	newCert, err := s.certManager.LoadCertificate()
-	if err != nil {
+
+	if _, err := s.certManager.LoadCertificate(); err != nil {
 		s.logger.Error("証明書の再読み込みに失敗しました", map[string]interface{}{
 			"error": err.Error(),
 		})
 		return fmt.Errorf("証明書の再読み込みに失敗しました: %w", err)
 	}
-	
-	// Update server TLS config
-	s.server.TLSConfig.Certificates = []tls.Certificate{newCert}
-	
-	s.logger.Info("証明書の再読み込みが完了しました")
+
+	logCertificateInfo(s.logger, s.certManager, "証明書の再読み込みが完了しました")
 	return nil
 }
 
@@ -658,6 +1005,15 @@ func (s *Server) getDetailedHealthStatus(ctx context.Context) HealthStatus {
 		}
 	}
 	
+	// webhook設定のcaBundleとサーバー証明書の一致チェック
+	if caBundleStatus, matched := s.caBundleHealth(); caBundleStatus != nil {
+		status.Components["ca_bundle"] = caBundleStatus
+		if !matched {
+			overallHealthy = false
+			messages = append(messages, "サーバー証明書がwebhook設定のcaBundleと一致しません")
+		}
+	}
+	
 	// バリデーター機能チェック
 	validatorStatus := s.checkValidatorHealth(ctx)
 	status.Components["validator"] = validatorStatus
@@ -806,8 +1162,22 @@ func (s *Server) checkValidatorHealth(ctx context.Context) map[string]interface{
 
 // checkMetricsHealth はメトリクス機能の健康状態をチェック
 func (s *Server) checkMetricsHealth() map[string]interface{} {
+	if s.config == nil || !s.config.MetricsEnabled {
+		return map[string]interface{}{
+			"status": "disabled",
+		}
+	}
+
+	metricsURL, ok := s.metricsURL.Load().(string)
+	if !ok {
+		return map[string]interface{}{
+			"status": "warning",
+			"error":  "metrics server not started",
+		}
+	}
+
 	// メトリクスエンドポイントの簡単なテスト
-	req, err := http.NewRequest("GET", "http://localhost:8080/metrics", nil)
+	req, err := http.NewRequest("GET", metricsURL, nil)
 	if err != nil {
 		return map[string]interface{}{
 			"status": "warning",
@@ -837,34 +1207,49 @@ func (s *Server) checkMetricsHealth() map[string]interface{} {
 
 	return map[string]interface{}{
 		"status": "healthy",
-		"endpoint": "/metrics",
+		"endpoint": metricsURL,
 	}
 }
 
-// reloadCertificate reloads the TLS certificate for the server
-func (s *Server) reloadCertificate(newCert tls.Certificate) {
-	s.logger.Info("TLS証明書を再読み込みしています", nil)
-	
-	// サーバーのTLS設定を更新
-	if s.server.TLSConfig != nil {
-		s.server.TLSConfig.Certificates = []tls.Certificate{newCert}
-		s.logger.Info("TLS証明書の再読み込みが完了しました", nil)
-		
-		// 証明書情報を更新
-		if info, err := s.certManager.GetCertificateInfo(); err == nil {
-			s.logger.Info("新しい証明書情報", map[string]interface{}{
-				"subject":             info.Subject,
-				"not_after":           info.NotAfter.Format("2006-01-02 15:04:05"),
-				"days_until_expiry":   info.DaysUntilExpiry,
-				"dns_names":           info.DNSNames,
-			})
-			
-			// メトリクスを更新
-			metrics.UpdateCertificateExpiry(info.DaysUntilExpiry)
-		}
-	} else {
-		s.logger.Error("TLS設定が見つかりません。証明書の再読み込みに失敗しました", nil)
+// certCheckInterval 証明書ファイルの変更を確認する間隔（未設定の場合は1分）
+func (s *Server) certCheckInterval() time.Duration {
+	if s.config.CertCheckInterval > 0 {
+		return s.config.CertCheckInterval
 	}
+	return time.Minute
+}
+
+// onCertificateReloaded 証明書マネージャーが証明書を再読み込みした際に呼ばれる
+func (s *Server) onCertificateReloaded(tls.Certificate) {
+	logCertificateInfo(s.logger, s.certManager, "TLS証明書の再読み込みが完了しました")
+
+	// 差し替え後の状態をヘルスチェックとメトリクスに反映
+	s.checkCABundle(context.Background())
+}
+
+// logCertificateInfo 現在の証明書情報をログに記録し、有効期限メトリクスを更新
+func logCertificateInfo(logger *logging.Logger, certManager *cert.Manager, message string) {
+	info, err := certManager.GetCertificateInfo()
+	if err != nil {
+		return
+	}
+
+	logger.Info(message, map[string]interface{}{
+		"subject":           info.Subject,
+		"not_after":         info.NotAfter.Format("2006-01-02 15:04:05"),
+		"days_until_expiry": info.DaysUntilExpiry,
+		"dns_names":         info.DNSNames,
+		"key_algorithm":     info.PublicKeyAlgorithm,
+	})
+
+	if info.DaysUntilExpiry <= 30 {
+		logger.Warn("証明書の有効期限が近づいています", map[string]interface{}{
+			"days_until_expiry": info.DaysUntilExpiry,
+		})
+	}
+
+	// 証明書の有効期限メトリクスを更新
+	metrics.UpdateCertificateExpiry(info.DaysUntilExpiry)
 }
 
 // GetCertificateInfo returns current certificate information
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s-deployment-hpa-validator/internal/config"
+)
+
+// newFuzzTestServer ファズテスト用のサーバーを作成
+func newFuzzTestServer() *Server {
+	return newTestServer(fake.NewSimpleClientset(), &config.WebhookConfig{
+		Environment:         "development",
+		MutationEnabled:     true,
+		MaxRequestBodyBytes: 64 * 1024,
+	})
+}
+
+// addAdmissionSeeds 正常なAdmissionReviewと典型的な不正入力をシードとして追加
+func addAdmissionSeeds(f *testing.F) {
+	for _, req := range []*admissionv1.AdmissionRequest{
+		createDeploymentAdmissionRequest("test-deployment", "default", 1),
+		createDeploymentAdmissionRequest("test-deployment", "default", 3),
+		createHPAAdmissionRequest("test-hpa", "default", "test-deployment"),
+	} {
+		body, err := json.Marshal(&admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request:  req,
+		})
+		if err != nil {
+			f.Fatalf("Failed to marshal admission review: %v", err)
+		}
+		f.Add(body, "application/json")
+	}
+
+	f.Add([]byte(`{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview"}`), "application/json")
+	f.Add([]byte(`{"request":null}`), "application/json; charset=utf-8")
+	f.Add([]byte(`{"request":{"uid":"x","kind":{"kind":"Deployment"},"object":null}}`), "application/json")
+	f.Add([]byte(`{"request":{"uid":"x","kind":{"kind":"HorizontalPodAutoscaler"},"object":"not-an-object"}}`), "application/json")
+	f.Add([]byte(`{"request":`), "application/json")
+	f.Add([]byte(`[]`), "application/json")
+	f.Add([]byte(`{}`), "text/plain")
+	f.Add([]byte{}, "")
+}
+
+// checkAdmissionResponse ハンドラーのレスポンスが想定されたステータスと形式であることを検証
+func checkAdmissionResponse(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+
+	switch w.Code {
+	case http.StatusOK:
+		var review admissionv1.AdmissionReview
+		if err := json.Unmarshal(w.Body.Bytes(), &review); err != nil {
+			t.Fatalf("Response is not a valid AdmissionReview: %v", err)
+		}
+		if review.Response == nil {
+			t.Fatal("AdmissionReview response is missing")
+		}
+		if !review.Response.Allowed && review.Response.Result == nil {
+			t.Error("Denied response has no result")
+		}
+	case http.StatusBadRequest, http.StatusUnsupportedMediaType, http.StatusRequestEntityTooLarge:
+	default:
+		t.Fatalf("Unexpected status %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func FuzzServer_handleValidate(f *testing.F) {
+	addAdmissionSeeds(f)
+	server := newFuzzTestServer()
+	handler := server.withMiddleware(server.handleValidate)
+
+	f.Fuzz(func(t *testing.T, body []byte, contentType string) {
+		req := httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		checkAdmissionResponse(t, w)
+	})
+}
+
+func FuzzServer_handleMutate(f *testing.F) {
+	addAdmissionSeeds(f)
+	server := newFuzzTestServer()
+	handler := server.withMiddleware(server.handleMutate)
+
+	f.Fuzz(func(t *testing.T, body []byte, contentType string) {
+		req := httptest.NewRequest(http.MethodPost, "/mutate", bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		checkAdmissionResponse(t, w)
+	})
+}
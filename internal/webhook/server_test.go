@@ -14,8 +14,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 
+	"k8s-deployment-hpa-validator/internal/audit"
 	"k8s-deployment-hpa-validator/internal/config"
 	"k8s-deployment-hpa-validator/internal/logging"
 	"k8s-deployment-hpa-validator/internal/metrics"
@@ -27,6 +29,37 @@ func init() {
 	metrics.EnableTestMode()
 }
 
+// testServerOption テスト用のサーバーに追加のフィールド（Eventの記録など）を設定する
+type testServerOption func(*Server)
+
+// newTestServer clientを使って検証・自動修正するテスト用のサーバーを作成
+func newTestServer(client kubernetes.Interface, cfg *config.WebhookConfig, options ...testServerOption) *Server {
+	logger := logging.NewLogger("test-webhook")
+	v := validator.NewDeploymentHPAValidator(client)
+	server := &Server{
+		client:       client,
+		validator:    v,
+		mutator:      v,
+		logger:       logger,
+		config:       cfg,
+		errorHandler: NewErrorHandler(cfg, logger),
+	}
+	for _, option := range options {
+		option(server)
+	}
+	return server
+}
+
+// newHPATestClient default/test-deploymentを対象とするHPA（test-hpa）が存在するクラスターのクライアントを作成
+func newHPATestClient() *fake.Clientset {
+	return fake.NewSimpleClientset(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-hpa", Namespace: "default", UID: "hpa-uid"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment"},
+		},
+	})
+}
+
 func TestServer_validateAdmissionRequest(t *testing.T) {
 	// Create fake Kubernetes client
 	fakeClient := fake.NewSimpleClientset()
@@ -256,6 +289,7 @@ func TestServer_withMiddleware(t *testing.T) {
 	})
 	
 	req := httptest.NewRequest("POST", "/validate", nil)
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	
 	handler(w, req)
@@ -331,6 +365,54 @@ func TestServer_handleValidate(t *testing.T) {
 	}
 }
 
+func TestServer_validateAdmissionRequest_FieldCauses(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-hpa", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "test-deployment"},
+		},
+	})
+
+	cfg := &config.WebhookConfig{
+		Environment: "development",
+		LogLevel:    "info",
+		LogFormat:   "json",
+	}
+	logger := logging.NewLogger("test-webhook")
+	server := &Server{
+		client:       fakeClient,
+		validator:    validator.NewDeploymentHPAValidator(fakeClient),
+		logger:       logger,
+		config:       cfg,
+		errorHandler: NewErrorHandler(cfg, logger),
+	}
+
+	response := server.validateAdmissionRequest(context.Background(), createDeploymentAdmissionRequest("test-deployment", "default", 1))
+	if response.Allowed {
+		t.Fatal("Expected the request to be denied")
+	}
+	if response.Result.Details == nil || len(response.Result.Details.Causes) == 0 {
+		t.Fatalf("Expected field causes, got %+v", response.Result.Details)
+	}
+	if cause := response.Result.Details.Causes[0]; cause.Field != "spec.replicas" || !strings.Contains(cause.Message, "test-hpa") {
+		t.Errorf("Unexpected cause: %+v", cause)
+	}
+
+	// 開発環境では修正案がDetailsと監査ログの両方に含まれる
+	var fixCauses []metav1.StatusCause
+	for _, cause := range response.Result.Details.Causes {
+		if cause.Type == CauseTypeSuggestedFix {
+			fixCauses = append(fixCauses, cause)
+		}
+	}
+	if len(fixCauses) != 1 || !strings.Contains(fixCauses[0].Message, "kubectl patch deployment test-deployment -n default") {
+		t.Errorf("Unexpected suggested fix causes: %+v", fixCauses)
+	}
+	if _, ok := response.AuditAnnotations[AuditAnnotationSuggestedFixes]; !ok {
+		t.Errorf("Expected %s audit annotation, got %v", AuditAnnotationSuggestedFixes, response.AuditAnnotations)
+	}
+}
+
 // Helper function to create deployment admission request
 func createDeploymentAdmissionRequest(name, namespace string, replicas int32) *admissionv1.AdmissionRequest {
 	deployment := &appsv1.Deployment{
@@ -392,4 +474,156 @@ func createHPAAdmissionRequest(name, namespace, targetName string) *admissionv1.
 			Raw: hpaBytes,
 		},
 	}
-}
\ No newline at end of file
+}
+func TestServer_newMetricsServer(t *testing.T) {
+	tests := []struct {
+		name           string
+		metricsEnabled bool
+		healthEnabled  bool
+		expectServer   bool
+		expectMetrics  bool
+		expectHealth   bool
+		auditEnabled   bool
+	}{
+		{name: "metrics and health", metricsEnabled: true, healthEnabled: true, expectServer: true, expectMetrics: true, expectHealth: true},
+		{name: "metrics only", metricsEnabled: true, expectServer: true, expectMetrics: true},
+		{name: "health only", healthEnabled: true, expectServer: true, expectHealth: true},
+		{name: "audit only", auditEnabled: true, expectServer: true},
+		{name: "both disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.WebhookConfig{
+				Environment:    "development",
+				MetricsEnabled: tt.metricsEnabled,
+				HealthEnabled:  tt.healthEnabled,
+				MetricsPort:    9090,
+			}
+			server := &Server{logger: logging.NewLogger("test-webhook"), config: cfg}
+			if tt.auditEnabled {
+				server.auditScanner = audit.NewScanner(fake.NewSimpleClientset(), validator.NewDeploymentHPAValidator(fake.NewSimpleClientset()), cfg, server.logger)
+			}
+
+			metricsServer := server.newMetricsServer()
+			if (metricsServer != nil) != tt.expectServer {
+				t.Fatalf("newMetricsServer() = %v, expectServer %v", metricsServer, tt.expectServer)
+			}
+			if metricsServer == nil {
+				return
+			}
+			if metricsServer.Addr != ":9090" {
+				t.Errorf("Addr = %s, want :9090", metricsServer.Addr)
+			}
+
+			for path, expected := range map[string]bool{"/metrics": tt.expectMetrics, "/livez": tt.expectHealth} {
+				w := httptest.NewRecorder()
+				metricsServer.Handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+				if (w.Code == http.StatusOK) != expected {
+					t.Errorf("GET %s status = %d, expected served = %v", path, w.Code, expected)
+				}
+			}
+			// 最初のスキャンの前は503を返す
+			w := httptest.NewRecorder()
+			metricsServer.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/violations", nil))
+			if (w.Code != http.StatusNotFound) != tt.auditEnabled {
+				t.Errorf("GET /violations status = %d, expected served = %v", w.Code, tt.auditEnabled)
+			}
+		})
+	}
+}
+
+func TestServer_MetricsListenerLifecycle(t *testing.T) {
+	cfg := &config.WebhookConfig{
+		Environment:    "development",
+		MetricsEnabled: true,
+		HealthEnabled:  true,
+		MetricsPort:    0, // 空いているポートを使用
+	}
+	server := &Server{logger: logging.NewLogger("test-webhook"), config: cfg}
+
+	// 起動前は警告
+	if status := server.checkMetricsHealth(); status["status"] != "warning" {
+		t.Errorf("checkMetricsHealth() before start = %v, want warning", status)
+	}
+
+	server.metricsServer = server.newMetricsServer()
+	errCh := make(chan error, 1)
+	if err := server.startMetricsServer(errCh); err != nil {
+		t.Fatalf("startMetricsServer() error = %v", err)
+	}
+
+	if status := server.checkMetricsHealth(); status["status"] != "healthy" {
+		t.Errorf("checkMetricsHealth() after start = %v, want healthy", status)
+	}
+
+	if err := server.shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+	if status := server.checkMetricsHealth(); status["status"] != "warning" {
+		t.Errorf("checkMetricsHealth() after shutdown = %v, want warning", status)
+	}
+
+	// メトリクスが無効な場合はdisabled
+	server.config = &config.WebhookConfig{HealthEnabled: true}
+	if status := server.checkMetricsHealth(); status["status"] != "disabled" {
+		t.Errorf("checkMetricsHealth() with metrics disabled = %v, want disabled", status)
+	}
+}
+
+func TestServer_withMiddleware_RequestValidation(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	cfg := &config.WebhookConfig{
+		Environment:         "development",
+		MaxRequestBodyBytes: 1024,
+	}
+	logger := logging.NewLogger("test-webhook")
+	server := &Server{
+		client:       fakeClient,
+		validator:    validator.NewDeploymentHPAValidator(fakeClient),
+		logger:       logger,
+		config:       cfg,
+		errorHandler: NewErrorHandler(cfg, logger),
+	}
+	handler := server.withMiddleware(server.handleValidate)
+
+	validBody, err := json.Marshal(&admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  createHPAAdmissionRequest("test-hpa", "default", "test-deployment"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal admission review: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{name: "valid request", contentType: "application/json", body: string(validBody), expectedStatus: http.StatusOK},
+		{name: "content type with charset", contentType: "application/json; charset=utf-8", body: string(validBody), expectedStatus: http.StatusOK},
+		{name: "missing content type", contentType: "", body: string(validBody), expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "non-JSON content type", contentType: "application/yaml", body: string(validBody), expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "body too large", contentType: "application/json", body: `{"request":"` + strings.Repeat("a", 2048) + `"}`, expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "missing request", contentType: "application/json", body: `{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview"}`, expectedStatus: http.StatusBadRequest},
+		{name: "null request", contentType: "application/json", body: `{"request":null}`, expectedStatus: http.StatusBadRequest},
+		{name: "malformed JSON", contentType: "application/json", body: `{"request":`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("status = %v, expected %v (body: %s)", w.Code, tt.expectedStatus, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"k8s-deployment-hpa-validator/internal/cert"
+	"k8s-deployment-hpa-validator/internal/config"
+)
+
+// NewTLSConfig creates the TLS configuration for the webhook server
+// TLSプロファイルからwebhookサーバーのTLS設定を作成
+// 証明書はハンドシェイクごとに証明書マネージャーから取得する（再読み込み時はマネージャー側でアトミックに差し替え）
+func NewTLSConfig(cfg *config.WebhookConfig, certManager *cert.Manager) (*tls.Config, error) {
+	settings, err := cfg.ResolveTLSSettings()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS profile: %w", err)
+	}
+
+	return &tls.Config{
+		GetCertificate:   certManager.GetCertificate,
+		MinVersion:       settings.MinVersion,
+		CipherSuites:     settings.CipherSuites,
+		CurvePreferences: settings.CurvePreferences,
+	}, nil
+}
+
+// checkCipherSuiteCompatibility TLS 1.2で証明書の鍵の種類に対応する暗号スイートがあるかを確認
+// ECDSA・Ed25519の証明書にはECDHE_ECDSA、RSAの証明書にはECDHE_RSAの暗号スイートが必要
+// （TLS 1.3は暗号スイートが鍵の種類に依存しないため、最小バージョンが1.3の場合は確認しない）
+func checkCipherSuiteCompatibility(tlsConfig *tls.Config, certificate *tls.Certificate) error {
+	if certificate == nil || tlsConfig.MinVersion >= tls.VersionTLS13 || len(tlsConfig.CipherSuites) == 0 {
+		return nil
+	}
+
+	var keyType string
+	switch certificate.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		keyType = "RSA"
+	case *ecdsa.PrivateKey, ed25519.PrivateKey:
+		keyType = "ECDSA"
+	default:
+		return nil
+	}
+
+	for _, id := range tlsConfig.CipherSuites {
+		if strings.HasPrefix(tls.CipherSuiteName(id), "TLS_ECDHE_"+keyType+"_") {
+			return nil
+		}
+	}
+	return fmt.Errorf("TLS_ECDHE_%s_* の暗号スイートが設定されていません", keyType)
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"testing"
+)
+
+func TestCheckCipherSuiteCompatibility(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key: %v", err)
+	}
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+
+	rsaOnly := []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}
+	ecdsaOnly := []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}
+
+	tests := []struct {
+		name         string
+		key          interface{}
+		minVersion   uint16
+		cipherSuites []uint16
+		expectError  bool
+	}{
+		{name: "RSA key with RSA suites", key: rsaKey, minVersion: tls.VersionTLS12, cipherSuites: rsaOnly},
+		{name: "ECDSA key with RSA suites", key: ecdsaKey, minVersion: tls.VersionTLS12, cipherSuites: rsaOnly, expectError: true},
+		{name: "Ed25519 key with ECDSA suites", key: ed25519Key, minVersion: tls.VersionTLS12, cipherSuites: ecdsaOnly},
+		{name: "RSA key with ECDSA suites", key: rsaKey, minVersion: tls.VersionTLS12, cipherSuites: ecdsaOnly, expectError: true},
+		{name: "TLS 1.3 only", key: ecdsaKey, minVersion: tls.VersionTLS13, cipherSuites: rsaOnly},
+		{name: "default cipher suites", key: ecdsaKey, minVersion: tls.VersionTLS12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig := &tls.Config{MinVersion: tt.minVersion, CipherSuites: tt.cipherSuites}
+			err := checkCipherSuiteCompatibility(tlsConfig, &tls.Certificate{PrivateKey: tt.key})
+			if (err != nil) != tt.expectError {
+				t.Errorf("checkCipherSuiteCompatibility() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
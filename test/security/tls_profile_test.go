@@ -0,0 +1,225 @@
+//go:build security
+// +build security
+
+package security
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s-deployment-hpa-validator/internal/cert"
+	"k8s-deployment-hpa-validator/internal/config"
+	"k8s-deployment-hpa-validator/internal/webhook"
+)
+
+// generateKeyPairFiles 指定した鍵の種類の自己署名サーバー証明書をファイルに書き出す
+func generateKeyPairFiles(t *testing.T, keyType string) (string, string) {
+	t.Helper()
+
+	var key crypto.Signer
+	var err error
+	switch keyType {
+	case "RSA":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ECDSA":
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "Ed25519":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		t.Fatalf("%sの秘密鍵の生成に失敗しました: %v", keyType, err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("%sの証明書の作成に失敗しました: %v", keyType, err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("%sの秘密鍵のエンコードに失敗しました: %v", keyType, err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("証明書の書き込みに失敗しました: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("秘密鍵の書き込みに失敗しました: %v", err)
+	}
+	return certFile, keyFile
+}
+
+// startProfileServer TLSプロファイルと鍵の種類を指定してwebhookと同じTLS設定のサーバーを起動
+func startProfileServer(t *testing.T, cfg *config.WebhookConfig, keyType string) string {
+	t.Helper()
+
+	certFile, keyFile := generateKeyPairFiles(t, keyType)
+	manager := cert.NewManager(certFile, keyFile, "")
+	if _, err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("証明書の読み込みに失敗しました: %v", err)
+	}
+
+	tlsConfig, err := webhook.NewTLSConfig(cfg, manager)
+	if err != nil {
+		t.Fatalf("TLS設定の作成に失敗しました: %v", err)
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatalf("TLSリスナーの作成に失敗しました: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// dialProfileServer クライアントの設定でハンドシェイクし、接続状態を返す
+func dialProfileServer(addr string, client *tls.Config) (*tls.ConnectionState, error) {
+	client.InsecureSkipVerify = true // 鍵の種類と暗号スイートの確認のため、証明書の検証は行わない
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", addr, client)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	state := conn.ConnectionState()
+	return &state, nil
+}
+
+// TestTLSProfiles TLSプロファイルごとのハンドシェイクのセキュリティテスト
+func TestTLSProfiles(t *testing.T) {
+	keyTypes := []string{"RSA", "ECDSA", "Ed25519"}
+
+	t.Run("intermediateプロファイル", func(t *testing.T) {
+		cfg := &config.WebhookConfig{TLSProfile: config.TLSProfileIntermediate}
+		for _, keyType := range keyTypes {
+			t.Run(keyType, func(t *testing.T) {
+				addr := startProfileServer(t, cfg, keyType)
+
+				// TLS 1.2のクライアントも前方秘匿性のあるAEAD暗号スイートで接続できる
+				state, err := dialProfileServer(addr, &tls.Config{MaxVersion: tls.VersionTLS12})
+				if err != nil {
+					t.Fatalf("TLS 1.2のハンドシェイクに失敗しました: %v", err)
+				}
+				if state.Version != tls.VersionTLS12 {
+					t.Errorf("TLS 1.2が期待されましたが、%x でした", state.Version)
+				}
+				t.Logf("%s: %s", keyType, tls.CipherSuiteName(state.CipherSuite))
+
+				if state, err := dialProfileServer(addr, &tls.Config{}); err != nil || state.Version != tls.VersionTLS13 {
+					t.Errorf("TLS 1.3のハンドシェイクに失敗しました: %v", err)
+				}
+
+				// TLS 1.1以下は拒否する
+				if _, err := dialProfileServer(addr, &tls.Config{MinVersion: tls.VersionTLS10, MaxVersion: tls.VersionTLS11}); err == nil {
+					t.Error("TLS 1.1での接続が許可されています")
+				}
+
+				// CBCモードの暗号スイートのみのクライアントは拒否する
+				if _, err := dialProfileServer(addr, &tls.Config{
+					MaxVersion: tls.VersionTLS12,
+					CipherSuites: []uint16{
+						tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+						tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+					},
+				}); err == nil {
+					t.Error("CBCモードの暗号スイートでの接続が許可されています")
+				}
+			})
+		}
+	})
+
+	t.Run("modernプロファイル", func(t *testing.T) {
+		cfg := &config.WebhookConfig{TLSProfile: config.TLSProfileModern}
+		for _, keyType := range keyTypes {
+			t.Run(keyType, func(t *testing.T) {
+				addr := startProfileServer(t, cfg, keyType)
+
+				state, err := dialProfileServer(addr, &tls.Config{})
+				if err != nil {
+					t.Fatalf("TLS 1.3のハンドシェイクに失敗しました: %v", err)
+				}
+				if state.Version != tls.VersionTLS13 {
+					t.Errorf("TLS 1.3が期待されましたが、%x でした", state.Version)
+				}
+
+				if _, err := dialProfileServer(addr, &tls.Config{MaxVersion: tls.VersionTLS12}); err == nil {
+					t.Error("modernプロファイルでTLS 1.2での接続が許可されています")
+				}
+			})
+		}
+	})
+
+	t.Run("customプロファイル", func(t *testing.T) {
+		cfg := &config.WebhookConfig{
+			TLSProfile:          config.TLSProfileCustom,
+			TLSMinVersion:       "1.2",
+			TLSCipherSuites:     []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+			TLSCurvePreferences: []string{"P384"},
+		}
+
+		addr := startProfileServer(t, cfg, "ECDSA")
+		state, err := dialProfileServer(addr, &tls.Config{MaxVersion: tls.VersionTLS12})
+		if err != nil {
+			t.Fatalf("TLS 1.2のハンドシェイクに失敗しました: %v", err)
+		}
+		if state.CipherSuite != tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384 {
+			t.Errorf("指定した暗号スイートが使用されていません: %s", tls.CipherSuiteName(state.CipherSuite))
+		}
+
+		// 指定した楕円曲線に対応しないクライアントは拒否する
+		if _, err := dialProfileServer(addr, &tls.Config{MaxVersion: tls.VersionTLS12, CurvePreferences: []tls.CurveID{tls.X25519}}); err == nil {
+			t.Error("指定していない楕円曲線での接続が許可されています")
+		}
+
+		// RSAの証明書はECDSA用の暗号スイートのみではTLS 1.2で接続できない
+		rsaAddr := startProfileServer(t, cfg, "RSA")
+		if _, err := dialProfileServer(rsaAddr, &tls.Config{MaxVersion: tls.VersionTLS12}); err == nil {
+			t.Error("RSAの証明書でECDSA用の暗号スイートのみの接続が成功しています")
+		}
+	})
+
+	t.Run("無効なプロファイル", func(t *testing.T) {
+		certFile, keyFile := generateKeyPairFiles(t, "ECDSA")
+		manager := cert.NewManager(certFile, keyFile, "")
+		if _, err := webhook.NewTLSConfig(&config.WebhookConfig{TLSProfile: config.TLSProfileCustom, TLSCipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}, manager); err == nil {
+			t.Error("安全でない暗号スイートを指定したプロファイルが許可されています")
+		}
+	})
+}